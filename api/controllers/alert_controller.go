@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AlertController handles attendance alert rule and alert HTTP requests
+type AlertController struct {
+	alertService services.AlertService
+}
+
+// NewAlertController creates a new instance of AlertController
+func NewAlertController(alertService services.AlertService) *AlertController {
+	return &AlertController{
+		alertService: alertService,
+	}
+}
+
+// GetRules retrieves all attendance alert rules
+func (c *AlertController) GetRules(ctx *gin.Context) {
+	rules, err := c.alertService.GetRules(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+// CreateRule creates a new attendance alert rule
+func (c *AlertController) CreateRule(ctx *gin.Context) {
+	// Parse request body
+	var rule models.AttendanceAlertRule
+	if err := ctx.ShouldBindJSON(&rule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.alertService.CreateRule(ctx, &rule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule updates an attendance alert rule
+func (c *AlertController) UpdateRule(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var rule models.AttendanceAlertRule
+	if err := ctx.ShouldBindJSON(&rule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	rule.ID = id
+
+	if err := c.alertService.UpdateRule(ctx, &rule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule deletes an attendance alert rule
+func (c *AlertController) DeleteRule(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.alertService.DeleteRule(ctx, id); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "alert rule not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "alert rule deleted successfully"})
+}
+
+// GetAlerts retrieves attendance alerts, optionally filtered by counselor_id, student_id and status.
+// Counselors only see the alerts of the students they counsel.
+func (c *AlertController) GetAlerts(ctx *gin.Context) {
+	filter := repositories.AlertFilter{Status: models.AlertStatus(ctx.Query("status"))}
+	if value := ctx.Query("counselor_id"); value != "" {
+		counselorID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid counselor ID"})
+			return
+		}
+		filter.CounselorID = &counselorID
+	}
+	if value := ctx.Query("student_id"); value != "" {
+		studentID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+			return
+		}
+		filter.StudentID = &studentID
+	}
+	if currentUserRole(ctx) == "Counselor" {
+		userID := currentUserID(ctx)
+		filter.CounselorID = &userID
+	}
+
+	alerts, err := c.alertService.GetAlerts(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alerts)
+}
+
+// GetAlert retrieves an attendance alert by ID
+func (c *AlertController) GetAlert(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	alert, err := c.alertService.GetAlertByID(ctx, id)
+	if err != nil || !c.canSee(ctx, alert) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alert)
+}
+
+// AcknowledgeAlert marks an open attendance alert as picked up, with optional notes
+func (c *AlertController) AcknowledgeAlert(ctx *gin.Context) {
+	c.updateAlert(ctx, c.alertService.AcknowledgeAlert)
+}
+
+// ResolveAlert closes an attendance alert, with optional notes
+func (c *AlertController) ResolveAlert(ctx *gin.Context) {
+	c.updateAlert(ctx, c.alertService.ResolveAlert)
+}
+
+// updateAlert applies a status change to the alert named in the URL on behalf of the current user
+func (c *AlertController) updateAlert(ctx *gin.Context, update func(ctx context.Context, id, userID uuid.UUID, notes string) (*models.AttendanceAlert, error)) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Notes string `json:"notes"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	alert, err := c.alertService.GetAlertByID(ctx, id)
+	if err != nil || !c.canSee(ctx, alert) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+		return
+	}
+
+	alert, err = update(ctx, id, currentUserID(ctx), req.Notes)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alert)
+}
+
+// canSee reports whether the current user may see an alert: counselors only see their own students'
+func (c *AlertController) canSee(ctx *gin.Context, alert *models.AttendanceAlert) bool {
+	if currentUserRole(ctx) != "Counselor" {
+		return true
+	}
+	return alert.Student != nil && alert.Student.CounselorID != nil && *alert.Student.CounselorID == currentUserID(ctx)
+}
+
+// EvaluateAlerts checks the alert rules against every student with recent attendance
+func (c *AlertController) EvaluateAlerts(ctx *gin.Context) {
+	alerts, err := c.alertService.EvaluateAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"raised": len(alerts), "alerts": alerts})
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AnalyticsController handles attendance analytics HTTP requests
+type AnalyticsController struct {
+	analyticsService services.AnalyticsService
+}
+
+// NewAnalyticsController creates a new instance of AnalyticsController
+func NewAnalyticsController(analyticsService services.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetAttendanceAnalytics breaks down attendance over a from/to date range, optionally filtered by
+// course_id, teacher_id and grade_level
+func (c *AnalyticsController) GetAttendanceAnalytics(ctx *gin.Context) {
+	filter := models.AttendanceAnalyticsFilter{
+		From:       ctx.Query("from"),
+		To:         ctx.Query("to"),
+		GradeLevel: ctx.Query("grade_level"),
+	}
+	if value := ctx.Query("course_id"); value != "" {
+		courseID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+			return
+		}
+		filter.CourseID = &courseID
+	}
+	if value := ctx.Query("teacher_id"); value != "" {
+		teacherID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid teacher ID"})
+			return
+		}
+		filter.TeacherID = &teacherID
+	}
+
+	analytics, err := c.analyticsService.GetAttendanceAnalytics(ctx, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnalyticsQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, analytics)
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AssessmentController handles assessment and gradebook HTTP requests
+type AssessmentController struct {
+	assessmentService services.AssessmentService
+}
+
+// NewAssessmentController creates a new instance of AssessmentController
+func NewAssessmentController(assessmentService services.AssessmentService) *AssessmentController {
+	return &AssessmentController{
+		assessmentService: assessmentService,
+	}
+}
+
+// CreateAssessment creates a new assessment
+func (c *AssessmentController) CreateAssessment(ctx *gin.Context) {
+	// Parse request body
+	var assessment models.Assessment
+	if err := ctx.ShouldBindJSON(&assessment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assessment.CreatedBy = currentUserID(ctx)
+
+	// Create assessment
+	if err := c.assessmentService.CreateAssessment(authorized(ctx), &assessment); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, assessment)
+}
+
+// GetAssessment retrieves an assessment by ID
+func (c *AssessmentController) GetAssessment(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	assessment, err := c.assessmentService.GetAssessmentByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "assessment not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assessment)
+}
+
+// UpdateAssessment updates an assessment
+func (c *AssessmentController) UpdateAssessment(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var assessment models.Assessment
+	if err := ctx.ShouldBindJSON(&assessment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	assessment.ID = id
+
+	if err := c.assessmentService.UpdateAssessment(authorized(ctx), &assessment, currentUserID(ctx)); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assessment)
+}
+
+// DeleteAssessment deletes an assessment
+func (c *AssessmentController) DeleteAssessment(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.assessmentService.DeleteAssessment(authorized(ctx), id, currentUserID(ctx)); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "assessment deleted successfully"})
+}
+
+// RecordScores saves scores for an assessment
+func (c *AssessmentController) RecordScores(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Scores []models.AssessmentScore `json:"scores" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.assessmentService.RecordScores(authorized(ctx), id, req.Scores, currentUserID(ctx)); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, req.Scores)
+}
+
+// GetCourseAssessments retrieves the assessments of a course
+func (c *AssessmentController) GetCourseAssessments(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	assessments, err := c.assessmentService.GetCourseAssessments(ctx, courseID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assessments)
+}
+
+// GetGradebook retrieves the student x assessment matrix of a course
+func (c *AssessmentController) GetGradebook(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	gradebook, err := c.assessmentService.GetGradebook(authorized(ctx), courseID, ctx.Query("term"))
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gradebook)
+}
+
+// GetWeights retrieves the category weights of a course
+func (c *AssessmentController) GetWeights(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	weights, err := c.assessmentService.GetWeights(ctx, courseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, weights)
+}
+
+// SetWeights replaces the category weights of a course
+func (c *AssessmentController) SetWeights(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Weights []models.AssessmentWeight `json:"weights"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.assessmentService.SetWeights(authorized(ctx), courseID, req.Weights, currentUserID(ctx)); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, req.Weights)
+}
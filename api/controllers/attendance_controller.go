@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"school-management-api/internal/authz"
 	"school-management-api/internal/models"
 	"school-management-api/internal/services"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,12 +37,26 @@ func (c *AttendanceController) CreateAttendance(ctx *gin.Context) {
 		attendance.CreatedBy = userID.(uuid.UUID)
 	}
 
-	if err := c.attendanceService.CreateAttendance(&attendance); err != nil {
+	// Marking the same student, course, date and period again updates the existing record
+	created, err := c.attendanceService.CreateAttendance(authorized(ctx), &attendance, currentUserID(ctx))
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidAttendance) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, attendance)
+	if created {
+		ctx.JSON(http.StatusCreated, attendance)
+		return
+	}
+	ctx.JSON(http.StatusOK, attendance)
 }
 
 // UpdateAttendance updates an attendance record
@@ -52,8 +69,12 @@ func (c *AttendanceController) UpdateAttendance(ctx *gin.Context) {
 	}
 
 	// Check if attendance exists
-	existingAttendance, err := c.attendanceService.GetAttendanceByID(id)
+	existingAttendance, err := c.attendanceService.GetAttendanceByID(authorized(ctx), id)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Attendance record not found"})
 		return
 	}
@@ -77,7 +98,15 @@ func (c *AttendanceController) UpdateAttendance(ctx *gin.Context) {
 	attendance.CreatedBy = existingAttendance.CreatedBy
 	attendance.CreatedAt = existingAttendance.CreatedAt
 
-	if err := c.attendanceService.UpdateAttendance(&attendance); err != nil {
+	if err := c.attendanceService.UpdateAttendance(authorized(ctx), &attendance); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidAttendance) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -94,7 +123,11 @@ func (c *AttendanceController) DeleteAttendance(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.attendanceService.DeleteAttendance(id); err != nil {
+	if err := c.attendanceService.DeleteAttendance(authorized(ctx), id); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -111,8 +144,12 @@ func (c *AttendanceController) GetAttendance(ctx *gin.Context) {
 		return
 	}
 
-	attendance, err := c.attendanceService.GetAttendanceByID(id)
+	attendance, err := c.attendanceService.GetAttendanceByID(authorized(ctx), id)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Attendance record not found"})
 		return
 	}
@@ -122,8 +159,16 @@ func (c *AttendanceController) GetAttendance(ctx *gin.Context) {
 
 // GetAllAttendances gets all attendance records
 func (c *AttendanceController) GetAllAttendances(ctx *gin.Context) {
-	attendances, err := c.attendanceService.GetAllAttendances()
+	attendances, err := c.attendanceService.GetAllAttendances(authorized(ctx), ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -140,8 +185,16 @@ func (c *AttendanceController) GetAttendancesByStudent(ctx *gin.Context) {
 		return
 	}
 
-	attendances, err := c.attendanceService.GetAttendancesByStudent(studentID)
+	attendances, err := c.attendanceService.GetAttendancesByStudent(authorized(ctx), studentID, ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -158,8 +211,16 @@ func (c *AttendanceController) GetAttendancesByCourse(ctx *gin.Context) {
 		return
 	}
 
-	attendances, err := c.attendanceService.GetAttendancesByCourse(courseID)
+	attendances, err := c.attendanceService.GetAttendancesByCourse(authorized(ctx), courseID, ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -176,8 +237,12 @@ func (c *AttendanceController) GetAttendancesByDate(ctx *gin.Context) {
 		return
 	}
 
-	attendances, err := c.attendanceService.GetAttendancesByDate(date)
+	attendances, err := c.attendanceService.GetAttendancesByDate(authorized(ctx), date)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -194,8 +259,12 @@ func (c *AttendanceController) GetStudentAttendanceReport(ctx *gin.Context) {
 		return
 	}
 
-	report, err := c.attendanceService.GetStudentAttendanceReport(studentID)
+	report, err := c.attendanceService.GetStudentAttendanceReport(authorized(ctx), studentID)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -212,8 +281,127 @@ func (c *AttendanceController) GetCourseAttendanceReport(ctx *gin.Context) {
 		return
 	}
 
-	report, err := c.attendanceService.GetCourseAttendanceReport(courseID)
+	report, err := c.attendanceService.GetCourseAttendanceReport(authorized(ctx), courseID)
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// GetStudentAttendanceRates gets a student's attendance rates against the sessions the school calendar held
+func (c *AttendanceController) GetStudentAttendanceRates(ctx *gin.Context) {
+	studentIDStr := ctx.Param("studentId")
+	studentID, err := uuid.Parse(studentIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	rates, err := c.attendanceService.GetStudentAttendanceRates(authorized(ctx), studentID, ctx.Query("term"))
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+// GetCourseAttendanceRates gets the attendance rates of a course's students against the sessions the school calendar held
+func (c *AttendanceController) GetCourseAttendanceRates(ctx *gin.Context) {
+	courseIDStr := ctx.Param("courseId")
+	courseID, err := uuid.Parse(courseIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	rates, err := c.attendanceService.GetCourseAttendanceRates(authorized(ctx), courseID, ctx.Query("term"))
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+// GetMissingAttendance lists the sessions of a teacher's sections that had no attendance taken
+func (c *AttendanceController) GetMissingAttendance(ctx *gin.Context) {
+	teacherIDStr := ctx.Param("teacherId")
+	teacherID, err := uuid.Parse(teacherIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+
+	from, err := parseOptionalDate(ctx.Query("from"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format. Use YYYY-MM-DD"})
+		return
+	}
+	to, err := parseOptionalDate(ctx.Query("to"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	sessions, err := c.attendanceService.GetMissingAttendance(authorized(ctx), teacherID, ctx.Query("term"), from, to)
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) || errors.Is(err, services.ErrInvalidAttendance) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// GetStudentMinutesReport gets the instructional minutes a student missed in each course
+func (c *AttendanceController) GetStudentMinutesReport(ctx *gin.Context) {
+	studentIDStr := ctx.Param("studentId")
+	studentID, err := uuid.Parse(studentIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	report, err := c.attendanceService.GetStudentMinutesReport(authorized(ctx), studentID, ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -221,6 +409,32 @@ func (c *AttendanceController) GetCourseAttendanceReport(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, report)
 }
 
+// GetCourseMinutesReport gets the instructional minutes each student of a course missed
+func (c *AttendanceController) GetCourseMinutesReport(ctx *gin.Context) {
+	courseIDStr := ctx.Param("courseId")
+	courseID, err := uuid.Parse(courseIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	report, err := c.attendanceService.GetCourseMinutesReport(authorized(ctx), courseID, ctx.Query("term"))
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
 // GetAttendancesByCourseAndDate gets attendance records by course ID and date
 func (c *AttendanceController) GetAttendancesByCourseAndDate(ctx *gin.Context) {
 	courseIDStr := ctx.Param("courseId")
@@ -237,11 +451,102 @@ func (c *AttendanceController) GetAttendancesByCourseAndDate(ctx *gin.Context) {
 		return
 	}
 
-	attendances, err := c.attendanceService.GetAttendancesByCourseAndDate(courseID, date)
+	attendances, err := c.attendanceService.GetAttendancesByCourseAndDate(authorized(ctx), courseID, date)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, attendances)
 }
+
+// GetRollCall gets a course's roster for a date with any attendance already recorded, to prefill a roll call
+func (c *AttendanceController) GetRollCall(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", ctx.Param("date"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	period, err := rollCallPeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period"})
+		return
+	}
+
+	rollCall, err := c.attendanceService.GetRollCall(authorized(ctx), courseID, date, period)
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rollCall)
+}
+
+// TakeRollCall records the attendance of a whole course for a date in one request
+func (c *AttendanceController) TakeRollCall(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", ctx.Param("date"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	period, err := rollCallPeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period"})
+		return
+	}
+
+	var req struct {
+		Marks []models.RollCallMark `json:"marks" binding:"dive"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rollCall, err := c.attendanceService.TakeRollCall(authorized(ctx), courseID, date, period, req.Marks, currentUserID(ctx))
+	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rollCall)
+}
+
+// rollCallPeriod reads the optional period query parameter of a roll call, 0 meaning the whole day
+func rollCallPeriod(ctx *gin.Context) (int, error) {
+	value := ctx.Query("period")
+	if value == "" {
+		return 0, nil
+	}
+	period, err := strconv.Atoi(value)
+	if err != nil || period < 0 {
+		return 0, errors.New("invalid period")
+	}
+	return period, nil
+}
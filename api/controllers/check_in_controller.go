@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+// defaultQRCodeSize is the width and height in pixels of check-in QR codes when no size is asked for
+const defaultQRCodeSize = 512
+
+// CheckInController handles self-service attendance check-in HTTP requests
+type CheckInController struct {
+	checkInService services.CheckInService
+}
+
+// NewCheckInController creates a new instance of CheckInController
+func NewCheckInController(checkInService services.CheckInService) *CheckInController {
+	return &CheckInController{
+		checkInService: checkInService,
+	}
+}
+
+// IssueSession issues a check-in code for today's occurrence of a section meeting, valid for an
+// optional ttl_seconds
+func (c *CheckInController) IssueSession(ctx *gin.Context) {
+	// Parse request body
+	var req struct {
+		MeetingID  uuid.UUID `json:"meeting_id" binding:"required"`
+		TTLSeconds int       `json:"ttl_seconds"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := c.checkInService.IssueSession(authorized(ctx), req.MeetingID, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		ctx.JSON(checkInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, session)
+}
+
+// GetQRCode issues a check-in code for today's occurrence of a section meeting and renders it as a PNG
+// QR code, with optional ttl_seconds and size query parameters. Displays refresh it to rotate the code.
+func (c *CheckInController) GetQRCode(ctx *gin.Context) {
+	// Parse ID
+	meetingID, err := uuid.Parse(ctx.Param("meetingId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid meeting ID"})
+		return
+	}
+
+	ttlSeconds, err := strconv.Atoi(ctx.DefaultQuery("ttl_seconds", "0"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ttl_seconds"})
+		return
+	}
+	size, err := strconv.Atoi(ctx.DefaultQuery("size", strconv.Itoa(defaultQRCodeSize)))
+	if err != nil || size < 128 || size > 2048 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "size must be between 128 and 2048 pixels"})
+		return
+	}
+
+	session, err := c.checkInService.IssueSession(authorized(ctx), meetingID, time.Duration(ttlSeconds)*time.Second)
+	if err != nil {
+		ctx.JSON(checkInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	png, err := qrcode.Encode(session.Token, qrcode.Medium, size)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Check-In-Expires-At", session.ExpiresAt.Format(time.RFC3339))
+	ctx.Data(http.StatusOK, "image/png", png)
+}
+
+// CheckIn records the signed-in student's attendance from a scanned check-in code
+func (c *CheckInController) CheckIn(ctx *gin.Context) {
+	// Parse request body
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studentID := currentStudentID(ctx)
+	if studentID == nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "this login is not linked to a student"})
+		return
+	}
+
+	result, err := c.checkInService.CheckIn(ctx, req.Token, *studentID, currentUserID(ctx), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(checkInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// checkInErrorStatus maps a check-in service error to an HTTP status
+func checkInErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, authz.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAlreadyCheckedIn):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidCheckIn), errors.Is(err, services.ErrInvalidAttendance):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
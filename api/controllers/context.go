@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"school-management-api/internal/authz"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentUserID returns the authenticated user's ID, or uuid.Nil when unauthenticated
+func currentUserID(ctx *gin.Context) uuid.UUID {
+	if userID, exists := ctx.Get("userID"); exists {
+		return userID.(uuid.UUID)
+	}
+	return uuid.Nil
+}
+
+// currentUserRole returns the authenticated user's role, or an empty string when unauthenticated
+func currentUserRole(ctx *gin.Context) string {
+	role, _ := ctx.Get("role")
+	value, _ := role.(string)
+	return value
+}
+
+// currentStudentID returns the ID of the student the authenticated user is the login of, or nil
+func currentStudentID(ctx *gin.Context) *uuid.UUID {
+	if studentID, exists := ctx.Get("studentID"); exists {
+		id := studentID.(uuid.UUID)
+		return &id
+	}
+	return nil
+}
+
+// currentGuardianID returns the ID of the guardian the authenticated user is the login of, or nil
+func currentGuardianID(ctx *gin.Context) *uuid.UUID {
+	if guardianID, exists := ctx.Get("guardianID"); exists {
+		id := guardianID.(uuid.UUID)
+		return &id
+	}
+	return nil
+}
+
+// authorized returns a context carrying the authenticated user as the principal services limit the
+// records they read and change to
+func authorized(ctx *gin.Context) context.Context {
+	principal := &authz.Principal{
+		UserID:     currentUserID(ctx),
+		Role:       currentUserRole(ctx),
+		StudentID:  currentStudentID(ctx),
+		GuardianID: currentGuardianID(ctx),
+	}
+	if teacherID, exists := ctx.Get("teacherID"); exists {
+		id := teacherID.(uuid.UUID)
+		principal.TeacherID = &id
+	}
+	return authz.WithPrincipal(ctx, principal)
+}
+
+// parseOptionalDate parses a YYYY-MM-DD query value, returning nil when it is empty
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
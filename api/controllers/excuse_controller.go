@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExcuseController handles absence excuse request HTTP requests
+type ExcuseController struct {
+	excuseService services.ExcuseService
+}
+
+// NewExcuseController creates a new instance of ExcuseController
+func NewExcuseController(excuseService services.ExcuseService) *ExcuseController {
+	return &ExcuseController{
+		excuseService: excuseService,
+	}
+}
+
+// SubmitExcuse submits an excuse request for a student's absences. It accepts JSON, or a multipart form
+// with the supporting document in the "document" field.
+func (c *ExcuseController) SubmitExcuse(ctx *gin.Context) {
+	// Parse request body
+	var req struct {
+		StudentID string `json:"student_id" form:"student_id" binding:"required"`
+		FromDate  string `json:"from_date" form:"from_date" binding:"required"`
+		ToDate    string `json:"to_date" form:"to_date"`
+		Reason    string `json:"reason" form:"reason" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studentID, err := uuid.Parse(req.StudentID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+	fromDate, err := parseOptionalDate(req.FromDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date, expected YYYY-MM-DD"})
+		return
+	}
+	toDate, err := parseOptionalDate(req.ToDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date, expected YYYY-MM-DD"})
+		return
+	}
+
+	request := models.ExcuseRequest{
+		StudentID:   studentID,
+		SubmittedBy: currentUserID(ctx),
+		FromDate:    *fromDate,
+		Reason:      req.Reason,
+	}
+	if toDate != nil {
+		request.ToDate = *toDate
+	}
+
+	// Open the uploaded document, if any
+	var document *services.ExcuseDocument
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		header, err := ctx.FormFile("document")
+		if err != nil && !errors.Is(err, http.ErrMissingFile) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if header != nil {
+			if header.Size > services.MaxExcuseDocumentSize {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "document is too large"})
+				return
+			}
+			file, err := header.Open()
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			defer file.Close()
+			document = &services.ExcuseDocument{Name: header.Filename, Content: file}
+		}
+	}
+
+	if err := c.excuseService.SubmitExcuse(authorized(ctx), &request, document); err != nil {
+		if errors.Is(err, services.ErrInvalidExcuse) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, request)
+}
+
+// GetExcuses retrieves excuse requests, optionally filtered by student_id and status.
+// Guardians only see the requests they submitted.
+func (c *ExcuseController) GetExcuses(ctx *gin.Context) {
+	filter := repositories.ExcuseFilter{Status: models.ExcuseStatus(ctx.Query("status"))}
+	if value := ctx.Query("student_id"); value != "" {
+		studentID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+			return
+		}
+		filter.StudentID = &studentID
+	}
+	if currentUserRole(ctx) == "Guardian" {
+		userID := currentUserID(ctx)
+		filter.SubmittedBy = &userID
+	}
+
+	requests, err := c.excuseService.GetExcuses(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+// GetExcuse retrieves an excuse request by ID
+func (c *ExcuseController) GetExcuse(ctx *gin.Context) {
+	request, ok := c.findExcuse(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// GetDocument downloads the supporting document of an excuse request
+func (c *ExcuseController) GetDocument(ctx *gin.Context) {
+	request, ok := c.findExcuse(ctx)
+	if !ok {
+		return
+	}
+
+	path, err := c.excuseService.DocumentPath(request)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", request.DocumentType)
+	ctx.FileAttachment(path, request.DocumentName)
+}
+
+// ApproveExcuse approves a pending excuse request, with optional notes, excusing the absences it covers
+func (c *ExcuseController) ApproveExcuse(ctx *gin.Context) {
+	c.reviewExcuse(ctx, c.excuseService.ApproveExcuse)
+}
+
+// RejectExcuse rejects a pending excuse request, with optional notes
+func (c *ExcuseController) RejectExcuse(ctx *gin.Context) {
+	c.reviewExcuse(ctx, c.excuseService.RejectExcuse)
+}
+
+// reviewExcuse applies a review decision to the excuse request named in the URL on behalf of the current user
+func (c *ExcuseController) reviewExcuse(ctx *gin.Context, review func(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*models.ExcuseRequest, error)) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Notes string `json:"notes"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if _, err := c.excuseService.GetExcuseByID(ctx, id); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "excuse request not found"})
+		return
+	}
+
+	request, err := review(ctx, id, currentUserID(ctx), req.Notes)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// findExcuse loads the excuse request named in the URL, writing an error response when it is missing
+// or, for guardians, was submitted by someone else
+func (c *ExcuseController) findExcuse(ctx *gin.Context) (*models.ExcuseRequest, bool) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return nil, false
+	}
+
+	request, err := c.excuseService.GetExcuseByID(ctx, id)
+	if err != nil || (currentUserRole(ctx) == "Guardian" && request.SubmittedBy != currentUserID(ctx)) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "excuse request not found"})
+		return nil, false
+	}
+	return request, true
+}
@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"school-management-api/internal/authz"
 	"school-management-api/internal/models"
 	"school-management-api/internal/services"
 
@@ -12,11 +14,12 @@ import (
 // GradeController handles grade-related HTTP requests
 type GradeController struct {
 	gradeService services.GradeService
+	gpaService   services.GPAService
 }
 
 // NewGradeController creates a new GradeController
-func NewGradeController(gradeService services.GradeService) *GradeController {
-	return &GradeController{gradeService: gradeService}
+func NewGradeController(gradeService services.GradeService, gpaService services.GPAService) *GradeController {
+	return &GradeController{gradeService: gradeService, gpaService: gpaService}
 }
 
 // CreateGrade creates a new grade
@@ -33,7 +36,15 @@ func (c *GradeController) CreateGrade(ctx *gin.Context) {
 		grade.CreatedBy = userID.(uuid.UUID)
 	}
 
-	if err := c.gradeService.CreateGrade(&grade); err != nil {
+	if err := c.gradeService.CreateGrade(authorized(ctx), &grade); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -52,8 +63,12 @@ func (c *GradeController) UpdateGrade(ctx *gin.Context) {
 	}
 
 	// Check if grade exists
-	existingGrade, err := c.gradeService.GetGradeByID(id)
+	existingGrade, err := c.gradeService.GetGradeByID(authorized(ctx), id)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Grade not found"})
 		return
 	}
@@ -77,7 +92,15 @@ func (c *GradeController) UpdateGrade(ctx *gin.Context) {
 	grade.CreatedBy = existingGrade.CreatedBy
 	grade.CreatedAt = existingGrade.CreatedAt
 
-	if err := c.gradeService.UpdateGrade(&grade); err != nil {
+	if err := c.gradeService.UpdateGrade(authorized(ctx), &grade); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -95,7 +118,11 @@ func (c *GradeController) DeleteGrade(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.gradeService.DeleteGrade(id); err != nil {
+	if err := c.gradeService.DeleteGrade(authorized(ctx), id); err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -113,8 +140,12 @@ func (c *GradeController) GetGrade(ctx *gin.Context) {
 		return
 	}
 
-	grade, err := c.gradeService.GetGradeByID(id)
+	grade, err := c.gradeService.GetGradeByID(authorized(ctx), id)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Grade not found"})
 		return
 	}
@@ -124,8 +155,16 @@ func (c *GradeController) GetGrade(ctx *gin.Context) {
 
 // GetAllGrades gets all grades
 func (c *GradeController) GetAllGrades(ctx *gin.Context) {
-	grades, err := c.gradeService.GetAllGrades()
+	grades, err := c.gradeService.GetAllGrades(authorized(ctx), ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -143,8 +182,16 @@ func (c *GradeController) GetGradesByStudent(ctx *gin.Context) {
 		return
 	}
 
-	grades, err := c.gradeService.GetGradesByStudent(studentID)
+	grades, err := c.gradeService.GetGradesByStudent(authorized(ctx), studentID, ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -162,8 +209,16 @@ func (c *GradeController) GetGradesByCourse(ctx *gin.Context) {
 		return
 	}
 
-	grades, err := c.gradeService.GetGradesByCourse(courseID)
+	grades, err := c.gradeService.GetGradesByCourse(authorized(ctx), courseID, ctx.Query("term"))
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTerm) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -181,13 +236,28 @@ func (c *GradeController) GetStudentGPA(ctx *gin.Context) {
 		return
 	}
 
-	gpa, err := c.gradeService.GetStudentGPA(studentID)
+	query := models.GPAQuery{
+		Scope:        models.GPAScope(ctx.Query("scope")),
+		Term:         ctx.Query("term"),
+		Department:   ctx.Query("department"),
+		RetakePolicy: models.RetakePolicy(ctx.Query("retake")),
+	}
+
+	result, err := c.gpaService.GetStudentGPA(authorized(ctx), studentID, query)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidGPAQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"gpa": gpa})
+	ctx.JSON(http.StatusOK, result)
 }
 
 // GetCourseGradeDistribution gets the distribution of grades for a course
@@ -200,8 +270,12 @@ func (c *GradeController) GetCourseGradeDistribution(ctx *gin.Context) {
 		return
 	}
 
-	distribution, err := c.gradeService.GetCourseGradeDistribution(courseID)
+	distribution, err := c.gradeService.GetCourseGradeDistribution(authorized(ctx), courseID)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GradingScaleController handles grading scale HTTP requests
+type GradingScaleController struct {
+	scaleService services.GradingScaleService
+}
+
+// NewGradingScaleController creates a new instance of GradingScaleController
+func NewGradingScaleController(scaleService services.GradingScaleService) *GradingScaleController {
+	return &GradingScaleController{
+		scaleService: scaleService,
+	}
+}
+
+// GetScales retrieves all grading scales
+func (c *GradingScaleController) GetScales(ctx *gin.Context) {
+	scales, err := c.scaleService.GetAllScales(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scales)
+}
+
+// GetScale retrieves a grading scale by ID
+func (c *GradingScaleController) GetScale(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	scale, err := c.scaleService.GetScaleByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "grading scale not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scale)
+}
+
+// CreateScale creates a new grading scale
+func (c *GradingScaleController) CreateScale(ctx *gin.Context) {
+	// Parse request body
+	var scale models.GradingScale
+	if err := ctx.ShouldBindJSON(&scale); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create scale
+	if err := c.scaleService.CreateScale(ctx, &scale); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, gin.H{"message": "grading scale created successfully", "id": scale.ID})
+}
+
+// UpdateScale updates a grading scale
+func (c *GradingScaleController) UpdateScale(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var scale models.GradingScale
+	if err := ctx.ShouldBindJSON(&scale); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	scale.ID = id
+
+	// Update scale
+	if err := c.scaleService.UpdateScale(ctx, &scale); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "grading scale updated successfully"})
+}
+
+// DeleteScale deletes a grading scale
+func (c *GradingScaleController) DeleteScale(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.scaleService.DeleteScale(ctx, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "grading scale deleted successfully"})
+}
+
+// GetAssignments retrieves the courses, departments and terms a scale is assigned to
+func (c *GradingScaleController) GetAssignments(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	assignments, err := c.scaleService.GetScaleAssignments(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assignments)
+}
+
+// AssignScale assigns a grading scale to a course, department or term
+func (c *GradingScaleController) AssignScale(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var assignment models.GradingScaleAssignment
+	if err := ctx.ShouldBindJSON(&assignment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment.ScaleID = id
+	if err := c.scaleService.AssignScale(ctx, &assignment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, assignment)
+}
+
+// RemoveAssignment removes a grading scale assignment
+func (c *GradingScaleController) RemoveAssignment(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("assignmentId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid assignment ID"})
+		return
+	}
+
+	if err := c.scaleService.RemoveAssignment(ctx, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "grading scale assignment removed successfully"})
+}
+
+// ResolveScale returns the grading scale that applies to a course in a term
+func (c *GradingScaleController) ResolveScale(ctx *gin.Context) {
+	courseID, err := uuid.Parse(ctx.Query("course_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	scale, err := c.scaleService.ResolveCourseScale(ctx, courseID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scale)
+}
@@ -0,0 +1,389 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GuardianController handles guardian-related HTTP requests, including the portal guardians use to
+// follow their children
+type GuardianController struct {
+	guardianService   services.GuardianService
+	gradeService      services.GradeService
+	attendanceService services.AttendanceService
+	timetableService  services.TimetableService
+}
+
+// NewGuardianController creates a new instance of GuardianController
+func NewGuardianController(
+	guardianService services.GuardianService,
+	gradeService services.GradeService,
+	attendanceService services.AttendanceService,
+	timetableService services.TimetableService,
+) *GuardianController {
+	return &GuardianController{
+		guardianService:   guardianService,
+		gradeService:      gradeService,
+		attendanceService: attendanceService,
+		timetableService:  timetableService,
+	}
+}
+
+// GetGuardians retrieves all guardians with pagination
+func (c *GuardianController) GetGuardians(ctx *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+
+	// Get guardians from service
+	guardians, total, err := c.guardianService.GetAllGuardians(ctx, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": guardians,
+		"meta": gin.H{
+			"page":      page,
+			"pageSize":  pageSize,
+			"total":     total,
+			"totalPage": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// GetGuardian retrieves a guardian by ID, with the students they are linked to
+func (c *GuardianController) GetGuardian(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Get guardian from service
+	guardian, err := c.guardianService.GetGuardianByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "guardian not found"})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, guardian)
+}
+
+// CreateGuardian creates a new guardian
+func (c *GuardianController) CreateGuardian(ctx *gin.Context) {
+	// Parse request body
+	var guardian models.Guardian
+	if err := ctx.ShouldBindJSON(&guardian); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create guardian
+	account, err := c.guardianService.CreateGuardian(ctx, &guardian)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	response := gin.H{"message": "guardian created successfully", "id": guardian.ID}
+	if account != nil {
+		response["account"] = account
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ProvisionAccount provisions the login of an existing guardian
+func (c *GuardianController) ProvisionAccount(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var req models.AccountRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	account, err := c.guardianService.ProvisionAccount(ctx, id, req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, account)
+}
+
+// UpdateGuardian updates a guardian
+func (c *GuardianController) UpdateGuardian(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var guardian models.Guardian
+	if err := ctx.ShouldBindJSON(&guardian); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	guardian.ID = id
+
+	// Update guardian
+	if err := c.guardianService.UpdateGuardian(ctx, &guardian); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "guardian not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "guardian updated successfully"})
+}
+
+// DeleteGuardian deletes a guardian and their links to students
+func (c *GuardianController) DeleteGuardian(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Delete guardian
+	if err := c.guardianService.DeleteGuardian(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "guardian not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "guardian deleted successfully"})
+}
+
+// LinkStudent links a guardian to a student, or updates the relationship, custody and contact flags
+// of an existing link
+func (c *GuardianController) LinkStudent(ctx *gin.Context) {
+	// Parse IDs
+	guardianID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid guardian ID"})
+		return
+	}
+	studentID, err := uuid.Parse(ctx.Param("studentId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+
+	// Parse request body
+	var link models.StudentGuardian
+	if err := ctx.ShouldBindJSON(&link); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	link.GuardianID = guardianID
+	link.StudentID = studentID
+
+	if err := c.guardianService.LinkStudent(ctx, &link); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "guardian not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, link)
+}
+
+// UnlinkStudent removes the link between a guardian and a student
+func (c *GuardianController) UnlinkStudent(ctx *gin.Context) {
+	// Parse IDs
+	guardianID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid guardian ID"})
+		return
+	}
+	studentID, err := uuid.Parse(ctx.Param("studentId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+
+	if err := c.guardianService.UnlinkStudent(ctx, guardianID, studentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "guardian is not linked to this student"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "student unlinked from guardian successfully"})
+}
+
+// GetStudentGuardians retrieves the guardians of a student, primary contacts first
+func (c *GuardianController) GetStudentGuardians(ctx *gin.Context) {
+	// Parse ID
+	studentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+
+	guardians, err := c.guardianService.GetStudentGuardians(ctx, studentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "student not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, guardians)
+}
+
+// GetMyChildren retrieves the children of the authenticated guardian whose records they may see
+func (c *GuardianController) GetMyChildren(ctx *gin.Context) {
+	guardianID := currentGuardianID(ctx)
+	if guardianID == nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "your login is not linked to a guardian"})
+		return
+	}
+
+	children, err := c.guardianService.GetChildren(ctx, *guardianID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, children)
+}
+
+// GetMyChildGrades retrieves the grades of one of the authenticated guardian's children, optionally for a term
+func (c *GuardianController) GetMyChildGrades(ctx *gin.Context) {
+	studentID, ok := c.findChild(ctx)
+	if !ok {
+		return
+	}
+
+	grades, err := c.gradeService.GetGradesByStudent(authorized(ctx), studentID, ctx.Query("term"))
+	if err != nil {
+		c.writeRecordError(ctx, err)
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, grades)
+}
+
+// GetMyChildAttendance retrieves the attendance of one of the authenticated guardian's children,
+// optionally for a term, together with their attendance rates
+func (c *GuardianController) GetMyChildAttendance(ctx *gin.Context) {
+	studentID, ok := c.findChild(ctx)
+	if !ok {
+		return
+	}
+
+	records, err := c.attendanceService.GetAttendancesByStudent(authorized(ctx), studentID, ctx.Query("term"))
+	if err != nil {
+		c.writeRecordError(ctx, err)
+		return
+	}
+	rates, err := c.attendanceService.GetStudentAttendanceRates(authorized(ctx), studentID, ctx.Query("term"))
+	if err != nil {
+		c.writeRecordError(ctx, err)
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"records": records, "rates": rates})
+}
+
+// GetMyChildSchedule retrieves the weekly timetable of one of the authenticated guardian's children,
+// optionally for a term
+func (c *GuardianController) GetMyChildSchedule(ctx *gin.Context) {
+	studentID, ok := c.findChild(ctx)
+	if !ok {
+		return
+	}
+
+	timetable, err := c.timetableService.GetStudentTimetable(ctx, studentID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, timetable)
+}
+
+// findChild parses the student named in the URL and checks that they are a child of the authenticated
+// guardian whose records the guardian may see, writing an error response when not
+func (c *GuardianController) findChild(ctx *gin.Context) (uuid.UUID, bool) {
+	studentID, err := uuid.Parse(ctx.Param("studentId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return uuid.Nil, false
+	}
+
+	guardianID := currentGuardianID(ctx)
+	if guardianID == nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "your login is not linked to a guardian"})
+		return uuid.Nil, false
+	}
+	if _, err := c.guardianService.GetChild(ctx, *guardianID, studentID); err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	return studentID, true
+}
+
+// writeRecordError writes the response for an error reading a child's grades or attendance
+func (c *GuardianController) writeRecordError(ctx *gin.Context, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUnknownTerm) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
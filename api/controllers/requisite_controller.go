@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequisiteController handles course prerequisite and corequisite HTTP requests
+type RequisiteController struct {
+	requisiteService services.RequisiteService
+}
+
+// NewRequisiteController creates a new instance of RequisiteController
+func NewRequisiteController(requisiteService services.RequisiteService) *RequisiteController {
+	return &RequisiteController{
+		requisiteService: requisiteService,
+	}
+}
+
+// GetRules retrieves the prerequisite and corequisite rule tree of a course
+func (c *RequisiteController) GetRules(ctx *gin.Context) {
+	// Parse ID
+	courseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	groups, err := c.requisiteService.GetRules(ctx, courseID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"course_id": courseID, "groups": groups})
+}
+
+// SetRules replaces the prerequisite and corequisite rule tree of a course
+func (c *RequisiteController) SetRules(ctx *gin.Context) {
+	// Parse ID
+	courseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Groups []models.RequisiteGroup `json:"groups"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.requisiteService.SetRules(ctx, courseID, req.Groups); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"course_id": courseID, "groups": req.Groups})
+}
+
+// GetOverrides retrieves the recorded requisite overrides of a course
+func (c *RequisiteController) GetOverrides(ctx *gin.Context) {
+	// Parse ID
+	courseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	overrides, err := c.requisiteService.GetOverrides(ctx, courseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, overrides)
+}
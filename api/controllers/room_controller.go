@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RoomController handles room, booking and utilisation HTTP requests
+type RoomController struct {
+	roomService services.RoomService
+}
+
+// NewRoomController creates a new instance of RoomController
+func NewRoomController(roomService services.RoomService) *RoomController {
+	return &RoomController{
+		roomService: roomService,
+	}
+}
+
+// GetRooms retrieves rooms, optionally filtered by type, building, min_capacity and comma-separated equipment
+func (c *RoomController) GetRooms(ctx *gin.Context) {
+	filter := repositories.RoomFilter{
+		Type:     models.RoomType(ctx.Query("type")),
+		Building: ctx.Query("building"),
+	}
+	if value := ctx.Query("min_capacity"); value != "" {
+		capacity, err := strconv.Atoi(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_capacity"})
+			return
+		}
+		filter.MinCapacity = capacity
+	}
+	if value := ctx.Query("equipment"); value != "" {
+		filter.Equipment = strings.Split(value, ",")
+	}
+
+	rooms, err := c.roomService.GetRooms(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rooms)
+}
+
+// GetRoom retrieves a room by ID
+func (c *RoomController) GetRoom(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	room, err := c.roomService.GetRoomByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, room)
+}
+
+// CreateRoom creates a new room
+func (c *RoomController) CreateRoom(ctx *gin.Context) {
+	// Parse request body
+	var room models.Room
+	if err := ctx.ShouldBindJSON(&room); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.roomService.CreateRoom(ctx, &room); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, room)
+}
+
+// UpdateRoom updates a room
+func (c *RoomController) UpdateRoom(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var room models.Room
+	if err := ctx.ShouldBindJSON(&room); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	room.ID = id
+
+	if err := c.roomService.UpdateRoom(ctx, &room); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, room)
+}
+
+// DeleteRoom deletes a room
+func (c *RoomController) DeleteRoom(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.roomService.DeleteRoom(ctx, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "room deleted successfully"})
+}
+
+// GetBookings retrieves a room's bookings, optionally between from and to dates (YYYY-MM-DD)
+func (c *RoomController) GetBookings(ctx *gin.Context) {
+	// Parse ID
+	roomID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+		return
+	}
+
+	from, err := parseOptionalDate(ctx.Query("from"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+	to, err := parseOptionalDate(ctx.Query("to"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	bookings, err := c.roomService.GetBookings(ctx, roomID, from, to)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bookings)
+}
+
+// CreateBooking books a room for an event
+func (c *RoomController) CreateBooking(ctx *gin.Context) {
+	// Parse ID
+	roomID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Title     string `json:"title" binding:"required"`
+		Date      string `json:"date" binding:"required"`
+		StartTime string `json:"start_time" binding:"required"`
+		EndTime   string `json:"end_time" binding:"required"`
+		Notes     string `json:"notes"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	booking := models.RoomBooking{
+		RoomID:    roomID,
+		Title:     req.Title,
+		Date:      date,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Notes:     req.Notes,
+		BookedBy:  currentUserID(ctx),
+	}
+	if err := c.roomService.CreateBooking(ctx, &booking); err != nil {
+		if errors.Is(err, services.ErrRoomDoubleBooked) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, booking)
+}
+
+// CancelBooking cancels a room booking; only the person who booked it or an administrator may cancel
+func (c *RoomController) CancelBooking(ctx *gin.Context) {
+	// Parse ID
+	bookingID, err := uuid.Parse(ctx.Param("bookingId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking ID"})
+		return
+	}
+
+	booking, err := c.roomService.GetBookingByID(ctx, bookingID)
+	if err != nil || booking.RoomID.String() != ctx.Param("id") {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if booking.BookedBy != currentUserID(ctx) && currentUserRole(ctx) != "Admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "only the person who made a booking or an administrator can cancel it"})
+		return
+	}
+
+	if err := c.roomService.CancelBooking(ctx, bookingID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "booking cancelled successfully"})
+}
+
+// GetUtilisation retrieves the room utilisation report for a term
+func (c *RoomController) GetUtilisation(ctx *gin.Context) {
+	report, err := c.roomService.GetUtilisation(ctx, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ScheduleController handles automatic timetable generation HTTP requests
+type ScheduleController struct {
+	scheduleService services.ScheduleService
+}
+
+// NewScheduleController creates a new instance of ScheduleController
+func NewScheduleController(scheduleService services.ScheduleService) *ScheduleController {
+	return &ScheduleController{
+		scheduleService: scheduleService,
+	}
+}
+
+// StartRun queues a timetable run that solves in the background
+func (c *ScheduleController) StartRun(ctx *gin.Context) {
+	// Parse request body
+	var req struct {
+		Term    string                 `json:"term"`
+		Options models.ScheduleOptions `json:"options"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := c.scheduleService.StartRun(ctx, req.Term, req.Options, currentUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusAccepted, run)
+}
+
+// GetRuns retrieves timetable runs, optionally filtered by term
+func (c *ScheduleController) GetRuns(ctx *gin.Context) {
+	runs, err := c.scheduleService.GetRuns(ctx, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// GetRun retrieves a timetable run with its draft schedule
+func (c *ScheduleController) GetRun(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	run, err := c.scheduleService.GetRun(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "timetable run not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, run)
+}
+
+// PublishRun publishes a reviewed draft schedule onto its sections
+func (c *ScheduleController) PublishRun(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	run, err := c.scheduleService.PublishRun(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, run)
+}
+
+// GetTeacherAvailability retrieves a teacher's weekly availability windows
+func (c *ScheduleController) GetTeacherAvailability(ctx *gin.Context) {
+	// Parse ID
+	teacherID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid teacher ID"})
+		return
+	}
+
+	windows, err := c.scheduleService.GetAvailability(ctx, teacherID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "teacher not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, windows)
+}
+
+// SetTeacherAvailability replaces a teacher's weekly availability windows
+func (c *ScheduleController) SetTeacherAvailability(ctx *gin.Context) {
+	// Parse ID
+	teacherID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid teacher ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Windows []models.TeacherAvailability `json:"windows"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.scheduleService.SetAvailability(ctx, teacherID, req.Windows); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"teacher_id": teacherID, "windows": req.Windows})
+}
+
+// GetCourseRequests retrieves a student's course requests, optionally filtered by term
+func (c *ScheduleController) GetCourseRequests(ctx *gin.Context) {
+	// Parse ID
+	studentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+
+	requests, err := c.scheduleService.GetCourseRequests(ctx, studentID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+// CreateCourseRequest records a student's request to take a course
+func (c *ScheduleController) CreateCourseRequest(ctx *gin.Context) {
+	// Parse ID
+	studentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		CourseID uuid.UUID `json:"course_id" binding:"required"`
+		Term     string    `json:"term"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := c.scheduleService.CreateCourseRequest(ctx, studentID, req.CourseID, req.Term)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, request)
+}
+
+// DeleteCourseRequest withdraws a student's course request
+func (c *ScheduleController) DeleteCourseRequest(ctx *gin.Context) {
+	// Parse IDs
+	studentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+	requestID, err := uuid.Parse(ctx.Param("requestId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course request ID"})
+		return
+	}
+
+	if err := c.scheduleService.DeleteCourseRequest(ctx, studentID, requestID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "course request deleted successfully"})
+}
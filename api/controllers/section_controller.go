@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SectionController handles course section HTTP requests
+type SectionController struct {
+	sectionService services.SectionService
+}
+
+// NewSectionController creates a new instance of SectionController
+func NewSectionController(sectionService services.SectionService) *SectionController {
+	return &SectionController{
+		sectionService: sectionService,
+	}
+}
+
+// GetSections retrieves sections, optionally filtered by course_id and term
+func (c *SectionController) GetSections(ctx *gin.Context) {
+	var courseID *uuid.UUID
+	if value := ctx.Query("course_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+			return
+		}
+		courseID = &id
+	}
+
+	sections, err := c.sectionService.GetSections(ctx, courseID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sections)
+}
+
+// GetCourseSections retrieves the sections of a course
+func (c *SectionController) GetCourseSections(ctx *gin.Context) {
+	// Parse ID
+	courseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	sections, err := c.sectionService.GetSections(ctx, &courseID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sections)
+}
+
+// GetSection retrieves a section with its teachers and roster
+func (c *SectionController) GetSection(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	section, err := c.sectionService.GetSectionByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "section not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, section)
+}
+
+// CreateSection creates a new course section
+func (c *SectionController) CreateSection(ctx *gin.Context) {
+	// Parse request body
+	var section models.Section
+	if err := ctx.ShouldBindJSON(&section); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create section
+	if err := c.sectionService.CreateSection(ctx, &section); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, section)
+}
+
+// UpdateSection updates a course section
+func (c *SectionController) UpdateSection(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var section models.Section
+	if err := ctx.ShouldBindJSON(&section); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	section.ID = id
+
+	if err := c.sectionService.UpdateSection(ctx, &section); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, section)
+}
+
+// DeleteSection deletes a course section
+func (c *SectionController) DeleteSection(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.sectionService.DeleteSection(ctx, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "section deleted successfully"})
+}
+
+// GetSeatCount retrieves the seat counts of a section
+func (c *SectionController) GetSeatCount(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	seats, err := c.sectionService.GetSeatCount(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "section not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, seats)
+}
+
+// GetWaitlist retrieves the waitlist of a section
+func (c *SectionController) GetWaitlist(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	entries, err := c.sectionService.GetWaitlist(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "section not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"school-management-api/internal/authz"
 	"school-management-api/internal/models"
 	"school-management-api/internal/services"
 
@@ -78,14 +80,46 @@ func (c *StudentController) CreateStudent(ctx *gin.Context) {
 	}
 
 	// Create student
-	err := c.studentService.CreateStudent(ctx, &student)
+	account, err := c.studentService.CreateStudent(ctx, &student)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Return response
-	ctx.JSON(http.StatusCreated, gin.H{"message": "student created successfully", "id": student.ID})
+	response := gin.H{"message": "student created successfully", "id": student.ID}
+	if account != nil {
+		response["account"] = account
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ProvisionAccount provisions the login of an existing student
+func (c *StudentController) ProvisionAccount(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var req models.AccountRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	account, err := c.studentService.ProvisionAccount(ctx, id, req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, account)
 }
 
 // UpdateStudent updates a student
@@ -138,8 +172,8 @@ func (c *StudentController) DeleteStudent(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "student deleted successfully"})
 }
 
-// EnrollCourse enrolls a student in a course
-func (c *StudentController) EnrollCourse(ctx *gin.Context) {
+// EnrollSection enrolls a student in a course section
+func (c *StudentController) EnrollSection(ctx *gin.Context) {
 	// Parse IDs
 	studentID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -149,26 +183,42 @@ func (c *StudentController) EnrollCourse(ctx *gin.Context) {
 
 	// Parse request body
 	var req struct {
-		CourseID uuid.UUID `json:"course_id" binding:"required"`
+		SectionID          uuid.UUID `json:"section_id" binding:"required"`
+		OverrideRequisites bool      `json:"override_requisites"`
+		OverrideReason     string    `json:"override_reason"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Enroll student in course
-	err = c.studentService.EnrollCourse(ctx, studentID, req.CourseID)
+	// Only administrators may waive prerequisites and corequisites
+	var override *models.RequisiteOverride
+	if req.OverrideRequisites {
+		if currentUserRole(ctx) != "Admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "only administrators can override course requisites"})
+			return
+		}
+		override = &models.RequisiteOverride{Reason: req.OverrideReason, GrantedBy: currentUserID(ctx)}
+	}
+
+	// Enroll student in section, or add them to its waitlist
+	result, err := c.studentService.EnrollSection(authorized(ctx), studentID, req.SectionID, override)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Return response
-	ctx.JSON(http.StatusOK, gin.H{"message": "student enrolled in course successfully"})
+	ctx.JSON(http.StatusOK, result)
 }
 
-// DropCourse removes a student from a course
-func (c *StudentController) DropCourse(ctx *gin.Context) {
+// DropSection removes a student from a course section or its waitlist
+func (c *StudentController) DropSection(ctx *gin.Context) {
 	// Parse IDs
 	studentID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -176,21 +226,65 @@ func (c *StudentController) DropCourse(ctx *gin.Context) {
 		return
 	}
 
-	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	sectionID, err := uuid.Parse(ctx.Param("sectionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid section ID"})
+		return
+	}
+
+	// Drop student from section
+	err = c.studentService.DropSection(authorized(ctx), studentID, sectionID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "student dropped from section successfully"})
+}
+
+// GetStudentSections retrieves the sections a student is enrolled in
+func (c *StudentController) GetStudentSections(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Get sections
+	sections, err := c.studentService.GetStudentSections(ctx, id, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, sections)
+}
+
+// GetStudentWaitlist retrieves the waitlists a student is on and their position in each
+func (c *StudentController) GetStudentWaitlist(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
 		return
 	}
 
-	// Drop student from course
-	err = c.studentService.DropCourse(ctx, studentID, courseID)
+	// Get waitlist entries
+	entries, err := c.studentService.GetStudentWaitlist(ctx, id)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Return response
-	ctx.JSON(http.StatusOK, gin.H{"message": "student dropped from course successfully"})
+	ctx.JSON(http.StatusOK, entries)
 }
 
 // GetStudentCourses retrieves all courses for a student
@@ -203,7 +297,7 @@ func (c *StudentController) GetStudentCourses(ctx *gin.Context) {
 	}
 
 	// Get courses
-	courses, err := c.studentService.GetStudentCourses(ctx, id)
+	courses, err := c.studentService.GetStudentCourses(ctx, id, ctx.Query("term"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -78,14 +78,46 @@ func (c *TeacherController) CreateTeacher(ctx *gin.Context) {
 	}
 
 	// Create teacher
-	err := c.teacherService.CreateTeacher(ctx, &teacher)
+	account, err := c.teacherService.CreateTeacher(ctx, &teacher)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Return response
-	ctx.JSON(http.StatusCreated, gin.H{"message": "teacher created successfully", "id": teacher.ID})
+	response := gin.H{"message": "teacher created successfully", "id": teacher.ID}
+	if account != nil {
+		response["account"] = account
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ProvisionAccount provisions the login of an existing teacher
+func (c *TeacherController) ProvisionAccount(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var req models.AccountRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	account, err := c.teacherService.ProvisionAccount(ctx, id, req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, account)
 }
 
 // UpdateTeacher updates a teacher
@@ -138,8 +170,8 @@ func (c *TeacherController) DeleteTeacher(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "teacher deleted successfully"})
 }
 
-// AssignCourse assigns a course to a teacher
-func (c *TeacherController) AssignCourse(ctx *gin.Context) {
+// AssignSection assigns a teacher to a course section
+func (c *TeacherController) AssignSection(ctx *gin.Context) {
 	// Parse IDs
 	teacherID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -149,26 +181,26 @@ func (c *TeacherController) AssignCourse(ctx *gin.Context) {
 
 	// Parse request body
 	var req struct {
-		CourseID uuid.UUID `json:"course_id" binding:"required"`
+		SectionID uuid.UUID `json:"section_id" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Assign course to teacher
-	err = c.teacherService.AssignCourse(ctx, teacherID, req.CourseID)
+	// Assign section to teacher
+	err = c.teacherService.AssignSection(ctx, teacherID, req.SectionID)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Return response
-	ctx.JSON(http.StatusOK, gin.H{"message": "course assigned to teacher successfully"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "section assigned to teacher successfully"})
 }
 
-// RemoveCourse removes a course from a teacher
-func (c *TeacherController) RemoveCourse(ctx *gin.Context) {
+// RemoveSection removes a teacher from a course section
+func (c *TeacherController) RemoveSection(ctx *gin.Context) {
 	// Parse IDs
 	teacherID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -176,21 +208,41 @@ func (c *TeacherController) RemoveCourse(ctx *gin.Context) {
 		return
 	}
 
-	courseID, err := uuid.Parse(ctx.Param("courseId"))
+	sectionID, err := uuid.Parse(ctx.Param("sectionId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid section ID"})
+		return
+	}
+
+	// Remove section from teacher
+	err = c.teacherService.RemoveSection(ctx, teacherID, sectionID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "section removed from teacher successfully"})
+}
+
+// GetTeacherSections retrieves the sections a teacher is assigned to
+func (c *TeacherController) GetTeacherSections(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
 		return
 	}
 
-	// Remove course from teacher
-	err = c.teacherService.RemoveCourse(ctx, teacherID, courseID)
+	// Get sections
+	sections, err := c.teacherService.GetTeacherSections(ctx, id, ctx.Query("term"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Return response
-	ctx.JSON(http.StatusOK, gin.H{"message": "course removed from teacher successfully"})
+	ctx.JSON(http.StatusOK, sections)
 }
 
 // GetTeacherCourses retrieves all courses for a teacher
@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TermController handles academic year and term HTTP requests
+type TermController struct {
+	termService services.TermService
+}
+
+// NewTermController creates a new instance of TermController
+func NewTermController(termService services.TermService) *TermController {
+	return &TermController{
+		termService: termService,
+	}
+}
+
+// GetYears retrieves all academic years
+func (c *TermController) GetYears(ctx *gin.Context) {
+	years, err := c.termService.GetAllYears(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, years)
+}
+
+// GetYear retrieves an academic year and its terms
+func (c *TermController) GetYear(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	year, err := c.termService.GetYearByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "academic year not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, year)
+}
+
+// CreateYear creates a new academic year
+func (c *TermController) CreateYear(ctx *gin.Context) {
+	// Parse request body
+	var year models.AcademicYear
+	if err := ctx.ShouldBindJSON(&year); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create academic year
+	if err := c.termService.CreateYear(ctx, &year); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, year)
+}
+
+// UpdateYear updates an academic year
+func (c *TermController) UpdateYear(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var year models.AcademicYear
+	if err := ctx.ShouldBindJSON(&year); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	year.ID = id
+
+	if err := c.termService.UpdateYear(ctx, &year); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, year)
+}
+
+// DeleteYear deletes an academic year
+func (c *TermController) DeleteYear(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.termService.DeleteYear(ctx, id); err != nil {
+		if errors.Is(err, services.ErrTermInUse) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "academic year deleted successfully"})
+}
+
+// GetTerms retrieves all terms
+func (c *TermController) GetTerms(ctx *gin.Context) {
+	terms, err := c.termService.GetAllTerms(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, terms)
+}
+
+// GetCurrentTerm retrieves the current term
+func (c *TermController) GetCurrentTerm(ctx *gin.Context) {
+	term, err := c.termService.GetCurrentTerm(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, term)
+}
+
+// GetTerm retrieves a term by ID
+func (c *TermController) GetTerm(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	term, err := c.termService.GetTermByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "term not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, term)
+}
+
+// CreateTerm creates a new term
+func (c *TermController) CreateTerm(ctx *gin.Context) {
+	// Parse request body
+	var term models.Term
+	if err := ctx.ShouldBindJSON(&term); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create term
+	if err := c.termService.CreateTerm(ctx, &term); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusCreated, term)
+}
+
+// UpdateTerm updates a term
+func (c *TermController) UpdateTerm(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var term models.Term
+	if err := ctx.ShouldBindJSON(&term); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set ID
+	term.ID = id
+
+	if err := c.termService.UpdateTerm(ctx, &term); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, term)
+}
+
+// DeleteTerm deletes a term
+func (c *TermController) DeleteTerm(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.termService.DeleteTerm(ctx, id); err != nil {
+		if errors.Is(err, services.ErrTermInUse) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "term deleted successfully"})
+}
+
+// SetCurrentTerm marks a term as the current term
+func (c *TermController) SetCurrentTerm(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.termService.SetCurrentTerm(ctx, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "current term updated successfully"})
+}
+
+// GetCalendar retrieves the school calendar of a term
+func (c *TermController) GetCalendar(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	calendar, err := c.termService.GetCalendar(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "term not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendar)
+}
+
+// SetCalendar replaces the school calendar of a term
+func (c *TermController) SetCalendar(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse request body
+	var calendar models.SchoolCalendar
+	if err := ctx.ShouldBindJSON(&calendar); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set term ID
+	calendar.TermID = id
+
+	if err := c.termService.SetCalendar(ctx, &calendar); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendar)
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"net/http"
+
+	"school-management-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TimetableController handles weekly timetable HTTP requests
+type TimetableController struct {
+	timetableService services.TimetableService
+}
+
+// NewTimetableController creates a new instance of TimetableController
+func NewTimetableController(timetableService services.TimetableService) *TimetableController {
+	return &TimetableController{
+		timetableService: timetableService,
+	}
+}
+
+// GetStudentTimetable retrieves a student's weekly timetable, optionally for a term
+func (c *TimetableController) GetStudentTimetable(ctx *gin.Context) {
+	// Parse ID
+	studentID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid student ID"})
+		return
+	}
+
+	timetable, err := c.timetableService.GetStudentTimetable(ctx, studentID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, timetable)
+}
+
+// GetTeacherTimetable retrieves a teacher's weekly timetable, optionally for a term
+func (c *TimetableController) GetTeacherTimetable(ctx *gin.Context) {
+	// Parse ID
+	teacherID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid teacher ID"})
+		return
+	}
+
+	timetable, err := c.timetableService.GetTeacherTimetable(ctx, teacherID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, timetable)
+}
+
+// GetRoomTimetable retrieves a room's weekly timetable, optionally for a term
+func (c *TimetableController) GetRoomTimetable(ctx *gin.Context) {
+	// Parse ID
+	roomID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+		return
+	}
+
+	timetable, err := c.timetableService.GetRoomTimetable(ctx, roomID, ctx.Query("term"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, timetable)
+}
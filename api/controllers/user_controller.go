@@ -1,14 +1,20 @@
 package controllers
 
 import (
+	"encoding/base64"
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
 
+	"school-management-api/internal/authz"
 	"school-management-api/internal/models"
 	"school-management-api/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
 )
 
 // UserController handles user-related HTTP requests
@@ -68,6 +74,17 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, user)
 }
 
+// GetCurrentUser retrieves the authenticated user, including the teacher, student or guardian they are the login of
+func (c *UserController) GetCurrentUser(ctx *gin.Context) {
+	user, err := c.userService.GetUserByID(ctx, currentUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
 // CreateUser creates a new user
 func (c *UserController) CreateUser(ctx *gin.Context) {
 	// Parse request body
@@ -108,8 +125,12 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 	user.ID = id
 
 	// Update user
-	err = c.userService.UpdateUser(ctx, &user)
+	err = c.userService.UpdateUser(authorized(ctx), &user)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -148,8 +169,11 @@ func (c *UserController) Login(ctx *gin.Context) {
 	}
 
 	// Authenticate user
-	resp, err := c.userService.Login(ctx, req.Username, req.Password)
+	resp, err := c.userService.Login(ctx, req.Username, req.Password, ctx.ClientIP())
 	if err != nil {
+		if writeThrottled(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -157,3 +181,366 @@ func (c *UserController) Login(ctx *gin.Context) {
 	// Return response
 	ctx.JSON(http.StatusOK, resp)
 }
+
+// Refresh exchanges a refresh token for a new access token and refresh token
+func (c *UserController) Refresh(ctx *gin.Context) {
+	// Parse request body
+	var req models.RefreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.userService.RefreshTokens(ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Logout ends the current session, revoking its access token and the refresh token sent with it
+func (c *UserController) Logout(ctx *gin.Context) {
+	// Parse request body
+	var req models.LogoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	tokenID := ctx.GetString("tokenID")
+	expiresAt := ctx.GetTime("tokenExpiresAt")
+	if err := c.userService.Logout(ctx, currentUserID(ctx), tokenID, expiresAt, req.RefreshToken); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+}
+
+// LogoutAll ends every session of the current user
+func (c *UserController) LogoutAll(ctx *gin.Context) {
+	if err := c.userService.LogoutAll(ctx, currentUserID(ctx)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "logged out of all sessions successfully"})
+}
+
+// ForgotPassword emails a password reset link to the user with the given email address. The response is
+// the same whether or not there is such a user.
+func (c *UserController) ForgotPassword(ctx *gin.Context) {
+	// Parse request body
+	var req models.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ForgotPassword(ctx, req.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "if a user has this email address, a link to reset their password has been sent to it"})
+}
+
+// ResetPassword sets a new password with the token from a password reset link
+func (c *UserController) ResetPassword(ctx *gin.Context) {
+	// Parse request body
+	var req models.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ResetPassword(ctx, req.Token, req.Password); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})
+}
+
+// SendVerification emails the current user a new link to verify their email address
+func (c *UserController) SendVerification(ctx *gin.Context) {
+	if err := c.userService.SendVerification(ctx, currentUserID(ctx)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "verification email sent"})
+}
+
+// VerifyEmail verifies an email address with the token from a verification link
+func (c *UserController) VerifyEmail(ctx *gin.Context) {
+	// Parse request body
+	var req models.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.VerifyEmail(ctx, req.Token); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "email address verified successfully"})
+}
+
+// LoginTwoFactor finishes a login with the challenge token from Login and a code from the user's
+// authenticator app or one of their recovery codes
+func (c *UserController) LoginTwoFactor(ctx *gin.Context) {
+	// Parse request body
+	var req models.LoginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := c.userService.LoginTwoFactor(ctx, req.ChallengeToken, req.Code, ctx.ClientIP())
+	if err != nil {
+		if writeThrottled(ctx, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// SetupTwoFactor generates a new secret for the current user to add to their authenticator app, with a PNG
+// QR code of it to scan
+func (c *UserController) SetupTwoFactor(ctx *gin.Context) {
+	setup, err := c.userService.SetupTwoFactor(ctx, currentUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	png, err := qrcode.Encode(setup.ProvisioningURI, qrcode.Medium, 256)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	setup.QRCode = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+
+	// Return response
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, setup)
+}
+
+// EnableTwoFactor turns on two-factor authentication for the current user with a code from their
+// authenticator app, returning their recovery codes
+func (c *UserController) EnableTwoFactor(ctx *gin.Context) {
+	// Parse request body
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := c.userService.EnableTwoFactor(ctx, currentUserID(ctx), req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, gin.H{"message": "two-factor authentication turned on, log in again to continue", "recovery_codes": codes})
+}
+
+// DisableTwoFactor turns off two-factor authentication for the current user with a current code
+func (c *UserController) DisableTwoFactor(ctx *gin.Context) {
+	// Parse request body
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.DisableTwoFactor(ctx, currentUserID(ctx), req.Code); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "two-factor authentication turned off"})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes given a current code
+func (c *UserController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	// Parse request body
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := c.userService.RegenerateRecoveryCodes(ctx, currentUserID(ctx), req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// ResetTwoFactor turns off a user's two-factor authentication, for when they have lost their
+// authenticator app and recovery codes
+func (c *UserController) ResetTwoFactor(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.userService.ResetTwoFactor(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "two-factor authentication reset successfully"})
+}
+
+// GetTwoFactorPolicy retrieves the roles required to use two-factor authentication
+func (c *UserController) GetTwoFactorPolicy(ctx *gin.Context) {
+	policies, err := c.userService.GetTwoFactorPolicy(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"data": policies})
+}
+
+// SetTwoFactorPolicy sets the roles required to use two-factor authentication
+func (c *UserController) SetTwoFactorPolicy(ctx *gin.Context) {
+	// Parse request body
+	var req models.TwoFactorPolicyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policies, err := c.userService.SetTwoFactorPolicy(ctx, req.RequiredRoles, currentUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"data": policies})
+}
+
+// GetLoginAttempts retrieves a user's login history with pagination, latest first, and whether logging
+// in to their account is currently held back
+func (c *UserController) GetLoginAttempts(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if page < 1 || pageSize < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "page and pageSize must be positive"})
+		return
+	}
+
+	attempts, total, err := c.userService.GetLoginAttempts(ctx, id, page, pageSize)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	lockout, err := c.userService.GetLockout(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":    attempts,
+		"lockout": lockout,
+		"meta": gin.H{
+			"page":      page,
+			"pageSize":  pageSize,
+			"total":     total,
+			"totalPage": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// UnlockUser lifts the lockout of a user's account after failed login attempts
+func (c *UserController) UnlockUser(ctx *gin.Context) {
+	// Parse ID
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := c.userService.UnlockUser(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response
+	ctx.JSON(http.StatusOK, gin.H{"message": "user unlocked successfully"})
+}
+
+// writeThrottled responds 429 Too Many Requests, saying when to retry, when logging in was refused after
+// too many failed attempts, reporting whether it did
+func writeThrottled(ctx *gin.Context, err error) bool {
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	return true
+}
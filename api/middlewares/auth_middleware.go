@@ -1,17 +1,29 @@
 package middlewares
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
+
+	"school-management-api/internal/authz"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-// JWTAuthMiddleware is a middleware that checks for a valid JWT token
-func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// TokenRevocations reports whether an access token was revoked before it expired
+type TokenRevocations interface {
+	IsTokenRevoked(ctx context.Context, userID uuid.UUID, tokenID string, issuedAt time.Time) (bool, error)
+}
+
+// JWTAuthMiddleware is a middleware that checks for a valid JWT token that has not been revoked. Tokens
+// limited to a scope, such as setting up two-factor authentication, are only accepted for the scopes given.
+func JWTAuthMiddleware(jwtSecret string, revocations TokenRevocations, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -51,9 +63,55 @@ func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 				return
 			}
 
+			// Refuse tokens that were revoked one by one, or that were issued before all of the user's tokens
+			// were last revoked, as when they changed their password
+			tokenID, _ := claims["jti"].(string)
+			issuedAt, ok := authz.IssuedAt(claims)
+			expiresAt, _ := claims.GetExpirationTime()
+			if tokenID == "" || !ok || expiresAt == nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+				return
+			}
+			revoked, err := revocations.IsTokenRevoked(c.Request.Context(), userID, tokenID, issuedAt)
+			if err != nil {
+				log.Printf("Failed to check whether token %s is revoked: %v", tokenID, err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not verify token"})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+				return
+			}
+
+			// Refuse limited tokens outside of what they are limited to
+			if scope, ok := claims["scope"].(string); ok && !slices.Contains(scopes, scope) {
+				if scope == authz.ScopeTwoFactorSetup {
+					c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "two-factor authentication must be set up first"})
+				} else {
+					c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token is not valid for this request"})
+				}
+				return
+			}
+
 			c.Set("userID", userID)
+			c.Set("tokenID", tokenID)
+			c.Set("tokenExpiresAt", expiresAt.Time)
 			c.Set("username", claims["name"])
 			c.Set("role", claims["role"])
+
+			// Set the teacher, student or guardian the user is the login of
+			for claim, key := range map[string]string{"teacher_id": "teacherID", "student_id": "studentID", "guardian_id": "guardianID"} {
+				value, ok := claims[claim].(string)
+				if !ok {
+					continue
+				}
+				id, err := uuid.Parse(value)
+				if err != nil {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+					return
+				}
+				c.Set(key, id)
+			}
 			c.Next()
 		} else {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
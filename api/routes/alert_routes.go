@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAlertRoutes sets up attendance alert rule and alert routes
+func SetupAlertRoutes(router *gin.RouterGroup, controller *controllers.AlertController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc, counselorAdminMiddleware gin.HandlerFunc) {
+	rules := router.Group("/attendance-alert-rules")
+	{
+		rules.GET("", authMiddleware, counselorAdminMiddleware, controller.GetRules)
+		rules.POST("", authMiddleware, adminMiddleware, controller.CreateRule)
+		rules.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateRule)
+		rules.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteRule)
+	}
+
+	alerts := router.Group("/attendance-alerts")
+	{
+		alerts.GET("", authMiddleware, counselorAdminMiddleware, controller.GetAlerts)
+		alerts.POST("/evaluate", authMiddleware, adminMiddleware, controller.EvaluateAlerts)
+		alerts.GET("/:id", authMiddleware, counselorAdminMiddleware, controller.GetAlert)
+		alerts.POST("/:id/acknowledge", authMiddleware, counselorAdminMiddleware, controller.AcknowledgeAlert)
+		alerts.POST("/:id/resolve", authMiddleware, counselorAdminMiddleware, controller.ResolveAlert)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAnalyticsRoutes sets up attendance analytics routes
+func SetupAnalyticsRoutes(router *gin.RouterGroup, controller *controllers.AnalyticsController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+	router.GET("/attendance/analytics", authMiddleware, adminMiddleware, controller.GetAttendanceAnalytics)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAssessmentRoutes sets up assessment and gradebook routes
+func SetupAssessmentRoutes(router *gin.RouterGroup, controller *controllers.AssessmentController, authMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc) {
+	assessments := router.Group("/assessments")
+	{
+		assessments.POST("", authMiddleware, teacherAdminMiddleware, controller.CreateAssessment)
+		assessments.GET("/:id", authMiddleware, controller.GetAssessment)
+		assessments.PUT("/:id", authMiddleware, teacherAdminMiddleware, controller.UpdateAssessment)
+		assessments.DELETE("/:id", authMiddleware, teacherAdminMiddleware, controller.DeleteAssessment)
+		assessments.PUT("/:id/scores", authMiddleware, teacherAdminMiddleware, controller.RecordScores)
+
+		// Course-related routes
+		courseRoutes := assessments.Group("/course")
+		courseRoutes.GET("/:courseId", authMiddleware, controller.GetCourseAssessments)
+		courseRoutes.GET("/:courseId/gradebook", authMiddleware, teacherAdminMiddleware, controller.GetGradebook)
+		courseRoutes.GET("/:courseId/weights", authMiddleware, controller.GetWeights)
+		courseRoutes.PUT("/:courseId/weights", authMiddleware, teacherAdminMiddleware, controller.SetWeights)
+	}
+}
@@ -25,12 +25,22 @@ func SetupAttendanceRoutes(router *gin.RouterGroup, attendanceController *contro
 		studentRoutes := attendance.Group("/student")
 		studentRoutes.GET("/:studentId", authMiddleware, attendanceController.GetAttendancesByStudent)
 		studentRoutes.GET("/:studentId/report", authMiddleware, attendanceController.GetStudentAttendanceReport)
+		studentRoutes.GET("/:studentId/minutes", authMiddleware, attendanceController.GetStudentMinutesReport)
+		studentRoutes.GET("/:studentId/rates", authMiddleware, attendanceController.GetStudentAttendanceRates)
 
 		// Course-related routes
 		courseRoutes := attendance.Group("/course")
 		courseRoutes.GET("/:courseId", authMiddleware, attendanceController.GetAttendancesByCourse)
 		courseRoutes.GET("/:courseId/report", authMiddleware, attendanceController.GetCourseAttendanceReport)
+		courseRoutes.GET("/:courseId/minutes", authMiddleware, teacherAdminMiddleware, attendanceController.GetCourseMinutesReport)
+		courseRoutes.GET("/:courseId/rates", authMiddleware, teacherAdminMiddleware, attendanceController.GetCourseAttendanceRates)
 		courseRoutes.GET("/:courseId/date/:date", authMiddleware, attendanceController.GetAttendancesByCourseAndDate)
+		courseRoutes.GET("/:courseId/date/:date/roll", authMiddleware, teacherAdminMiddleware, attendanceController.GetRollCall)
+		courseRoutes.POST("/:courseId/date/:date/roll", authMiddleware, teacherAdminMiddleware, attendanceController.TakeRollCall)
+
+		// Teacher-related routes
+		teacherRoutes := attendance.Group("/teacher")
+		teacherRoutes.GET("/:teacherId/missing", authMiddleware, teacherAdminMiddleware, attendanceController.GetMissingAttendance)
 
 		// Date-related routes
 		attendance.GET("/date/:date", authMiddleware, attendanceController.GetAttendancesByDate)
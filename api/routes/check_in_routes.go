@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCheckInRoutes sets up self-service attendance check-in routes
+func SetupCheckInRoutes(router *gin.RouterGroup, controller *controllers.CheckInController, authMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc, studentMiddleware gin.HandlerFunc) {
+	checkIn := router.Group("/attendance/check-in")
+	{
+		checkIn.POST("", authMiddleware, studentMiddleware, controller.CheckIn)
+		checkIn.POST("/sessions", authMiddleware, teacherAdminMiddleware, controller.IssueSession)
+		checkIn.GET("/meetings/:meetingId/qr", authMiddleware, teacherAdminMiddleware, controller.GetQRCode)
+	}
+}
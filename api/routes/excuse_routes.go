@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupExcuseRoutes sets up absence excuse request routes
+func SetupExcuseRoutes(router *gin.RouterGroup, controller *controllers.ExcuseController, authMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc, guardianStaffMiddleware gin.HandlerFunc) {
+	excuses := router.Group("/excuse-requests")
+	{
+		excuses.GET("", authMiddleware, guardianStaffMiddleware, controller.GetExcuses)
+		excuses.POST("", authMiddleware, guardianStaffMiddleware, controller.SubmitExcuse)
+		excuses.GET("/:id", authMiddleware, guardianStaffMiddleware, controller.GetExcuse)
+		excuses.GET("/:id/document", authMiddleware, guardianStaffMiddleware, controller.GetDocument)
+		excuses.POST("/:id/approve", authMiddleware, teacherAdminMiddleware, controller.ApproveExcuse)
+		excuses.POST("/:id/reject", authMiddleware, teacherAdminMiddleware, controller.RejectExcuse)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupGradingScaleRoutes sets up grading scale routes
+func SetupGradingScaleRoutes(router *gin.RouterGroup, controller *controllers.GradingScaleController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+	scales := router.Group("/grading-scales")
+	{
+		scales.GET("", authMiddleware, controller.GetScales)
+		scales.GET("/resolve", authMiddleware, controller.ResolveScale)
+		scales.GET("/:id", authMiddleware, controller.GetScale)
+		scales.POST("", authMiddleware, adminMiddleware, controller.CreateScale)
+		scales.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateScale)
+		scales.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteScale)
+		scales.GET("/:id/assignments", authMiddleware, controller.GetAssignments)
+		scales.POST("/:id/assignments", authMiddleware, adminMiddleware, controller.AssignScale)
+		scales.DELETE("/assignments/:assignmentId", authMiddleware, adminMiddleware, controller.RemoveAssignment)
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupGuardianRoutes sets up guardian routes and the portal guardians use to follow their children
+func SetupGuardianRoutes(router *gin.RouterGroup, controller *controllers.GuardianController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc, guardianMiddleware gin.HandlerFunc) {
+	guardians := router.Group("/guardians")
+	{
+		guardians.GET("", authMiddleware, adminMiddleware, controller.GetGuardians)
+		guardians.GET("/:id", authMiddleware, adminMiddleware, controller.GetGuardian)
+		guardians.POST("", authMiddleware, adminMiddleware, controller.CreateGuardian)
+		guardians.POST("/:id/account", authMiddleware, adminMiddleware, controller.ProvisionAccount)
+		guardians.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateGuardian)
+		guardians.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteGuardian)
+		guardians.PUT("/:id/students/:studentId", authMiddleware, adminMiddleware, controller.LinkStudent)
+		guardians.DELETE("/:id/students/:studentId", authMiddleware, adminMiddleware, controller.UnlinkStudent)
+	}
+
+	router.GET("/students/:id/guardians", authMiddleware, teacherAdminMiddleware, controller.GetStudentGuardians)
+
+	children := router.Group("/me/children")
+	{
+		children.GET("", authMiddleware, guardianMiddleware, controller.GetMyChildren)
+		children.GET("/:studentId/grades", authMiddleware, guardianMiddleware, controller.GetMyChildGrades)
+		children.GET("/:studentId/attendance", authMiddleware, guardianMiddleware, controller.GetMyChildAttendance)
+		children.GET("/:studentId/schedule", authMiddleware, guardianMiddleware, controller.GetMyChildSchedule)
+	}
+}
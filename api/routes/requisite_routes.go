@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRequisiteRoutes sets up course prerequisite and corequisite routes
+func SetupRequisiteRoutes(router *gin.RouterGroup, controller *controllers.RequisiteController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+	prerequisites := router.Group("/courses/:id/prerequisites")
+	{
+		prerequisites.GET("", controller.GetRules)
+		prerequisites.PUT("", authMiddleware, adminMiddleware, controller.SetRules)
+		prerequisites.GET("/overrides", authMiddleware, adminMiddleware, controller.GetOverrides)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRoomRoutes sets up room, booking and utilisation routes
+func SetupRoomRoutes(router *gin.RouterGroup, controller *controllers.RoomController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc) {
+	rooms := router.Group("/rooms")
+	{
+		rooms.GET("", controller.GetRooms)
+		rooms.GET("/utilisation", authMiddleware, adminMiddleware, controller.GetUtilisation)
+		rooms.GET("/:id", controller.GetRoom)
+		rooms.POST("", authMiddleware, adminMiddleware, controller.CreateRoom)
+		rooms.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateRoom)
+		rooms.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteRoom)
+		rooms.GET("/:id/bookings", authMiddleware, controller.GetBookings)
+		rooms.POST("/:id/bookings", authMiddleware, teacherAdminMiddleware, controller.CreateBooking)
+		rooms.DELETE("/:id/bookings/:bookingId", authMiddleware, teacherAdminMiddleware, controller.CancelBooking)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"school-management-api/api/controllers"
 	"school-management-api/api/middlewares"
+	"school-management-api/internal/authz"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,7 +16,21 @@ func SetupRouter(
 	userController *controllers.UserController,
 	gradeController *controllers.GradeController,
 	attendanceController *controllers.AttendanceController,
+	gradingScaleController *controllers.GradingScaleController,
+	assessmentController *controllers.AssessmentController,
+	termController *controllers.TermController,
+	sectionController *controllers.SectionController,
+	requisiteController *controllers.RequisiteController,
+	timetableController *controllers.TimetableController,
+	scheduleController *controllers.ScheduleController,
+	roomController *controllers.RoomController,
+	alertController *controllers.AlertController,
+	excuseController *controllers.ExcuseController,
+	checkInController *controllers.CheckInController,
+	analyticsController *controllers.AnalyticsController,
+	guardianController *controllers.GuardianController,
 	jwtSecret string,
+	tokenRevocations middlewares.TokenRevocations,
 ) *gin.Engine {
 	// Create a new Gin router
 	router := gin.Default()
@@ -25,21 +40,40 @@ func SetupRouter(
 	router.Use(middlewares.LoggerMiddleware())
 
 	// Create JWT auth middleware
-	authMiddleware := middlewares.JWTAuthMiddleware(jwtSecret)
+	authMiddleware := middlewares.JWTAuthMiddleware(jwtSecret, tokenRevocations)
+	// Users who must turn on two-factor authentication can only set it up, see themselves and log out
+	setupAuthMiddleware := middlewares.JWTAuthMiddleware(jwtSecret, tokenRevocations, authz.ScopeTwoFactorSetup)
 
 	// Create role-based middlewares
 	adminMiddleware := middlewares.RoleAuthMiddleware("Admin")
 	teacherAdminMiddleware := middlewares.RoleAuthMiddleware([]string{"Admin", "Teacher"})
+	counselorAdminMiddleware := middlewares.RoleAuthMiddleware([]string{"Admin", "Counselor"})
+	studentMiddleware := middlewares.RoleAuthMiddleware("Student")
+	guardianStaffMiddleware := middlewares.RoleAuthMiddleware([]string{"Admin", "Teacher", "Guardian"})
+	guardianMiddleware := middlewares.RoleAuthMiddleware("Guardian")
 
 	// Create API route group
 	api := router.Group("/api/v1")
 	// Set up routes
-	SetupUserRoutes(api, userController, authMiddleware, adminMiddleware)
-	SetupStudentRoutes(api, studentController, authMiddleware)
-	SetupTeacherRoutes(api, teacherController, authMiddleware)
+	SetupUserRoutes(api, userController, authMiddleware, setupAuthMiddleware, adminMiddleware)
+	SetupStudentRoutes(api, studentController, authMiddleware, adminMiddleware)
+	SetupTeacherRoutes(api, teacherController, authMiddleware, adminMiddleware)
 	SetupCourseRoutes(api, courseController, authMiddleware)
 	SetupGradeRoutes(api, gradeController, authMiddleware, teacherAdminMiddleware)
 	SetupAttendanceRoutes(api, attendanceController, authMiddleware, teacherAdminMiddleware)
+	SetupGradingScaleRoutes(api, gradingScaleController, authMiddleware, adminMiddleware)
+	SetupAssessmentRoutes(api, assessmentController, authMiddleware, teacherAdminMiddleware)
+	SetupTermRoutes(api, termController, authMiddleware, adminMiddleware)
+	SetupSectionRoutes(api, sectionController, authMiddleware, adminMiddleware, teacherAdminMiddleware)
+	SetupRequisiteRoutes(api, requisiteController, authMiddleware, adminMiddleware)
+	SetupTimetableRoutes(api, timetableController, authMiddleware)
+	SetupScheduleRoutes(api, scheduleController, authMiddleware, adminMiddleware, teacherAdminMiddleware)
+	SetupRoomRoutes(api, roomController, authMiddleware, adminMiddleware, teacherAdminMiddleware)
+	SetupAlertRoutes(api, alertController, authMiddleware, adminMiddleware, counselorAdminMiddleware)
+	SetupExcuseRoutes(api, excuseController, authMiddleware, teacherAdminMiddleware, guardianStaffMiddleware)
+	SetupCheckInRoutes(api, checkInController, authMiddleware, teacherAdminMiddleware, studentMiddleware)
+	SetupAnalyticsRoutes(api, analyticsController, authMiddleware, adminMiddleware)
+	SetupGuardianRoutes(api, guardianController, authMiddleware, adminMiddleware, teacherAdminMiddleware, guardianMiddleware)
 	// Health check
 	router.GET("/api/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
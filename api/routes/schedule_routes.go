@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupScheduleRoutes sets up timetable generation, teacher availability and course request routes
+func SetupScheduleRoutes(router *gin.RouterGroup, controller *controllers.ScheduleController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc) {
+	runs := router.Group("/schedule-runs")
+	{
+		runs.GET("", authMiddleware, adminMiddleware, controller.GetRuns)
+		runs.GET("/:id", authMiddleware, adminMiddleware, controller.GetRun)
+		runs.POST("", authMiddleware, adminMiddleware, controller.StartRun)
+		runs.POST("/:id/publish", authMiddleware, adminMiddleware, controller.PublishRun)
+	}
+
+	router.GET("/teachers/:id/availability", authMiddleware, controller.GetTeacherAvailability)
+	router.PUT("/teachers/:id/availability", authMiddleware, teacherAdminMiddleware, controller.SetTeacherAvailability)
+
+	router.GET("/students/:id/course-requests", authMiddleware, controller.GetCourseRequests)
+	router.POST("/students/:id/course-requests", authMiddleware, controller.CreateCourseRequest)
+	router.DELETE("/students/:id/course-requests/:requestId", authMiddleware, controller.DeleteCourseRequest)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSectionRoutes sets up course section routes
+func SetupSectionRoutes(router *gin.RouterGroup, controller *controllers.SectionController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc, teacherAdminMiddleware gin.HandlerFunc) {
+	sections := router.Group("/sections")
+	{
+		sections.GET("", controller.GetSections)
+		sections.GET("/:id", controller.GetSection)
+		sections.POST("", authMiddleware, adminMiddleware, controller.CreateSection)
+		sections.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateSection)
+		sections.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteSection)
+		sections.GET("/:id/seats", controller.GetSeatCount)
+		sections.GET("/:id/waitlist", authMiddleware, teacherAdminMiddleware, controller.GetWaitlist)
+	}
+
+	router.GET("/courses/:id/sections", controller.GetCourseSections)
+}
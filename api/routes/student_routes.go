@@ -7,16 +7,19 @@ import (
 )
 
 // SetupStudentRoutes sets up student-related routes
-func SetupStudentRoutes(router *gin.RouterGroup, controller *controllers.StudentController, authMiddleware gin.HandlerFunc) {
+func SetupStudentRoutes(router *gin.RouterGroup, controller *controllers.StudentController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
 	students := router.Group("/students")
 	{
 		students.GET("", controller.GetStudents)
 		students.GET("/:id", controller.GetStudent)
-		students.POST("", authMiddleware, controller.CreateStudent)
+		students.POST("", authMiddleware, adminMiddleware, controller.CreateStudent)
+		students.POST("/:id/account", authMiddleware, adminMiddleware, controller.ProvisionAccount)
 		students.PUT("/:id", authMiddleware, controller.UpdateStudent)
 		students.DELETE("/:id", authMiddleware, controller.DeleteStudent)
 		students.GET("/:id/courses", controller.GetStudentCourses)
-		students.POST("/:id/courses", authMiddleware, controller.EnrollCourse)
-		students.DELETE("/:id/courses/:courseId", authMiddleware, controller.DropCourse)
+		students.GET("/:id/sections", controller.GetStudentSections)
+		students.POST("/:id/sections", authMiddleware, controller.EnrollSection)
+		students.DELETE("/:id/sections/:sectionId", authMiddleware, controller.DropSection)
+		students.GET("/:id/waitlist", authMiddleware, controller.GetStudentWaitlist)
 	}
 }
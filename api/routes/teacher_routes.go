@@ -7,16 +7,18 @@ import (
 )
 
 // SetupTeacherRoutes sets up teacher-related routes
-func SetupTeacherRoutes(router *gin.RouterGroup, controller *controllers.TeacherController, authMiddleware gin.HandlerFunc) {
+func SetupTeacherRoutes(router *gin.RouterGroup, controller *controllers.TeacherController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
 	teachers := router.Group("/teachers")
 	{
 		teachers.GET("", controller.GetTeachers)
 		teachers.GET("/:id", controller.GetTeacher)
-		teachers.POST("", authMiddleware, controller.CreateTeacher)
+		teachers.POST("", authMiddleware, adminMiddleware, controller.CreateTeacher)
+		teachers.POST("/:id/account", authMiddleware, adminMiddleware, controller.ProvisionAccount)
 		teachers.PUT("/:id", authMiddleware, controller.UpdateTeacher)
 		teachers.DELETE("/:id", authMiddleware, controller.DeleteTeacher)
 		teachers.GET("/:id/courses", controller.GetTeacherCourses)
-		teachers.POST("/:id/courses", authMiddleware, controller.AssignCourse)
-		teachers.DELETE("/:id/courses/:courseId", authMiddleware, controller.RemoveCourse)
+		teachers.GET("/:id/sections", controller.GetTeacherSections)
+		teachers.POST("/:id/sections", authMiddleware, adminMiddleware, controller.AssignSection)
+		teachers.DELETE("/:id/sections/:sectionId", authMiddleware, adminMiddleware, controller.RemoveSection)
 	}
 }
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"school-management-api/api/controllers"
+	"school-management-api/api/middlewares"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// signedInAs stands in for the JWT middleware, signing the request in with a role
+func signedInAs(role string, teacherID uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("userID", uuid.New())
+		c.Set("role", role)
+		c.Set("teacherID", teacherID)
+		c.Next()
+	}
+}
+
+func TestTeacherCannotAssignThemselvesToSection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	teacherID := uuid.New()
+	router := gin.New()
+	SetupTeacherRoutes(router.Group("/api/v1"), controllers.NewTeacherController(nil),
+		signedInAs("Teacher", teacherID), middlewares.RoleAuthMiddleware("Admin"))
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/v1/teachers/"+teacherID.String()+"/sections",
+			strings.NewReader(`{"section_id": "`+uuid.NewString()+`"}`)),
+		httptest.NewRequest(http.MethodDelete, "/api/v1/teachers/"+teacherID.String()+"/sections/"+uuid.NewString(), nil),
+	}
+	for _, req := range requests {
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: got status %d, want %d", req.Method, req.URL.Path, rec.Code, http.StatusForbidden)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTermRoutes sets up academic year and term routes
+func SetupTermRoutes(router *gin.RouterGroup, controller *controllers.TermController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+	years := router.Group("/academic-years")
+	{
+		years.GET("", authMiddleware, controller.GetYears)
+		years.GET("/:id", authMiddleware, controller.GetYear)
+		years.POST("", authMiddleware, adminMiddleware, controller.CreateYear)
+		years.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateYear)
+		years.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteYear)
+	}
+
+	terms := router.Group("/terms")
+	{
+		terms.GET("", authMiddleware, controller.GetTerms)
+		terms.GET("/current", authMiddleware, controller.GetCurrentTerm)
+		terms.GET("/:id", authMiddleware, controller.GetTerm)
+		terms.POST("", authMiddleware, adminMiddleware, controller.CreateTerm)
+		terms.PUT("/:id", authMiddleware, adminMiddleware, controller.UpdateTerm)
+		terms.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteTerm)
+		terms.POST("/:id/current", authMiddleware, adminMiddleware, controller.SetCurrentTerm)
+		terms.GET("/:id/calendar", authMiddleware, controller.GetCalendar)
+		terms.PUT("/:id/calendar", authMiddleware, adminMiddleware, controller.SetCalendar)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"school-management-api/api/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTimetableRoutes sets up weekly timetable routes
+func SetupTimetableRoutes(router *gin.RouterGroup, controller *controllers.TimetableController, authMiddleware gin.HandlerFunc) {
+	timetable := router.Group("/timetable")
+	{
+		timetable.GET("/students/:id", authMiddleware, controller.GetStudentTimetable)
+		timetable.GET("/teachers/:id", authMiddleware, controller.GetTeacherTimetable)
+		timetable.GET("/rooms/:id", authMiddleware, controller.GetRoomTimetable)
+	}
+}
@@ -7,11 +7,29 @@ import (
 )
 
 // SetupUserRoutes sets up user-related routes
-func SetupUserRoutes(router *gin.RouterGroup, controller *controllers.UserController, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+func SetupUserRoutes(router *gin.RouterGroup, controller *controllers.UserController, authMiddleware gin.HandlerFunc, setupAuthMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
 	// Public routes
 	router.POST("/login", controller.Login)
+	router.POST("/login/2fa", controller.LoginTwoFactor)
+	router.POST("/refresh", controller.Refresh)
+	router.POST("/password/forgot", controller.ForgotPassword)
+	router.POST("/password/reset", controller.ResetPassword)
+	router.POST("/email/verify", controller.VerifyEmail)
 
-	// Protected routes
+	// Protected routes, the first of which users who must turn on two-factor authentication can use before they do
+	router.GET("/me", setupAuthMiddleware, controller.GetCurrentUser)
+	router.POST("/logout", setupAuthMiddleware, controller.Logout)
+	router.POST("/logout-all", setupAuthMiddleware, controller.LogoutAll)
+	twoFactor := router.Group("/2fa")
+	{
+		twoFactor.POST("/setup", setupAuthMiddleware, controller.SetupTwoFactor)
+		twoFactor.POST("/enable", setupAuthMiddleware, controller.EnableTwoFactor)
+		twoFactor.POST("/disable", authMiddleware, controller.DisableTwoFactor)
+		twoFactor.POST("/recovery-codes", authMiddleware, controller.RegenerateRecoveryCodes)
+		twoFactor.GET("/policy", authMiddleware, adminMiddleware, controller.GetTwoFactorPolicy)
+		twoFactor.PUT("/policy", authMiddleware, adminMiddleware, controller.SetTwoFactorPolicy)
+	}
+	router.POST("/email/verification", authMiddleware, controller.SendVerification)
 	users := router.Group("/users")
 	{
 		users.GET("", authMiddleware, adminMiddleware, controller.GetUsers)
@@ -19,5 +37,8 @@ func SetupUserRoutes(router *gin.RouterGroup, controller *controllers.UserContro
 		users.POST("", authMiddleware, adminMiddleware, controller.CreateUser)
 		users.PUT("/:id", authMiddleware, controller.UpdateUser)
 		users.DELETE("/:id", authMiddleware, adminMiddleware, controller.DeleteUser)
+		users.DELETE("/:id/2fa", authMiddleware, adminMiddleware, controller.ResetTwoFactor)
+		users.GET("/:id/login-attempts", authMiddleware, adminMiddleware, controller.GetLoginAttempts)
+		users.POST("/:id/unlock", authMiddleware, adminMiddleware, controller.UnlockUser)
 	}
 }
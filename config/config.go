@@ -4,19 +4,30 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for our application
 type Config struct {
-	DBHost     string
-	DBPort     int
-	DBUser     string
-	DBPassword string
-	DBName     string
-	JWTSecret  string
-	Port       int
+	DBHost               string
+	DBPort               int
+	DBUser               string
+	DBPassword           string
+	DBName               string
+	JWTSecret            string
+	Port                 int
+	GPARetakePolicy      string
+	UploadDir            string
+	AppURL               string
+	Mailer               string
+	MailFrom             string
+	MailDir              string
+	LoginThrottleStore   string
+	LoginLockoutAttempts int
+	LoginLockoutMinutes  int
+	TrustedProxies       []string
 }
 
 // LoadConfig loads configuration from environment variables
@@ -29,15 +40,35 @@ func LoadConfig() (*Config, error) {
 
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	port, _ := strconv.Atoi(getEnv("PORT", "8080"))
+	lockoutAttempts, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_ATTEMPTS", "10"))
+	lockoutMinutes, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MINUTES", "15"))
+
+	// Only proxies listed are trusted to report the address a request came from
+	var trustedProxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
 
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     dbPort,
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "school_db"),
-		JWTSecret:  getEnv("JWT_SECRET", "default_jwt_secret"),
-		Port:       port,
+		DBHost:               getEnv("DB_HOST", "localhost"),
+		DBPort:               dbPort,
+		DBUser:               getEnv("DB_USER", "postgres"),
+		DBPassword:           getEnv("DB_PASSWORD", "postgres"),
+		DBName:               getEnv("DB_NAME", "school_db"),
+		JWTSecret:            getEnv("JWT_SECRET", "default_jwt_secret"),
+		Port:                 port,
+		GPARetakePolicy:      getEnv("GPA_RETAKE_POLICY", "latest"),
+		UploadDir:            getEnv("UPLOAD_DIR", "uploads"),
+		AppURL:               getEnv("APP_URL", "http://localhost:3000"),
+		Mailer:               getEnv("MAILER", "log"),
+		MailFrom:             getEnv("MAIL_FROM", "no-reply@school.local"),
+		MailDir:              getEnv("MAIL_DIR", "mail"),
+		LoginThrottleStore:   getEnv("LOGIN_THROTTLE_STORE", "memory"),
+		LoginLockoutAttempts: lockoutAttempts,
+		LoginLockoutMinutes:  lockoutMinutes,
+		TrustedProxies:       trustedProxies,
 	}, nil
 }
 
@@ -2,7 +2,10 @@ package config
 
 import (
 	"log"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"school-management-api/internal/models"
 
 	"gorm.io/gorm"
@@ -11,6 +14,34 @@ import (
 // MigrateDB runs database migrations
 func MigrateDB(db *gorm.DB) error {
 	log.Println("Running database migrations...")
+	// Use custom join tables that record enrollment terms and timestamps
+	if err := db.SetupJoinTable(&models.Student{}, "Courses", &models.StudentCourse{}); err != nil {
+		return err
+	}
+	if err := db.SetupJoinTable(&models.Course{}, "Students", &models.StudentCourse{}); err != nil {
+		return err
+	}
+	if err := db.SetupJoinTable(&models.Section{}, "Students", &models.SectionStudent{}); err != nil {
+		return err
+	}
+	if err := db.SetupJoinTable(&models.Section{}, "Teachers", &models.SectionTeacher{}); err != nil {
+		return err
+	}
+
+	// Duplicate attendance records would stop the unique index from being created
+	if err := dedupeAttendance(db); err != nil {
+		return err
+	}
+	// So would a rule's duplicate unresolved alerts for a student
+	if err := dedupeUnresolvedAlerts(db); err != nil {
+		return err
+	}
+
+	// Deleted records used to keep their names from being reused
+	if err := dropUniqueNameIndexes(db); err != nil {
+		return err
+	}
+
 	// Auto-migrate schemas
 	err := db.AutoMigrate(
 		&models.User{},
@@ -19,15 +50,270 @@ func MigrateDB(db *gorm.DB) error {
 		&models.Course{},
 		&models.Grade{},
 		&models.Attendance{},
+		&models.GradingScale{},
+		&models.GradingBand{},
+		&models.GradingScaleAssignment{},
+		&models.AssessmentWeight{},
+		&models.Assessment{},
+		&models.AssessmentScore{},
+		&models.AcademicYear{},
+		&models.Term{},
+		&models.Section{},
+		&models.SectionMeeting{},
+		&models.WaitlistEntry{},
+		&models.CourseEnrollment{},
+		&models.RequisiteGroup{},
+		&models.RequisiteOption{},
+		&models.RequisiteOverride{},
+		&models.ScheduleRun{},
+		&models.ScheduleAssignment{},
+		&models.TeacherAvailability{},
+		&models.CourseRequest{},
+		&models.Room{},
+		&models.RoomBooking{},
+		&models.SchoolCalendar{},
+		&models.CalendarDay{},
+		&models.AttendanceAlertRule{},
+		&models.AttendanceAlert{},
+		&models.ExcuseRequest{},
+		&models.CheckIn{},
+		&models.Guardian{},
+		&models.StudentGuardian{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.UserToken{},
+		&models.RecoveryCode{},
+		&models.TwoFactorPolicy{},
+		&models.LoginAttempt{},
+		&models.LoginThrottle{},
 	)
 	if err != nil {
 		return err
 	}
 
+	if err := migrateRoomNames(db); err != nil {
+		return err
+	}
+	if err := linkAccountsByEmail(db); err != nil {
+		return err
+	}
+	if err := backfillCourseEnrollments(db); err != nil {
+		return err
+	}
+	if err := indexActiveScheduleRuns(db); err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
 
+// migrateRoomNames moves the free-text room names that sections and meetings used to carry into the
+// room registry, pointing each section and meeting at the matching room before dropping the old column
+func migrateRoomNames(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"sections", "section_meetings"} {
+			if !tx.Migrator().HasColumn(table, "room") {
+				continue
+			}
+
+			var names []string
+			if err := tx.Table(table).Where("room <> ''").Distinct().Pluck("room", &names).Error; err != nil {
+				return err
+			}
+			for _, name := range names {
+				room := models.Room{Name: name, Type: models.RoomClassroom}
+				if err := tx.Where("name = ?", name).FirstOrCreate(&room).Error; err != nil {
+					return err
+				}
+				if err := tx.Table(table).Where("room = ?", name).Update("room_id", room.ID).Error; err != nil {
+					return err
+				}
+			}
+			if len(names) > 0 {
+				log.Printf("Moved %d room names from %s into the room registry; set their capacity and type", len(names), table)
+			}
+
+			if err := tx.Migrator().DropColumn(table, "room"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// linkAccountsByEmail links the teacher, student and guardian logins created before accounts were linked to
+// people to the teacher, student or guardian with the same email address, unless that person already has a login
+func linkAccountsByEmail(db *gorm.DB) error {
+	links := []struct{ role, column, table string }{
+		{"Teacher", "teacher_id", "teachers"},
+		{"Student", "student_id", "students"},
+		{"Guardian", "guardian_id", "guardians"},
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, link := range links {
+			result := tx.Exec(`UPDATE users u SET `+link.column+` = p.id FROM `+link.table+` p
+				WHERE u.role = ? AND u.`+link.column+` IS NULL AND u.deleted_at IS NULL
+					AND p.deleted_at IS NULL AND LOWER(p.email) = LOWER(u.email)
+					AND NOT EXISTS (SELECT 1 FROM users o WHERE o.`+link.column+` = p.id AND o.deleted_at IS NULL)`, link.role)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				log.Printf("Linked %d existing %s logins to their %s by email address", result.RowsAffected, strings.ToLower(link.role), link.table)
+			}
+		}
+		return nil
+	})
+}
+
+// backfillCourseEnrollments records the enrollment history of the course enrollments made before it was
+// kept, which only know the term of their latest enrollment
+func backfillCourseEnrollments(db *gorm.DB) error {
+	result := db.Exec(`INSERT INTO course_enrollments (student_id, course_id, term_id, created_at)
+		SELECT student_id, course_id, term_id, created_at FROM student_courses WHERE term_id IS NOT NULL
+		ON CONFLICT DO NOTHING`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Recorded %d existing course enrollments in the enrollment history", result.RowsAffected)
+	}
+	return nil
+}
+
+// dropUniqueNameIndexes drops the unique indexes on names that counted soft-deleted records. AutoMigrate
+// replaces them with indexes over the records that are not deleted.
+func dropUniqueNameIndexes(db *gorm.DB) error {
+	indexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.GradingScale{}, "idx_grading_scales_name"},
+		{&models.AcademicYear{}, "idx_academic_years_name"},
+		{&models.Term{}, "idx_terms_name"},
+	}
+	for _, index := range indexes {
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexActiveScheduleRuns lets each term have at most one pending or running timetable run. Runs still
+// active at this point were interrupted by the restart and would be failed at startup anyway, so they
+// are failed first in case several of them share a term.
+func indexActiveScheduleRuns(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&models.ScheduleRun{}, "idx_schedule_runs_active_term") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ScheduleRun{}).
+			Where("status IN ?", []models.ScheduleRunStatus{models.ScheduleRunPending, models.ScheduleRunRunning}).
+			Updates(map[string]interface{}{"status": models.ScheduleRunFailed, "error": "interrupted by a server restart"}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX idx_schedule_runs_active_term ON schedule_runs (term_id)
+			WHERE status IN ('pending', 'running') AND deleted_at IS NULL`).Error
+	})
+}
+
+// dedupeAttendance merges attendance records that share a student, course and day into the most
+// recently updated one, keeping the notes of every record, before the unique index is added
+func dedupeAttendance(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Attendance{}) || db.Migrator().HasIndex(&models.Attendance{}, "idx_attendances_student_course_date_period") {
+		return nil
+	}
+
+	type duplicateGroup struct {
+		StudentID uuid.UUID
+		CourseID  uuid.UUID
+		Day       time.Time
+		Count     int
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var groups []duplicateGroup
+		if err := tx.Model(&models.Attendance{}).
+			Select("student_id, course_id, DATE(date) AS day, COUNT(*) AS count").
+			Group("student_id, course_id, DATE(date)").
+			Having("COUNT(*) > 1").
+			Scan(&groups).Error; err != nil {
+			return err
+		}
+
+		removed := 0
+		for _, group := range groups {
+			var records []models.Attendance
+			if err := tx.Where("student_id = ? AND course_id = ? AND DATE(date) = ?", group.StudentID, group.CourseID, group.Day.Format("2006-01-02")).
+				Order("updated_at DESC").
+				Find(&records).Error; err != nil {
+				return err
+			}
+			if len(records) < 2 {
+				continue
+			}
+
+			kept := records[0]
+			var notes []string
+			seen := make(map[string]bool)
+			ids := make([]uuid.UUID, 0, len(records)-1)
+			for i, record := range records {
+				if note := strings.TrimSpace(record.Notes); note != "" && !seen[note] {
+					seen[note] = true
+					notes = append(notes, note)
+				}
+				if i > 0 {
+					ids = append(ids, record.ID)
+				}
+			}
+
+			if err := tx.Model(&models.Attendance{}).Where("id = ?", kept.ID).Update("notes", strings.Join(notes, "; ")).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Attendance{}).Error; err != nil {
+				return err
+			}
+
+			log.Printf("Merged %d attendance records for student %s in course %s on %s into %s (kept status %s)",
+				len(records), group.StudentID, group.CourseID, group.Day.Format("2006-01-02"), kept.ID, kept.Status)
+			removed += len(ids)
+		}
+
+		if removed > 0 {
+			log.Printf("Removed %d duplicate attendance records across %d student, course and day groups", removed, len(groups))
+		}
+		return nil
+	})
+}
+
+// dedupeUnresolvedAlerts resolves all but the newest of the unresolved alerts a rule raised for the same
+// student, before the unique index is added
+func dedupeUnresolvedAlerts(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.AttendanceAlert{}) || db.Migrator().HasIndex(&models.AttendanceAlert{}, "idx_attendance_alerts_unresolved") {
+		return nil
+	}
+
+	result := db.Exec(`UPDATE attendance_alerts a SET status = ?, resolved_at = NOW(),
+			notes = CONCAT_WS('; ', NULLIF(a.notes, ''), 'resolved as a duplicate of a newer alert')
+		WHERE a.status <> ? AND a.deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM attendance_alerts b
+			WHERE b.student_id = a.student_id AND b.rule_id = a.rule_id AND b.status <> ? AND b.deleted_at IS NULL
+				AND (b.created_at > a.created_at OR (b.created_at = a.created_at AND b.id > a.id)))`,
+		models.AlertResolved, models.AlertResolved, models.AlertResolved)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Resolved %d duplicate unresolved attendance alerts", result.RowsAffected)
+	}
+	return nil
+}
+
 // SeedDB seeds the database with initial data if needed
 func SeedDB(db *gorm.DB) error {
 	// Check if admin user exists
@@ -52,5 +338,26 @@ func SeedDB(db *gorm.DB) error {
 		log.Println("Default admin user created")
 	}
 
+	// If no default grading scale exists, create the standard 4.0 scale
+	db.Model(&models.GradingScale{}).Where("is_default = ?", true).Count(&count)
+	if count == 0 {
+		log.Println("Creating default grading scale...")
+		if err := db.Create(models.DefaultGradingScale()).Error; err != nil {
+			return err
+		}
+		log.Println("Default grading scale created")
+	}
+
+	// If no attendance alert rules exist, create the standard early-warning rules
+	db.Model(&models.AttendanceAlertRule{}).Count(&count)
+	if count == 0 {
+		log.Println("Creating default attendance alert rules...")
+		rules := models.DefaultAlertRules()
+		if err := db.Create(&rules).Error; err != nil {
+			return err
+		}
+		log.Println("Default attendance alert rules created")
+	}
+
 	return nil
 }
@@ -0,0 +1,83 @@
+// Package authz describes who a request is made on behalf of, so services can limit the records
+// they read and change to the ones the caller may access.
+package authz
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Roles a user can have
+const (
+	RoleAdmin     = "Admin"
+	RoleTeacher   = "Teacher"
+	RoleCounselor = "Counselor"
+	RoleStudent   = "Student"
+	RoleGuardian  = "Guardian"
+)
+
+// ScopeTwoFactorSetup limits an access token to setting up two-factor authentication, for users whose
+// role requires it before they can do anything else
+const ScopeTwoFactorSetup = "two_factor_setup"
+
+// IssuedAt returns when a token was issued, to the millisecond, from its claims. Tokens are compared
+// with when their user's tokens were revoked to the millisecond, and the jwt package reads the claim
+// through a float that it truncates, a millisecond early about half the time, so the claim is rounded.
+func IssuedAt(claims jwt.MapClaims) (time.Time, bool) {
+	iat, ok := claims["iat"].(float64)
+	if !ok || iat <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(math.Round(iat * 1000))), true
+}
+
+// ErrForbidden is returned when the caller may not access a record
+var ErrForbidden = errors.New("forbidden")
+
+// Principal is the user a request is made on behalf of
+type Principal struct {
+	UserID     uuid.UUID
+	Role       string
+	TeacherID  *uuid.UUID // Teacher the user is the login of, if any
+	StudentID  *uuid.UUID // Student the user is the login of, if any
+	GuardianID *uuid.UUID // Guardian the user is the login of, if any
+	system     bool
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// System returns a context for work the application does on its own behalf, such as recording the
+// attendance of a student who checked themselves in, which may access any record
+func System(ctx context.Context) context.Context {
+	return WithPrincipal(ctx, &Principal{Role: RoleAdmin, system: true})
+}
+
+// FromContext returns the principal a context carries. A context without one is refused, so a caller
+// that forgets to say who it acts for sees nothing.
+func FromContext(ctx context.Context) (*Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	if !ok || principal == nil {
+		return nil, ErrForbidden
+	}
+	return principal, nil
+}
+
+// IsAdmin reports whether the principal may read and change any record
+func (p *Principal) IsAdmin() bool {
+	return p.system || p.Role == RoleAdmin
+}
+
+// ReadsAll reports whether the principal may read any record, as counselors following up on students can
+func (p *Principal) ReadsAll() bool {
+	return p.IsAdmin() || p.Role == RoleCounselor
+}
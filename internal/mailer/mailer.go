@@ -0,0 +1,89 @@
+// Package mailer sends email to users, such as the links to reset a password or verify an address.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is an email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New creates the mailer of a kind: "log" writes messages to the application log and "file" writes each
+// one to a file in dir, for local use and deployments without a mail server
+func New(kind, from, dir string) (Mailer, error) {
+	switch kind {
+	case "", "log":
+		return NewLogMailer(from), nil
+	case "file":
+		return NewFileMailer(from, dir), nil
+	}
+	return nil, fmt.Errorf("unknown mailer %q, expected log or file", kind)
+}
+
+// LogMailer writes messages to the application log
+type LogMailer struct {
+	from string
+}
+
+// NewLogMailer creates a new LogMailer
+func NewLogMailer(from string) Mailer {
+	return &LogMailer{from: from}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	log.Printf("Email from %s to %s: %s\n%s", m.from, message.To, message.Subject, message.Body)
+	return nil
+}
+
+// FileMailer writes each message to an .eml file in a directory
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer creates a new FileMailer writing to dir
+func NewFileMailer(from, dir string) Mailer {
+	return &FileMailer{from: from, dir: dir}
+}
+
+// Send writes the message to a new file
+func (m *FileMailer) Send(ctx context.Context, message Message) error {
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var content strings.Builder
+	fmt.Fprintf(&content, "From: %s\r\n", headerValue(m.from))
+	fmt.Fprintf(&content, "To: %s\r\n", headerValue(message.To))
+	fmt.Fprintf(&content, "Subject: %s\r\n", headerValue(message.Subject))
+	fmt.Fprintf(&content, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	content.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), uuid.New())
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content.String()), 0o640)
+}
+
+// headerValue strips line breaks from a header value, so it cannot add headers of its own
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
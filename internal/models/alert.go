@@ -0,0 +1,203 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertRuleType is the attendance pattern an alert rule looks for
+type AlertRuleType string
+
+const (
+	// AbsenceRateRule flags a student absent for at least Threshold percent of the sessions recorded
+	// over the last WindowDays instructional days
+	AbsenceRateRule AlertRuleType = "absence_rate"
+	// ConsecutiveAbsencesRule flags a student absent for at least Threshold instructional days in a row
+	ConsecutiveAbsencesRule AlertRuleType = "consecutive_absences"
+	// LatesRule flags a student late at least Threshold times in the last WindowDays calendar days
+	LatesRule AlertRuleType = "lates"
+)
+
+// AlertStatus is where an attendance alert is in a counselor's follow-up
+type AlertStatus string
+
+const (
+	AlertOpen         AlertStatus = "open"
+	AlertAcknowledged AlertStatus = "acknowledged"
+	AlertResolved     AlertStatus = "resolved"
+)
+
+// AttendanceAlertRule is a configurable threshold that raises an alert when a student's attendance crosses it
+type AttendanceAlertRule struct {
+	Base
+	Name       string        `json:"name" gorm:"size:100;not null"`
+	Type       AlertRuleType `json:"type" gorm:"size:30;not null"`
+	Threshold  float64       `json:"threshold"`   // A percentage for absence_rate, otherwise a count
+	WindowDays int           `json:"window_days"` // Instructional days for absence_rate, calendar days for lates
+	Disabled   bool          `json:"disabled"`
+}
+
+// AttendanceAlert is a student flagged by an alert rule, followed up by their counselor
+type AttendanceAlert struct {
+	Base
+	StudentID      uuid.UUID            `json:"student_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_attendance_alerts_unresolved,where:status <> 'resolved' AND deleted_at IS NULL"`
+	Student        *Student             `json:"student,omitempty"`
+	RuleID         uuid.UUID            `json:"rule_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_attendance_alerts_unresolved,where:status <> 'resolved' AND deleted_at IS NULL"`
+	Rule           *AttendanceAlertRule `json:"rule,omitempty"`
+	Status         AlertStatus          `json:"status" gorm:"size:20;not null;index"`
+	Value          float64              `json:"value"` // What the rule measured when it fired
+	Message        string               `json:"message" gorm:"type:text"`
+	TriggeredOn    time.Time            `json:"triggered_on" gorm:"type:date;not null"`
+	AcknowledgedBy *uuid.UUID           `json:"acknowledged_by" gorm:"type:uuid"`
+	AcknowledgedAt *time.Time           `json:"acknowledged_at"`
+	ResolvedBy     *uuid.UUID           `json:"resolved_by" gorm:"type:uuid"`
+	ResolvedAt     *time.Time           `json:"resolved_at"`
+	Notes          string               `json:"notes" gorm:"type:text"`
+}
+
+// AlertFinding is the outcome of checking one rule against a student's attendance
+type AlertFinding struct {
+	Triggered bool
+	Value     float64
+	Message   string
+	LastDate  time.Time // Most recent attendance that counted towards the finding
+}
+
+// DefaultAlertRules returns the rules seeded into a new database
+func DefaultAlertRules() []AttendanceAlertRule {
+	return []AttendanceAlertRule{
+		{Name: "Chronic absence", Type: AbsenceRateRule, Threshold: 10, WindowDays: 30},
+		{Name: "Consecutive absences", Type: ConsecutiveAbsencesRule, Threshold: 3},
+		{Name: "Frequent lates", Type: LatesRule, Threshold: 5, WindowDays: 7},
+	}
+}
+
+// IsValid reports whether the status is one of the AlertStatus constants
+func (s AlertStatus) IsValid() bool {
+	switch s {
+	case AlertOpen, AlertAcknowledged, AlertResolved:
+		return true
+	}
+	return false
+}
+
+// Validate checks the rule's name, type, threshold and window
+func (r *AttendanceAlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("rule name is required")
+	}
+	switch r.Type {
+	case AbsenceRateRule:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return errors.New("absence rate threshold must be a percentage above 0 and at most 100")
+		}
+	case ConsecutiveAbsencesRule, LatesRule:
+		if r.Threshold < 1 || r.Threshold != math.Trunc(r.Threshold) {
+			return errors.New("threshold must be a whole number of at least 1")
+		}
+	default:
+		return errors.New("rule type must be absence_rate, consecutive_absences or lates")
+	}
+	if r.Type == ConsecutiveAbsencesRule {
+		r.WindowDays = 0
+	} else if r.WindowDays < 1 {
+		return errors.New("window must be at least 1 day")
+	}
+	return nil
+}
+
+// LookbackDays is how many instructional days of attendance the rule needs to see
+func (r *AttendanceAlertRule) LookbackDays() int {
+	switch r.Type {
+	case ConsecutiveAbsencesRule:
+		// Days without attendance taken are skipped, so look a little further back
+		return int(r.Threshold) * 2
+	default:
+		return r.WindowDays
+	}
+}
+
+// Evaluate checks the rule against a student's attendance. Days are the instructional days up to
+// today in date order; records on other days are ignored.
+func (r *AttendanceAlertRule) Evaluate(records []Attendance, days []time.Time, today time.Time) AlertFinding {
+	byDay := make(map[string][]*Attendance)
+	for i := range records {
+		key := records[i].Date.Format("2006-01-02")
+		byDay[key] = append(byDay[key], &records[i])
+	}
+
+	var finding AlertFinding
+	switch r.Type {
+	case AbsenceRateRule:
+		window := days
+		if len(window) > r.WindowDays {
+			window = window[len(window)-r.WindowDays:]
+		}
+		sessions, absent := 0, 0
+		for _, day := range window {
+			for _, record := range byDay[day.Format("2006-01-02")] {
+				sessions++
+				if record.Status == Absent {
+					absent++
+					finding.LastDate = day
+				}
+			}
+		}
+		if sessions == 0 {
+			return finding
+		}
+		finding.Value = math.Round(float64(absent)/float64(sessions)*1000) / 10
+		finding.Triggered = absent > 0 && finding.Value >= r.Threshold
+		finding.Message = fmt.Sprintf("Absent for %.1f%% of sessions over the last %d instructional days", finding.Value, len(window))
+
+	case ConsecutiveAbsencesRule:
+		streak := 0
+		for i := len(days) - 1; i >= 0; i-- {
+			marks := byDay[days[i].Format("2006-01-02")]
+			if len(marks) == 0 {
+				// No attendance taken that day
+				continue
+			}
+			if !allAbsent(marks) {
+				break
+			}
+			if streak == 0 {
+				finding.LastDate = days[i]
+			}
+			streak++
+		}
+		finding.Value = float64(streak)
+		finding.Triggered = finding.Value >= r.Threshold
+		finding.Message = fmt.Sprintf("Absent for %d instructional days in a row", streak)
+
+	case LatesRule:
+		since := DateOnly(today).AddDate(0, 0, -r.WindowDays)
+		lates := 0
+		for i := range records {
+			if records[i].Status == Late && records[i].Date.After(since) {
+				lates++
+				if records[i].Date.After(finding.LastDate) {
+					finding.LastDate = records[i].Date
+				}
+			}
+		}
+		finding.Value = float64(lates)
+		finding.Triggered = finding.Value >= r.Threshold
+		finding.Message = fmt.Sprintf("Late %d times in the last %d days", lates, r.WindowDays)
+	}
+	return finding
+}
+
+// allAbsent reports whether every session marked on a day was an absence
+func allAbsent(marks []*Attendance) bool {
+	for _, mark := range marks {
+		if mark.Status != Absent {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceBreakdown counts the attendance recorded for one group of an analytics report
+type AttendanceBreakdown struct {
+	Key         string  `json:"key"`             // YYYY-MM-DD day or week start, course or teacher ID, or grade level
+	Label       string  `json:"label,omitempty"` // Course code or teacher name
+	Total       int     `json:"total"`
+	Present     int     `json:"present"`
+	Late        int     `json:"late"`
+	Absent      int     `json:"absent"`
+	Excused     int     `json:"excused"`
+	Rate        float64 `json:"rate"`         // Percentage of records attended, late included
+	AbsenceRate float64 `json:"absence_rate"` // Percentage of records absent without excuse
+}
+
+// AttendanceAnalyticsFilter narrows the attendance an analytics report covers; zero values match everything
+type AttendanceAnalyticsFilter struct {
+	From       string     `json:"from"` // YYYY-MM-DD, inclusive
+	To         string     `json:"to"`   // YYYY-MM-DD, inclusive
+	CourseID   *uuid.UUID `json:"course_id,omitempty"`
+	TeacherID  *uuid.UUID `json:"teacher_id,omitempty"`
+	GradeLevel string     `json:"grade_level,omitempty"`
+}
+
+// AttendanceAnalytics breaks down the attendance recorded over a date range. Days and weeks without
+// any attendance recorded are left out; weeks start on Monday.
+type AttendanceAnalytics struct {
+	Filter       AttendanceAnalyticsFilter `json:"filter"`
+	Total        AttendanceBreakdown       `json:"total"`
+	ByDay        []AttendanceBreakdown     `json:"by_day"`
+	ByWeek       []AttendanceBreakdown     `json:"by_week"`
+	ByCourse     []AttendanceBreakdown     `json:"by_course"`
+	ByGradeLevel []AttendanceBreakdown     `json:"by_grade_level"`
+	ByTeacher    []AttendanceBreakdown     `json:"by_teacher"` // Co-taught sections count towards each teacher
+}
+
+// UpdateRates computes the percentages from the counts
+func (b *AttendanceBreakdown) UpdateRates() {
+	if b.Total == 0 {
+		b.Rate, b.AbsenceRate = 0, 0
+		return
+	}
+	b.Rate = math.Round(float64(b.Present+b.Late)/float64(b.Total)*1000) / 10
+	b.AbsenceRate = math.Round(float64(b.Absent)/float64(b.Total)*1000) / 10
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssessmentType is the gradebook category an assessment belongs to
+type AssessmentType string
+
+const (
+	AssessmentQuiz     AssessmentType = "quiz"
+	AssessmentExam     AssessmentType = "exam"
+	AssessmentHomework AssessmentType = "homework"
+	AssessmentProject  AssessmentType = "project"
+)
+
+// IsValid reports whether the assessment type is a known category
+func (t AssessmentType) IsValid() bool {
+	switch t {
+	case AssessmentQuiz, AssessmentExam, AssessmentHomework, AssessmentProject:
+		return true
+	}
+	return false
+}
+
+// ScoreStatus marks whether a score was graded, is missing or was excused
+type ScoreStatus string
+
+const (
+	ScoreGraded  ScoreStatus = "graded"
+	ScoreMissing ScoreStatus = "missing"
+	ScoreExcused ScoreStatus = "excused"
+)
+
+// IsValid reports whether the score status is a known status
+func (s ScoreStatus) IsValid() bool {
+	switch s {
+	case ScoreGraded, ScoreMissing, ScoreExcused:
+		return true
+	}
+	return false
+}
+
+// AssessmentWeight sets how much a category of assessments counts toward a course grade
+type AssessmentWeight struct {
+	Base
+	CourseID   uuid.UUID      `json:"course_id" gorm:"type:uuid;not null;uniqueIndex:idx_assessment_weight_course_type"`
+	Type       AssessmentType `json:"type" gorm:"type:varchar(20);not null;uniqueIndex:idx_assessment_weight_course_type"`
+	Weight     float64        `json:"weight" gorm:"not null"` // Percentage of the final grade
+	DropLowest int            `json:"drop_lowest"`            // Number of lowest scores dropped
+}
+
+// Assessment is a quiz, exam, homework or project within a course
+type Assessment struct {
+	Base
+	CourseID  uuid.UUID      `json:"course_id" gorm:"type:uuid;not null;index"`
+	Course    Course         `json:"-" gorm:"foreignKey:CourseID"`
+	Term      string         `json:"term" gorm:"size:20"`
+	Type      AssessmentType `json:"type" gorm:"type:varchar(20);not null"`
+	Title     string         `json:"title" gorm:"not null"`
+	MaxPoints float64        `json:"max_points" gorm:"not null"`
+	DueDate   *time.Time     `json:"due_date,omitempty"`
+	CreatedBy uuid.UUID      `json:"created_by" gorm:"type:uuid"`
+}
+
+// AssessmentScore is a student's result on an assessment
+type AssessmentScore struct {
+	Base
+	AssessmentID uuid.UUID   `json:"assessment_id" gorm:"type:uuid;not null;uniqueIndex:idx_assessment_score_student"`
+	StudentID    uuid.UUID   `json:"student_id" gorm:"type:uuid;not null;uniqueIndex:idx_assessment_score_student"`
+	Points       *float64    `json:"points"`
+	Status       ScoreStatus `json:"status" gorm:"type:varchar(10);not null;default:graded"`
+	Comment      string      `json:"comment"`
+	UpdatedBy    uuid.UUID   `json:"updated_by" gorm:"type:uuid"`
+}
+
+// GradebookRow is one student's line in a course gradebook
+type GradebookRow struct {
+	StudentID        uuid.UUID                      `json:"student_id"`
+	FirstName        string                         `json:"first_name"`
+	LastName         string                         `json:"last_name"`
+	Scores           map[uuid.UUID]*AssessmentScore `json:"scores"`
+	CategoryAverages map[AssessmentType]float64     `json:"category_averages"`
+	WeightedAverage  *float64                       `json:"weighted_average"`
+	Grade            string                         `json:"grade,omitempty"`
+}
+
+// Gradebook is the student x assessment matrix for a course
+type Gradebook struct {
+	CourseID    uuid.UUID          `json:"course_id"`
+	Term        string             `json:"term,omitempty"`
+	Weights     []AssessmentWeight `json:"weights"`
+	Assessments []Assessment       `json:"assessments"`
+	Rows        []GradebookRow     `json:"rows"`
+}
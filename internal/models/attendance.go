@@ -1,6 +1,9 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,18 +20,39 @@ const (
 	Excused AttendanceStatus = "excused"
 )
 
-// Attendance tracks student attendance for classes
+// IsValid reports whether the status is one of the AttendanceStatus constants
+func (s AttendanceStatus) IsValid() bool {
+	switch s {
+	case Present, Absent, Late, Excused:
+		return true
+	}
+	return false
+}
+
+// Attendance tracks student attendance for classes.
+// A student has at most one record per course, date and period.
 type Attendance struct {
 	Base
-	StudentID uuid.UUID        `json:"student_id" gorm:"type:uuid;not null"`
+	StudentID uuid.UUID        `json:"student_id" gorm:"type:uuid;not null;uniqueIndex:idx_attendances_student_course_date_period,where:deleted_at IS NULL"`
 	Student   Student          `json:"student" gorm:"foreignKey:StudentID"`
-	CourseID  uuid.UUID        `json:"course_id" gorm:"type:uuid;not null"`
+	CourseID  uuid.UUID        `json:"course_id" gorm:"type:uuid;not null;uniqueIndex:idx_attendances_student_course_date_period,where:deleted_at IS NULL"`
 	Course    Course           `json:"course" gorm:"foreignKey:CourseID"`
-	Date      time.Time        `json:"date" gorm:"not null"`
+	Date      time.Time        `json:"date" gorm:"type:date;not null;uniqueIndex:idx_attendances_student_course_date_period,where:deleted_at IS NULL"`
+	Period    int              `json:"period" gorm:"not null;default:0;uniqueIndex:idx_attendances_student_course_date_period,where:deleted_at IS NULL"` // 0 means the whole day
+	MeetingID *uuid.UUID       `json:"meeting_id" gorm:"type:uuid;index"`                                                                                // Section meeting the record is for, when known
+	Meeting   *SectionMeeting  `json:"meeting,omitempty" gorm:"constraint:OnDelete:SET NULL"`
 	Status    AttendanceStatus `json:"status" gorm:"type:varchar(10);not null"`
-	Notes     string           `json:"notes" gorm:"type:text"`
-	CreatedBy uuid.UUID        `json:"created_by" gorm:"type:uuid"`
-	UpdatedBy uuid.UUID        `json:"updated_by" gorm:"type:uuid"`
+	// ArrivalTime and DepartureTime are HH:MM clock times; empty means the student was there from the start or to the end
+	ArrivalTime      string `json:"arrival_time" gorm:"size:5"`
+	DepartureTime    string `json:"departure_time" gorm:"size:5"`
+	ScheduledMinutes int    `json:"scheduled_minutes" gorm:"not null;default:0"` // Instructional minutes of the session
+	MinutesLate      int    `json:"minutes_late" gorm:"not null;default:0"`
+	MinutesLeftEarly int    `json:"minutes_left_early" gorm:"not null;default:0"`
+	Notes            string `json:"notes" gorm:"type:text"`
+	// ExcuseRequestID links an excused absence to the approved excuse request that excused it
+	ExcuseRequestID *uuid.UUID `json:"excuse_request_id" gorm:"type:uuid;index"`
+	CreatedBy       uuid.UUID  `json:"created_by" gorm:"type:uuid"`
+	UpdatedBy       uuid.UUID  `json:"updated_by" gorm:"type:uuid"`
 }
 
 // BeforeCreate - sets created by
@@ -42,3 +66,175 @@ func (a *Attendance) BeforeUpdate(tx *gorm.DB) error {
 	a.UpdatedAt = time.Now()
 	return nil
 }
+
+// Validate checks the student, course, date, period and status of an attendance record
+func (a *Attendance) Validate() error {
+	if a.StudentID == uuid.Nil || a.CourseID == uuid.Nil {
+		return errors.New("student and course are required")
+	}
+	if a.Date.IsZero() {
+		return errors.New("date is required")
+	}
+	if a.Period < 0 {
+		return errors.New("period cannot be negative")
+	}
+	if !a.Status.IsValid() {
+		return errors.New("status must be present, absent, late or excused")
+	}
+	if a.ScheduledMinutes < 0 || a.MinutesLate < 0 || a.MinutesLeftEarly < 0 {
+		return errors.New("minutes cannot be negative")
+	}
+
+	arrival, departure := -1, -1
+	if a.ArrivalTime != "" {
+		minutes, err := ParseClock(a.ArrivalTime)
+		if err != nil {
+			return fmt.Errorf("invalid arrival time: %w", err)
+		}
+		arrival = minutes
+	}
+	if a.DepartureTime != "" {
+		minutes, err := ParseClock(a.DepartureTime)
+		if err != nil {
+			return fmt.Errorf("invalid departure time: %w", err)
+		}
+		departure = minutes
+	}
+	if arrival >= 0 && departure >= 0 && departure <= arrival {
+		return errors.New("departure time must be after arrival time")
+	}
+	return nil
+}
+
+// ApplyMeeting times the record against a section meeting: the session length becomes the scheduled
+// minutes, and the arrival and departure times, when given, set the minutes late and left early
+func (a *Attendance) ApplyMeeting(meeting *SectionMeeting) {
+	start, errStart := ParseClock(meeting.StartTime)
+	end, errEnd := ParseClock(meeting.EndTime)
+	if errStart != nil || errEnd != nil {
+		return
+	}
+	id := meeting.ID
+	a.MeetingID = &id
+	a.ScheduledMinutes = end - start
+
+	if arrival, err := ParseClock(a.ArrivalTime); err == nil {
+		a.MinutesLate = max(0, min(arrival, end)-start)
+	}
+	if departure, err := ParseClock(a.DepartureTime); err == nil {
+		a.MinutesLeftEarly = max(0, end-max(departure, start))
+	}
+}
+
+// MinutesMissed returns the instructional minutes the student missed: the whole session when absent or
+// excused, otherwise the minutes late and left early, never more than the session's length when known
+func (a *Attendance) MinutesMissed() int {
+	if a.Status == Absent || a.Status == Excused {
+		return a.ScheduledMinutes
+	}
+	missed := a.MinutesLate + a.MinutesLeftEarly
+	if a.ScheduledMinutes > 0 && missed > a.ScheduledMinutes {
+		return a.ScheduledMinutes
+	}
+	return missed
+}
+
+// DateOnly returns the calendar date of t as midnight UTC, keeping the day as written in t's own zone
+func DateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RollCallMark is the status given to one student when taking a course's roll
+type RollCallMark struct {
+	StudentID     uuid.UUID        `json:"student_id" binding:"required"`
+	Status        AttendanceStatus `json:"status" binding:"required"`
+	ArrivalTime   string           `json:"arrival_time"`
+	DepartureTime string           `json:"departure_time"`
+	Notes         string           `json:"notes"`
+}
+
+// RollCallEntry is one enrolled student on a course's roll for a date, with their mark once recorded
+type RollCallEntry struct {
+	StudentID        uuid.UUID        `json:"student_id"`
+	FirstName        string           `json:"first_name"`
+	LastName         string           `json:"last_name"`
+	AttendanceID     *uuid.UUID       `json:"attendance_id"`
+	Status           AttendanceStatus `json:"status,omitempty"`
+	ArrivalTime      string           `json:"arrival_time,omitempty"`
+	DepartureTime    string           `json:"departure_time,omitempty"`
+	MinutesLate      int              `json:"minutes_late"`
+	MinutesLeftEarly int              `json:"minutes_left_early"`
+	Notes            string           `json:"notes,omitempty"`
+	Marked           bool             `json:"marked"`
+}
+
+// RollCall is a course's roster for a date and period merged with the attendance already recorded
+type RollCall struct {
+	CourseID uuid.UUID       `json:"course_id"`
+	Date     string          `json:"date"` // YYYY-MM-DD
+	Period   int             `json:"period"`
+	Entries  []RollCallEntry `json:"entries"`
+}
+
+// AttendanceMinutes totals the instructional minutes a student was scheduled for and missed, for one
+// course or across all of them
+type AttendanceMinutes struct {
+	StudentID        *uuid.UUID `json:"student_id,omitempty"`
+	FirstName        string     `json:"first_name,omitempty"`
+	LastName         string     `json:"last_name,omitempty"`
+	CourseID         *uuid.UUID `json:"course_id,omitempty"`
+	CourseCode       string     `json:"course_code,omitempty"`
+	Sessions         int        `json:"sessions"`
+	ScheduledMinutes int        `json:"scheduled_minutes"`
+	MinutesLate      int        `json:"minutes_late"`
+	MinutesLeftEarly int        `json:"minutes_left_early"`
+	MinutesAbsent    int        `json:"minutes_absent"`
+	MinutesExcused   int        `json:"minutes_excused"`
+	MinutesMissed    int        `json:"minutes_missed"`
+	// UntimedSessions counts sessions without a known length, whose absences cannot be turned into minutes
+	UntimedSessions int     `json:"untimed_sessions"`
+	PercentAttended float64 `json:"percent_attended"` // Share of scheduled minutes attended
+}
+
+// Add counts an attendance record into the totals
+func (m *AttendanceMinutes) Add(attendance *Attendance) {
+	m.Sessions++
+	m.ScheduledMinutes += attendance.ScheduledMinutes
+	if attendance.ScheduledMinutes == 0 {
+		m.UntimedSessions++
+	}
+
+	missed := attendance.MinutesMissed()
+	switch attendance.Status {
+	case Absent:
+		m.MinutesAbsent += missed
+	case Excused:
+		m.MinutesExcused += missed
+	default:
+		late := min(attendance.MinutesLate, missed)
+		m.MinutesLate += late
+		m.MinutesLeftEarly += missed - late
+	}
+	m.MinutesMissed += missed
+
+	if m.ScheduledMinutes > 0 {
+		attended := max(0, m.ScheduledMinutes-m.MinutesMissed)
+		m.PercentAttended = math.Round(float64(attended)/float64(m.ScheduledMinutes)*1000) / 10
+	}
+}
+
+// StudentMinutesReport is a student's instructional minutes missed per course with their overall totals
+type StudentMinutesReport struct {
+	StudentID uuid.UUID           `json:"student_id"`
+	Term      string              `json:"term,omitempty"`
+	Courses   []AttendanceMinutes `json:"courses"`
+	Total     AttendanceMinutes   `json:"total"`
+}
+
+// CourseMinutesReport is the instructional minutes missed by each student on a course's roster
+type CourseMinutesReport struct {
+	CourseID uuid.UUID           `json:"course_id"`
+	Term     string              `json:"term,omitempty"`
+	Students []AttendanceMinutes `json:"students"`
+	Total    AttendanceMinutes   `json:"total"`
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarDayType classifies a date that differs from a calendar's weekly pattern
+type CalendarDayType string
+
+const (
+	InstructionalDay CalendarDayType = "instructional" // Class on a day that normally has none, e.g. a make-up Saturday
+	Holiday          CalendarDayType = "holiday"
+	HalfDay          CalendarDayType = "half_day"
+)
+
+// IsValid reports whether the day type is one of the CalendarDayType constants
+func (t CalendarDayType) IsValid() bool {
+	switch t {
+	case InstructionalDay, Holiday, HalfDay:
+		return true
+	}
+	return false
+}
+
+// SchoolCalendar records which days of a term have class. Every date of the term on one of the
+// calendar's weekdays is an instructional day unless one of its Days says otherwise.
+type SchoolCalendar struct {
+	Base
+	TermID   uuid.UUID      `json:"term_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Weekdays []time.Weekday `json:"weekdays" gorm:"serializer:json"` // 0 = Sunday ... 6 = Saturday
+	Days     []CalendarDay  `json:"days" gorm:"foreignKey:CalendarID"`
+}
+
+// CalendarDay is an exception to a calendar's weekly pattern on one date
+type CalendarDay struct {
+	Base
+	CalendarID uuid.UUID       `json:"calendar_id" gorm:"type:uuid;not null;uniqueIndex:idx_calendar_days_calendar_date"`
+	Date       time.Time       `json:"date" gorm:"type:date;not null;uniqueIndex:idx_calendar_days_calendar_date"`
+	Type       CalendarDayType `json:"type" gorm:"size:20;not null"`
+	Name       string          `json:"name" gorm:"size:100"`
+	// DismissalTime is when classes end on a half day, HH:MM; meetings starting at or after it do not take place
+	DismissalTime string `json:"dismissal_time,omitempty" gorm:"size:5"`
+}
+
+// ExpectedSession is a section meeting that a school calendar says took place on a date
+type ExpectedSession struct {
+	SectionID   uuid.UUID `json:"section_id"`
+	SectionCode string    `json:"section_code"`
+	CourseID    uuid.UUID `json:"course_id"`
+	CourseCode  string    `json:"course_code,omitempty"`
+	MeetingID   uuid.UUID `json:"meeting_id"`
+	Date        string    `json:"date"`   // YYYY-MM-DD
+	Period      int       `json:"period"` // The meeting's place among the day's meetings, 0 when it is the only one
+	StartTime   string    `json:"start_time"`
+	EndTime     string    `json:"end_time"`
+}
+
+// AttendanceRate compares the attendance recorded for a student with the sessions the school calendar
+// says they should have had, for one course or across all of them
+type AttendanceRate struct {
+	StudentID        *uuid.UUID `json:"student_id,omitempty"`
+	FirstName        string     `json:"first_name,omitempty"`
+	LastName         string     `json:"last_name,omitempty"`
+	CourseID         *uuid.UUID `json:"course_id,omitempty"`
+	CourseCode       string     `json:"course_code,omitempty"`
+	ExpectedSessions int        `json:"expected_sessions"`
+	Present          int        `json:"present"`
+	Late             int        `json:"late"`
+	Absent           int        `json:"absent"`
+	Excused          int        `json:"excused"`
+	Unmarked         int        `json:"unmarked"`    // Expected sessions with no attendance recorded
+	Rate             float64    `json:"rate"`        // Percentage of expected sessions attended, late included
+	MarkedRate       float64    `json:"marked_rate"` // Percentage of expected sessions with attendance recorded
+}
+
+// DefaultSchoolCalendar returns the calendar used for a term that has none: class every weekday
+func DefaultSchoolCalendar(termID uuid.UUID) *SchoolCalendar {
+	return &SchoolCalendar{
+		TermID:   termID,
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Days:     []CalendarDay{},
+	}
+}
+
+// Validate checks the calendar's weekdays and that its days are distinct dates within the term
+func (c *SchoolCalendar) Validate(term *Term) error {
+	seen := make(map[time.Weekday]bool)
+	for _, day := range c.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return errors.New("weekdays must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if seen[day] {
+			return fmt.Errorf("weekday %s is listed more than once", day)
+		}
+		seen[day] = true
+	}
+
+	dates := make(map[string]bool)
+	for i := range c.Days {
+		day := &c.Days[i]
+		key := day.Date.Format("2006-01-02")
+		if !day.Type.IsValid() {
+			return fmt.Errorf("%s: type must be instructional, holiday or half_day", key)
+		}
+		if !term.Contains(day.Date) {
+			return fmt.Errorf("%s is outside the term", key)
+		}
+		if dates[key] {
+			return fmt.Errorf("%s is listed more than once", key)
+		}
+		dates[key] = true
+
+		if day.Type != HalfDay {
+			day.DismissalTime = ""
+		} else if day.DismissalTime != "" {
+			if _, err := ParseClock(day.DismissalTime); err != nil {
+				return fmt.Errorf("%s: invalid dismissal time: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// day returns the calendar's exception for a date, or nil when the weekly pattern applies
+func (c *SchoolCalendar) day(date time.Time) *CalendarDay {
+	key := date.Format("2006-01-02")
+	for i := range c.Days {
+		if c.Days[i].Date.Format("2006-01-02") == key {
+			return &c.Days[i]
+		}
+	}
+	return nil
+}
+
+// IsInstructional reports whether a date has class, including half days
+func (c *SchoolCalendar) IsInstructional(date time.Time) bool {
+	if day := c.day(date); day != nil {
+		return day.Type != Holiday
+	}
+	for _, weekday := range c.Weekdays {
+		if weekday == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// Holds reports whether a section meeting takes place on a date: the date has class, falls on the
+// meeting's weekday, and on a half day the meeting starts before dismissal
+func (c *SchoolCalendar) Holds(meeting *SectionMeeting, date time.Time) bool {
+	if meeting.Day != date.Weekday() || !c.IsInstructional(date) {
+		return false
+	}
+	day := c.day(date)
+	if day == nil || day.Type != HalfDay || day.DismissalTime == "" {
+		return true
+	}
+	start, errStart := ParseClock(meeting.StartTime)
+	dismissal, errDismissal := ParseClock(day.DismissalTime)
+	return errStart != nil || errDismissal != nil || start < dismissal
+}
+
+// Sessions lists the meetings of a section the calendar holds between two dates, inclusive
+func (c *SchoolCalendar) Sessions(section *Section, from, to time.Time) []ExpectedSession {
+	var sessions []ExpectedSession
+	for date := DateOnly(from); !date.After(DateOnly(to)); date = date.AddDate(0, 0, 1) {
+		daily := 0
+		for i := range section.Meetings {
+			if section.Meetings[i].Day == date.Weekday() {
+				daily++
+			}
+		}
+		if daily == 0 || !c.IsInstructional(date) {
+			continue
+		}
+
+		for period := 1; period <= daily; period++ {
+			meeting := section.MeetingOn(date.Weekday(), period)
+			if !c.Holds(meeting, date) {
+				continue
+			}
+			session := ExpectedSession{
+				SectionID:   section.ID,
+				SectionCode: section.Code,
+				CourseID:    section.CourseID,
+				MeetingID:   meeting.ID,
+				Date:        date.Format("2006-01-02"),
+				StartTime:   meeting.StartTime,
+				EndTime:     meeting.EndTime,
+			}
+			if daily > 1 {
+				session.Period = period
+			}
+			if section.Course != nil {
+				session.CourseCode = section.Course.Code
+			}
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// CoveredBy reports whether an attendance record marks the session: one recorded for its meeting, for
+// its period, or for the whole day
+func (s *ExpectedSession) CoveredBy(attendance *Attendance) bool {
+	if attendance.CourseID != s.CourseID || attendance.Date.Format("2006-01-02") != s.Date {
+		return false
+	}
+	if attendance.MeetingID != nil {
+		return *attendance.MeetingID == s.MeetingID
+	}
+	if attendance.Period == 0 {
+		return true
+	}
+	// A day's only meeting is also its first period
+	return attendance.Period == max(s.Period, 1)
+}
+
+// Count records the outcome of one expected session, nil when no attendance was taken
+func (r *AttendanceRate) Count(attendance *Attendance) {
+	r.ExpectedSessions++
+	if attendance == nil {
+		r.Unmarked++
+	} else {
+		switch attendance.Status {
+		case Present:
+			r.Present++
+		case Late:
+			r.Late++
+		case Absent:
+			r.Absent++
+		case Excused:
+			r.Excused++
+		}
+	}
+	r.updateRates()
+}
+
+// Merge adds the sessions counted in another rate, e.g. to total a student's courses
+func (r *AttendanceRate) Merge(other *AttendanceRate) {
+	r.ExpectedSessions += other.ExpectedSessions
+	r.Present += other.Present
+	r.Late += other.Late
+	r.Absent += other.Absent
+	r.Excused += other.Excused
+	r.Unmarked += other.Unmarked
+	r.updateRates()
+}
+
+// updateRates recomputes the percentages from the session counts
+func (r *AttendanceRate) updateRates() {
+	if r.ExpectedSessions == 0 {
+		return
+	}
+	r.Rate = math.Round(float64(r.Present+r.Late)/float64(r.ExpectedSessions)*1000) / 10
+	r.MarkedRate = math.Round(float64(r.ExpectedSessions-r.Unmarked)/float64(r.ExpectedSessions)*1000) / 10
+}
+
+// StudentAttendanceRates is a student's attendance rate in each course of a term and overall
+type StudentAttendanceRates struct {
+	StudentID uuid.UUID        `json:"student_id"`
+	Term      string           `json:"term"`
+	Through   string           `json:"through"` // Last date counted, YYYY-MM-DD
+	Courses   []AttendanceRate `json:"courses"`
+	Total     AttendanceRate   `json:"total"`
+}
+
+// CourseAttendanceRates is the attendance rate of each student in a course in a term and overall
+type CourseAttendanceRates struct {
+	CourseID uuid.UUID        `json:"course_id"`
+	Term     string           `json:"term"`
+	Through  string           `json:"through"` // Last date counted, YYYY-MM-DD
+	Students []AttendanceRate `json:"students"`
+	Total    AttendanceRate   `json:"total"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckIn is a student's self-service check-in to a section meeting with a signed session token.
+// A student checks in once per session, so a token cannot be replayed to check in again.
+type CheckIn struct {
+	Base
+	TokenID      string     `json:"token_id" gorm:"size:36;not null;index"` // ID of the session token scanned
+	StudentID    uuid.UUID  `json:"student_id" gorm:"type:uuid;not null;uniqueIndex:idx_check_ins_student_session"`
+	MeetingID    uuid.UUID  `json:"meeting_id" gorm:"type:uuid;not null;uniqueIndex:idx_check_ins_student_session"`
+	Date         time.Time  `json:"date" gorm:"type:date;not null;uniqueIndex:idx_check_ins_student_session"`
+	CourseID     uuid.UUID  `json:"course_id" gorm:"type:uuid;not null"`
+	AttendanceID *uuid.UUID `json:"attendance_id" gorm:"type:uuid"`
+	CheckedInAt  time.Time  `json:"checked_in_at"`
+	IPAddress    string     `json:"ip_address" gorm:"size:45"`
+}
+
+// CheckInSession is a signed token students scan to check in to one section meeting on one date
+type CheckInSession struct {
+	Token     string    `json:"token"`
+	TokenID   string    `json:"token_id"`
+	SectionID uuid.UUID `json:"section_id"`
+	CourseID  uuid.UUID `json:"course_id"`
+	MeetingID uuid.UUID `json:"meeting_id"`
+	Date      time.Time `json:"date"`
+	StartTime string    `json:"start_time"`
+	EndTime   string    `json:"end_time"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CheckInResult is the outcome of a student's check-in
+type CheckInResult struct {
+	CheckIn    CheckIn     `json:"check_in"`
+	Attendance *Attendance `json:"attendance"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnrollmentStatus is the outcome of an enrollment request
+type EnrollmentStatus string
+
+const (
+	EnrollmentEnrolled   EnrollmentStatus = "enrolled"
+	EnrollmentWaitlisted EnrollmentStatus = "waitlisted"
+)
+
+// StudentCourse is the student_courses join table, extended with the term of the student's latest
+// enrollment in the course. Enrollments in every term are kept as CourseEnrollments.
+type StudentCourse struct {
+	StudentID uuid.UUID  `json:"student_id" gorm:"type:uuid;primaryKey"`
+	CourseID  uuid.UUID  `json:"course_id" gorm:"type:uuid;primaryKey"`
+	TermID    *uuid.UUID `json:"term_id" gorm:"type:uuid;index"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CourseEnrollment records that a student was enrolled in a course in a term, so retaking a course in a
+// later term keeps the earlier enrollment
+type CourseEnrollment struct {
+	StudentID uuid.UUID `json:"student_id" gorm:"type:uuid;primaryKey"`
+	CourseID  uuid.UUID `json:"course_id" gorm:"type:uuid;primaryKey"`
+	TermID    uuid.UUID `json:"term_id" gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WaitlistEntry is a student waiting for a seat in a full section, served in CreatedAt order
+type WaitlistEntry struct {
+	SectionID uuid.UUID `json:"section_id" gorm:"type:uuid;primaryKey"`
+	StudentID uuid.UUID `json:"student_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	Section   *Section  `json:"section,omitempty"`
+	Position  int       `json:"position" gorm:"-"`
+}
+
+// EnrollmentResult reports whether a student got a seat or joined the waitlist
+type EnrollmentResult struct {
+	SectionID uuid.UUID        `json:"section_id"`
+	StudentID uuid.UUID        `json:"student_id"`
+	Status    EnrollmentStatus `json:"status"`
+	Position  int              `json:"position,omitempty"` // Waitlist position, 1 is next in line
+}
+
+// SeatCount summarizes the seats of a section
+type SeatCount struct {
+	SectionID  uuid.UUID `json:"section_id"`
+	Capacity   int       `json:"capacity"` // 0 means no limit
+	Enrolled   int       `json:"enrolled"`
+	Available  *int      `json:"available"` // nil when the section has no limit
+	Waitlisted int       `json:"waitlisted"`
+}
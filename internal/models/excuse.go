@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExcuseStatus is where an absence excuse request is in staff review
+type ExcuseStatus string
+
+const (
+	ExcusePending  ExcuseStatus = "pending"
+	ExcuseApproved ExcuseStatus = "approved"
+	ExcuseRejected ExcuseStatus = "rejected"
+)
+
+// ExcuseRequest asks staff to excuse a student's absences over a range of dates. Approved requests are
+// kept as the record of why the absences they converted were excused.
+type ExcuseRequest struct {
+	Base
+	StudentID   uuid.UUID `json:"student_id" gorm:"type:uuid;not null;index"`
+	Student     *Student  `json:"student,omitempty"`
+	SubmittedBy uuid.UUID `json:"submitted_by" gorm:"type:uuid;not null;index"`
+	FromDate    time.Time `json:"from_date" gorm:"type:date;not null"`
+	ToDate      time.Time `json:"to_date" gorm:"type:date;not null"`
+	Reason      string    `json:"reason" gorm:"type:text;not null"`
+	// Supporting document, such as a doctor's note, stored under the upload directory
+	DocumentName string       `json:"document_name" gorm:"size:255"`
+	DocumentType string       `json:"document_type" gorm:"size:100"`
+	DocumentPath string       `json:"-" gorm:"size:500"`
+	Status       ExcuseStatus `json:"status" gorm:"size:20;not null;index"`
+	ReviewedBy   *uuid.UUID   `json:"reviewed_by" gorm:"type:uuid"`
+	ReviewedAt   *time.Time   `json:"reviewed_at"`
+	ReviewNotes  string       `json:"review_notes" gorm:"type:text"`
+	ExcusedCount int          `json:"excused_count"` // Absences converted to excused when the request was approved
+}
+
+// IsValid reports whether the status is one of the ExcuseStatus constants
+func (s ExcuseStatus) IsValid() bool {
+	switch s {
+	case ExcusePending, ExcuseApproved, ExcuseRejected:
+		return true
+	}
+	return false
+}
+
+// Validate normalises the request's dates and checks the student, date range and reason
+func (r *ExcuseRequest) Validate() error {
+	if r.StudentID == uuid.Nil {
+		return errors.New("student is required")
+	}
+	if r.FromDate.IsZero() {
+		return errors.New("from date is required")
+	}
+	r.FromDate = DateOnly(r.FromDate)
+	if r.ToDate.IsZero() {
+		r.ToDate = r.FromDate
+	}
+	r.ToDate = DateOnly(r.ToDate)
+	if r.ToDate.Before(r.FromDate) {
+		return errors.New("to date must not be before from date")
+	}
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+// Covers reports whether a date falls within the request's date range
+func (r *ExcuseRequest) Covers(date time.Time) bool {
+	date = DateOnly(date)
+	return !date.Before(r.FromDate) && !date.After(r.ToDate)
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// GPAScope selects which grades contribute to a GPA calculation
+type GPAScope string
+
+const (
+	GPAScopeCumulative GPAScope = "cumulative"
+	GPAScopeTerm       GPAScope = "term"
+	GPAScopeDepartment GPAScope = "department"
+)
+
+// RetakePolicy decides which attempt counts when a course was taken more than once
+type RetakePolicy string
+
+const (
+	RetakeLatest  RetakePolicy = "latest"
+	RetakeHighest RetakePolicy = "highest"
+	RetakeAverage RetakePolicy = "average"
+)
+
+// IsValid reports whether the retake policy is one of the supported policies
+func (p RetakePolicy) IsValid() bool {
+	switch p {
+	case RetakeLatest, RetakeHighest, RetakeAverage:
+		return true
+	}
+	return false
+}
+
+// GPAQuery describes a GPA calculation request
+type GPAQuery struct {
+	Scope        GPAScope     `json:"scope"`
+	Term         string       `json:"term,omitempty"`
+	Department   string       `json:"department,omitempty"`
+	RetakePolicy RetakePolicy `json:"retake_policy"`
+}
+
+// GPACourse is a single course's contribution to a GPA
+type GPACourse struct {
+	CourseID        uuid.UUID `json:"course_id"`
+	Code            string    `json:"code"`
+	Name            string    `json:"name"`
+	Department      string    `json:"department"`
+	Credits         int       `json:"credits"`
+	Term            string    `json:"term"`
+	Grade           string    `json:"grade"`
+	GradePoints     float64   `json:"grade_points"`
+	Attempts        int       `json:"attempts"`
+	CountsTowardGPA bool      `json:"counts_toward_gpa"` // false for pass/fail courses
+}
+
+// GPAResult is the API response structure for a GPA calculation
+type GPAResult struct {
+	StudentID     uuid.UUID    `json:"student_id"`
+	Scope         GPAScope     `json:"scope"`
+	Term          string       `json:"term,omitempty"`
+	Department    string       `json:"department,omitempty"`
+	RetakePolicy  RetakePolicy `json:"retake_policy"`
+	GPA           float64      `json:"gpa"`
+	TotalCredits  int          `json:"total_credits"`
+	QualityPoints float64      `json:"quality_points"`
+	Courses       []GPACourse  `json:"courses"`
+}
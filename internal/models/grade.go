@@ -33,34 +33,9 @@ func (g *Grade) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
-// Calculate the letter grade based on score
-func (g *Grade) CalculateGrade() {
-	switch {
-	case g.Score >= 97:
-		g.Grade = "A+"
-	case g.Score >= 93:
-		g.Grade = "A"
-	case g.Score >= 90:
-		g.Grade = "A-"
-	case g.Score >= 87:
-		g.Grade = "B+"
-	case g.Score >= 83:
-		g.Grade = "B"
-	case g.Score >= 80:
-		g.Grade = "B-"
-	case g.Score >= 77:
-		g.Grade = "C+"
-	case g.Score >= 73:
-		g.Grade = "C"
-	case g.Score >= 70:
-		g.Grade = "C-"
-	case g.Score >= 67:
-		g.Grade = "D+"
-	case g.Score >= 63:
-		g.Grade = "D"
-	case g.Score >= 60:
-		g.Grade = "D-"
-	default:
-		g.Grade = "F"
+// CalculateGrade sets the letter grade from the score using the given grading scale
+func (g *Grade) CalculateGrade(scale *GradingScale) {
+	if band := scale.BandForScore(g.Score); band != nil {
+		g.Grade = band.Letter
 	}
 }
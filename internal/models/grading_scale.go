@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// GradingScale defines how scores map to letter grades and grade points
+type GradingScale struct {
+	Base
+	Name        string        `json:"name" gorm:"uniqueIndex:idx_grading_scales_active_name,where:deleted_at IS NULL"`
+	Description string        `json:"description"`
+	PassFail    bool          `json:"pass_fail"` // Pass/fail scales do not count toward GPA
+	IsDefault   bool          `json:"is_default"`
+	Bands       []GradingBand `json:"bands" gorm:"foreignKey:ScaleID;constraint:OnDelete:CASCADE"`
+}
+
+// GradingBand is a single score band within a grading scale
+type GradingBand struct {
+	Base
+	ScaleID     uuid.UUID `json:"scale_id" gorm:"type:uuid;not null;index"`
+	MinScore    float64   `json:"min_score" gorm:"not null"`
+	Letter      string    `json:"letter" gorm:"size:2;not null"`
+	GradePoints float64   `json:"grade_points"`
+	Passing     bool      `json:"passing"`
+}
+
+// GradingScaleAssignment assigns a grading scale to a course, department and/or term
+type GradingScaleAssignment struct {
+	Base
+	ScaleID    uuid.UUID  `json:"scale_id" gorm:"type:uuid;not null;index"`
+	CourseID   *uuid.UUID `json:"course_id,omitempty" gorm:"type:uuid;index"`
+	Department string     `json:"department,omitempty"`
+	Term       string     `json:"term,omitempty" gorm:"size:20"`
+}
+
+// Matches reports whether the assignment applies to the given course and term
+func (a *GradingScaleAssignment) Matches(course *Course, term string) bool {
+	if a.CourseID != nil && (course == nil || *a.CourseID != course.ID) {
+		return false
+	}
+	if a.Department != "" && (course == nil || a.Department != course.Department) {
+		return false
+	}
+	if a.Term != "" && a.Term != term {
+		return false
+	}
+	return true
+}
+
+// Specificity ranks assignments so that course beats department and department beats term
+func (a *GradingScaleAssignment) Specificity() int {
+	specificity := 0
+	if a.CourseID != nil {
+		specificity += 4
+	}
+	if a.Department != "" {
+		specificity += 2
+	}
+	if a.Term != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// Validate checks that the scale covers every score and has unique letters
+func (s *GradingScale) Validate() error {
+	if s.Name == "" {
+		return errors.New("grading scale name is required")
+	}
+	if len(s.Bands) == 0 {
+		return errors.New("grading scale must have at least one band")
+	}
+
+	letters := make(map[string]bool)
+	coversZero := false
+	for _, band := range s.Bands {
+		if band.Letter == "" || len(band.Letter) > 2 {
+			return errors.New("band letters must be one or two characters")
+		}
+		if letters[band.Letter] {
+			return errors.New("band letters must be unique within a scale")
+		}
+		letters[band.Letter] = true
+		if band.MinScore <= 0 {
+			coversZero = true
+		}
+	}
+	if !coversZero {
+		return errors.New("grading scale must have a band starting at 0")
+	}
+
+	return nil
+}
+
+// SortBands orders bands from the highest minimum score to the lowest
+func (s *GradingScale) SortBands() {
+	sort.SliceStable(s.Bands, func(i, j int) bool {
+		return s.Bands[i].MinScore > s.Bands[j].MinScore
+	})
+}
+
+// BandForScore returns the band a score falls into, the one with the highest minimum score the score
+// reaches. It does not reorder the bands, so scales shared between goroutines can be read concurrently.
+func (s *GradingScale) BandForScore(score float64) *GradingBand {
+	var band *GradingBand
+	for i := range s.Bands {
+		if score >= s.Bands[i].MinScore && (band == nil || s.Bands[i].MinScore > band.MinScore) {
+			band = &s.Bands[i]
+		}
+	}
+	return band
+}
+
+// BandForLetter returns the band with the given letter
+func (s *GradingScale) BandForLetter(letter string) *GradingBand {
+	for i := range s.Bands {
+		if s.Bands[i].Letter == letter {
+			return &s.Bands[i]
+		}
+	}
+	return nil
+}
+
+// DefaultGradingScale returns the standard US 4.0 scale used when no scale is configured
+func DefaultGradingScale() *GradingScale {
+	return &GradingScale{
+		Name:        "Standard 4.0",
+		Description: "US letter grades on a 4.0 scale",
+		IsDefault:   true,
+		Bands: []GradingBand{
+			{MinScore: 97, Letter: "A+", GradePoints: 4.0, Passing: true},
+			{MinScore: 93, Letter: "A", GradePoints: 4.0, Passing: true},
+			{MinScore: 90, Letter: "A-", GradePoints: 3.7, Passing: true},
+			{MinScore: 87, Letter: "B+", GradePoints: 3.3, Passing: true},
+			{MinScore: 83, Letter: "B", GradePoints: 3.0, Passing: true},
+			{MinScore: 80, Letter: "B-", GradePoints: 2.7, Passing: true},
+			{MinScore: 77, Letter: "C+", GradePoints: 2.3, Passing: true},
+			{MinScore: 73, Letter: "C", GradePoints: 2.0, Passing: true},
+			{MinScore: 70, Letter: "C-", GradePoints: 1.7, Passing: true},
+			{MinScore: 67, Letter: "D+", GradePoints: 1.3, Passing: true},
+			{MinScore: 63, Letter: "D", GradePoints: 1.0, Passing: true},
+			{MinScore: 60, Letter: "D-", GradePoints: 0.7, Passing: true},
+			{MinScore: 0, Letter: "F", GradePoints: 0, Passing: false},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Relationship is how a guardian is related to a student
+type Relationship string
+
+// Relationships a guardian can have to a student
+const (
+	RelationshipMother        Relationship = "mother"
+	RelationshipFather        Relationship = "father"
+	RelationshipParent        Relationship = "parent"
+	RelationshipStepParent    Relationship = "step_parent"
+	RelationshipGrandparent   Relationship = "grandparent"
+	RelationshipFosterParent  Relationship = "foster_parent"
+	RelationshipLegalGuardian Relationship = "legal_guardian"
+	RelationshipRelative      Relationship = "relative"
+	RelationshipOther         Relationship = "other"
+)
+
+// IsValid reports whether r is a known relationship
+func (r Relationship) IsValid() bool {
+	switch r {
+	case RelationshipMother, RelationshipFather, RelationshipParent, RelationshipStepParent, RelationshipGrandparent,
+		RelationshipFosterParent, RelationshipLegalGuardian, RelationshipRelative, RelationshipOther:
+		return true
+	}
+	return false
+}
+
+// ContactMethod is how a guardian prefers to be contacted
+type ContactMethod string
+
+// Contact methods a guardian can prefer
+const (
+	ContactEmail ContactMethod = "email"
+	ContactPhone ContactMethod = "phone"
+	ContactSMS   ContactMethod = "sms"
+)
+
+// IsValid reports whether m is a known contact method
+func (m ContactMethod) IsValid() bool {
+	switch m {
+	case ContactEmail, ContactPhone, ContactSMS:
+		return true
+	}
+	return false
+}
+
+// Guardian represents a parent or other adult responsible for one or more students
+type Guardian struct {
+	Base
+	FirstName         string            `json:"first_name" gorm:"not null"`
+	LastName          string            `json:"last_name" gorm:"not null"`
+	Email             string            `json:"email" gorm:"index"`
+	Phone             string            `json:"phone"`
+	AlternatePhone    string            `json:"alternate_phone,omitempty"`
+	Address           string            `json:"address"`
+	PreferredContact  ContactMethod     `json:"preferred_contact" gorm:"size:10"`                // Defaults to email
+	PreferredLanguage string            `json:"preferred_language,omitempty" gorm:"size:35"`     // Language to write to them in
+	Students          []StudentGuardian `json:"students,omitempty" gorm:"foreignKey:GuardianID"` // Students they are a guardian of
+	Account           *AccountRequest   `json:"account,omitempty" gorm:"-"`                      // Login to provision when the guardian is created
+}
+
+// StudentGuardian links a guardian to a student, recording how they are related and what they may do
+type StudentGuardian struct {
+	StudentID        uuid.UUID    `json:"student_id" gorm:"type:uuid;primaryKey"`
+	Student          *Student     `json:"student,omitempty" gorm:"foreignKey:StudentID"`
+	GuardianID       uuid.UUID    `json:"guardian_id" gorm:"type:uuid;primaryKey"`
+	Guardian         *Guardian    `json:"guardian,omitempty" gorm:"foreignKey:GuardianID"`
+	Relationship     Relationship `json:"relationship" gorm:"size:20;not null"`
+	HasCustody       bool         `json:"has_custody"`
+	CanPickUp        bool         `json:"can_pick_up"`       // May collect the student from school
+	EmergencyContact bool         `json:"emergency_contact"` // Called in an emergency
+	PrimaryContact   bool         `json:"primary_contact"`   // Contacted first
+	RecordsWithheld  bool         `json:"records_withheld"`  // Cannot see the student's records, e.g. by court order
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// Validate checks a guardian's name and contact preferences, defaulting to contact by email
+func (g *Guardian) Validate() error {
+	if g.FirstName == "" || g.LastName == "" {
+		return errors.New("first and last name are required")
+	}
+	if g.PreferredContact == "" {
+		g.PreferredContact = ContactEmail
+	}
+	if !g.PreferredContact.IsValid() {
+		return errors.New("preferred contact must be email, phone or sms")
+	}
+	if g.PreferredContact == ContactEmail && g.Email == "" {
+		return errors.New("an email address is required to be contacted by email")
+	}
+	if g.PreferredContact != ContactEmail && g.Phone == "" {
+		return errors.New("a phone number is required to be contacted by phone or sms")
+	}
+	return nil
+}
+
+// Validate checks a guardian's relationship to a student
+func (l *StudentGuardian) Validate() error {
+	if !l.Relationship.IsValid() {
+		return errors.New("relationship must be mother, father, parent, step_parent, grandparent, foster_parent, legal_guardian, relative or other")
+	}
+	return nil
+}
+
+// GuardianChild is a student as seen by one of their guardians in the portal
+type GuardianChild struct {
+	StudentID    uuid.UUID    `json:"student_id"`
+	FirstName    string       `json:"first_name"`
+	LastName     string       `json:"last_name"`
+	GradeLevel   string       `json:"grade_level"`
+	Relationship Relationship `json:"relationship"`
+	HasCustody   bool         `json:"has_custody"`
+	CanPickUp    bool         `json:"can_pick_up"`
+}
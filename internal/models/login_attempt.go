@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginStep is the step of logging in an attempt was made at
+type LoginStep string
+
+// Steps of logging in
+const (
+	LoginStepPassword  LoginStep = "password"
+	LoginStepTwoFactor LoginStep = "two_factor"
+)
+
+// LoginOutcome is how an attempt to log in ended
+type LoginOutcome string
+
+// Outcomes of login attempts
+const (
+	LoginSucceeded LoginOutcome = "succeeded"
+	LoginFailed    LoginOutcome = "failed"
+	LoginThrottled LoginOutcome = "throttled" // Refused without checking the credentials, after too many failures
+)
+
+// LoginAttempt records an attempt to log in to a user's account
+type LoginAttempt struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;index"`
+	Step      LoginStep    `json:"step" gorm:"size:20;not null"`
+	Outcome   LoginOutcome `json:"outcome" gorm:"size:20;not null"`
+	IPAddress string       `json:"ip_address" gorm:"size:45"`
+	CreatedAt time.Time    `json:"created_at" gorm:"index"`
+}
+
+// LoginThrottle counts the recent login attempts of a username or an IP address, shared by every
+// replica of the API when logins are throttled in the database
+type LoginThrottle struct {
+	Key           string    `gorm:"size:320;primaryKey"`
+	Failures      int       `gorm:"not null"`
+	LastAttemptAt time.Time `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"not null;index"` // When the failures are forgotten
+}
+
+// LoginLockout is whether logging in to a user's account is held back after failed attempts
+type LoginLockout struct {
+	FailedAttempts int        `json:"failed_attempts"`
+	Locked         bool       `json:"locked"`
+	BlockedUntil   *time.Time `json:"blocked_until"`
+}
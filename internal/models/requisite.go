@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RequisiteKind distinguishes courses that must be completed beforehand from courses that may be taken alongside
+type RequisiteKind string
+
+const (
+	RequisitePrerequisite RequisiteKind = "prerequisite"
+	RequisiteCorequisite  RequisiteKind = "corequisite"
+)
+
+// IsValid reports whether the kind is supported
+func (k RequisiteKind) IsValid() bool {
+	return k == RequisitePrerequisite || k == RequisiteCorequisite
+}
+
+// RequisiteGroup is one requirement of a course. A student meets a course's rules when every
+// group is satisfied, and a group is satisfied by any one of its options.
+type RequisiteGroup struct {
+	Base
+	CourseID uuid.UUID         `json:"course_id" gorm:"type:uuid;not null;index"`
+	Kind     RequisiteKind     `json:"kind" gorm:"size:20;not null"`
+	Options  []RequisiteOption `json:"options" gorm:"foreignKey:GroupID"`
+}
+
+// RequisiteOption is a course that satisfies a requisite group, with an optional minimum letter grade
+type RequisiteOption struct {
+	Base
+	GroupID          uuid.UUID `json:"group_id" gorm:"type:uuid;not null;index"`
+	RequiredCourseID uuid.UUID `json:"required_course_id" gorm:"type:uuid;not null;index"`
+	RequiredCourse   *Course   `json:"required_course,omitempty"`
+	MinGrade         string    `json:"min_grade" gorm:"size:2"` // Empty means any passing grade
+}
+
+// RequisiteOverride records an administrator letting a student enroll without meeting a course's requisites
+type RequisiteOverride struct {
+	Base
+	StudentID uuid.UUID `json:"student_id" gorm:"type:uuid;not null;index"`
+	CourseID  uuid.UUID `json:"course_id" gorm:"type:uuid;not null;index"`
+	SectionID uuid.UUID `json:"section_id" gorm:"type:uuid;not null"`
+	Reason    string    `json:"reason" gorm:"not null"`
+	GrantedBy uuid.UUID `json:"granted_by" gorm:"type:uuid"`
+	Unmet     []string  `json:"unmet" gorm:"serializer:json"`
+}
+
+// UnmetRequisite describes a requisite group a student does not satisfy
+type UnmetRequisite struct {
+	GroupID uuid.UUID     `json:"group_id"`
+	Kind    RequisiteKind `json:"kind"`
+	Options []string      `json:"options"` // e.g. "MATH101 (min C)"
+}
+
+// String describes the unmet group, e.g. "prerequisite: one of MATH101 (min C), MATH105"
+func (u UnmetRequisite) String() string {
+	desc := fmt.Sprintf("%s: ", u.Kind)
+	if len(u.Options) > 1 {
+		desc += "one of "
+	}
+	for i, option := range u.Options {
+		if i > 0 {
+			desc += ", "
+		}
+		desc += option
+	}
+	return desc
+}
+
+// Validate checks that the group has a kind and at least one option that is not the course itself
+func (g *RequisiteGroup) Validate() error {
+	if !g.Kind.IsValid() {
+		return fmt.Errorf("unknown requisite kind %q", g.Kind)
+	}
+	if len(g.Options) == 0 {
+		return errors.New("a requisite group needs at least one option")
+	}
+	for _, option := range g.Options {
+		if option.RequiredCourseID == g.CourseID {
+			return errors.New("a course cannot be its own requisite")
+		}
+	}
+	return nil
+}
+
+// Validate checks that the override explains why the requisites were waived
+func (o *RequisiteOverride) Validate() error {
+	if strings.TrimSpace(o.Reason) == "" {
+		return errors.New("a reason is required to override course requisites")
+	}
+	return nil
+}
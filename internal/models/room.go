@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomType is the kind of space a room provides
+type RoomType string
+
+const (
+	RoomClassroom RoomType = "classroom"
+	RoomLab       RoomType = "lab"
+	RoomGym       RoomType = "gym"
+	RoomHall      RoomType = "hall"
+	RoomOther     RoomType = "other"
+)
+
+// IsValid reports whether the room type is one of the known types
+func (t RoomType) IsValid() bool {
+	switch t {
+	case RoomClassroom, RoomLab, RoomGym, RoomHall, RoomOther:
+		return true
+	}
+	return false
+}
+
+// Room is a bookable space in a building, e.g. Science 204
+type Room struct {
+	Base
+	Name      string   `json:"name" gorm:"size:50;not null;uniqueIndex"`
+	Building  string   `json:"building" gorm:"index"`
+	Capacity  int      `json:"capacity"`
+	Type      RoomType `json:"type" gorm:"size:20;not null;default:classroom"`
+	Equipment []string `json:"equipment" gorm:"serializer:json"` // Tags such as projector or fume-hood
+}
+
+// Validate checks the room name, capacity and type
+func (r *Room) Validate() error {
+	if r.Name == "" {
+		return errors.New("room name is required")
+	}
+	if r.Capacity <= 0 {
+		return errors.New("room capacity must be positive")
+	}
+	if !r.Type.IsValid() {
+		return errors.New("room type must be classroom, lab, gym, hall or other")
+	}
+	return nil
+}
+
+// HasEquipment reports whether the room has every one of the equipment tags
+func (r *Room) HasEquipment(tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, equipment := range r.Equipment {
+			if strings.EqualFold(equipment, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// RoomBooking reserves a room for a one-off event on a date
+type RoomBooking struct {
+	Base
+	RoomID    uuid.UUID `json:"room_id" gorm:"type:uuid;not null;index:idx_room_bookings_room_date"`
+	Room      *Room     `json:"room,omitempty"`
+	Title     string    `json:"title" gorm:"not null"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;index:idx_room_bookings_room_date"`
+	StartTime string    `json:"start_time" gorm:"size:5"` // HH:MM
+	EndTime   string    `json:"end_time" gorm:"size:5"`   // HH:MM
+	Notes     string    `json:"notes"`
+	BookedBy  uuid.UUID `json:"booked_by" gorm:"type:uuid"`
+}
+
+// Validate checks that the booking has a title, a date and a valid time range
+func (b *RoomBooking) Validate() error {
+	if b.Title == "" {
+		return errors.New("booking title is required")
+	}
+	if b.Date.IsZero() {
+		return errors.New("booking date is required")
+	}
+	meeting := SectionMeeting{Day: b.Date.Weekday(), StartTime: b.StartTime, EndTime: b.EndTime}
+	return meeting.Validate()
+}
+
+// Overlaps reports whether the booking and a weekly meeting clash when the meeting falls on the booking's date
+func (b *RoomBooking) Overlaps(meeting *SectionMeeting) bool {
+	return meeting.Overlaps(&SectionMeeting{Day: b.Date.Weekday(), StartTime: b.StartTime, EndTime: b.EndTime})
+}
+
+// Minutes returns the booked duration
+func (b *RoomBooking) Minutes() int {
+	start, errStart := ParseClock(b.StartTime)
+	end, errEnd := ParseClock(b.EndTime)
+	if errStart != nil || errEnd != nil {
+		return 0
+	}
+	return end - start
+}
+
+// RoomUtilisation reports how much a room is used in a term
+type RoomUtilisation struct {
+	RoomID   uuid.UUID `json:"room_id"`
+	Name     string    `json:"name"`
+	Building string    `json:"building"`
+	Type     RoomType  `json:"type"`
+	Capacity int       `json:"capacity"`
+	Sections int       `json:"sections"`
+	// WeeklyMinutes is the class time scheduled in the room each week, out of AvailableMinutes in the school week
+	WeeklyMinutes    int     `json:"weekly_minutes"`
+	AvailableMinutes int     `json:"available_minutes"`
+	TimeUtilisation  float64 `json:"time_utilisation"` // Percentage of the school week in use
+	// SeatUtilisation is the average share of the room's seats taken by enrolled students during classes
+	SeatUtilisation float64 `json:"seat_utilisation"`
+	Bookings        int     `json:"bookings"`
+	BookedMinutes   int     `json:"booked_minutes"`
+}
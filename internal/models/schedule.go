@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRunStatus is the state of an automatic timetable run
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunPending   ScheduleRunStatus = "pending"
+	ScheduleRunRunning   ScheduleRunStatus = "running"
+	ScheduleRunCompleted ScheduleRunStatus = "completed"
+	ScheduleRunFailed    ScheduleRunStatus = "failed"
+	ScheduleRunPublished ScheduleRunStatus = "published"
+)
+
+// ScheduleOptions controls the periods and rooms an automatic timetable run works with
+type ScheduleOptions struct {
+	Days            []time.Weekday `json:"days"`              // Defaults to Monday to Friday
+	DayStart        string         `json:"day_start"`         // HH:MM, defaults to 08:00
+	DayEnd          string         `json:"day_end"`           // HH:MM, defaults to 16:00
+	PeriodMinutes   int            `json:"period_minutes"`    // Defaults to 50
+	BreakMinutes    int            `json:"break_minutes"`     // Defaults to 10
+	MeetingsPerWeek int            `json:"meetings_per_week"` // Defaults to 3
+	RoomIDs         []uuid.UUID    `json:"room_ids"`          // Rooms the run may use, defaults to every room
+	// Reschedule also moves sections that already have meetings; otherwise they are kept as they are
+	Reschedule bool `json:"reschedule"`
+}
+
+// ApplyDefaults fills in unset options
+func (o *ScheduleOptions) ApplyDefaults() {
+	if len(o.Days) == 0 {
+		o.Days = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	if o.DayStart == "" {
+		o.DayStart = "08:00"
+	}
+	if o.DayEnd == "" {
+		o.DayEnd = "16:00"
+	}
+	if o.PeriodMinutes == 0 {
+		o.PeriodMinutes = 50
+	}
+	if o.BreakMinutes == 0 {
+		o.BreakMinutes = 10
+	}
+	if o.MeetingsPerWeek == 0 {
+		o.MeetingsPerWeek = 3
+	}
+}
+
+// Validate checks the school day, period length and meetings per week
+func (o *ScheduleOptions) Validate() error {
+	for _, day := range o.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return errors.New("days must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+	start, err := ParseClock(o.DayStart)
+	if err != nil {
+		return fmt.Errorf("invalid day start: %w", err)
+	}
+	end, err := ParseClock(o.DayEnd)
+	if err != nil {
+		return fmt.Errorf("invalid day end: %w", err)
+	}
+	if o.PeriodMinutes <= 0 || o.BreakMinutes < 0 {
+		return errors.New("period length must be positive and breaks cannot be negative")
+	}
+	if start+o.PeriodMinutes > end {
+		return errors.New("the school day is shorter than one period")
+	}
+	if o.MeetingsPerWeek < 1 || o.MeetingsPerWeek > len(o.Days) {
+		return errors.New("meetings per week must be between 1 and the number of school days")
+	}
+	return nil
+}
+
+// ScheduleRun is an automatic timetable run for a term. Its assignments form a draft schedule
+// that is reviewed and then published onto the sections' meetings.
+type ScheduleRun struct {
+	Base
+	TermID           uuid.UUID            `json:"term_id" gorm:"type:uuid;not null;index"`
+	Term             *Term                `json:"term,omitempty"`
+	Status           ScheduleRunStatus    `json:"status" gorm:"size:20;not null"`
+	Options          ScheduleOptions      `json:"options" gorm:"serializer:json"`
+	RequestedBy      uuid.UUID            `json:"requested_by" gorm:"type:uuid"`
+	StartedAt        *time.Time           `json:"started_at"`
+	FinishedAt       *time.Time           `json:"finished_at"`
+	PublishedAt      *time.Time           `json:"published_at"`
+	Error            string               `json:"error,omitempty"`
+	Unresolved       []string             `json:"unresolved" gorm:"serializer:json"`
+	StudentConflicts int                  `json:"student_conflicts"`
+	Assignments      []ScheduleAssignment `json:"assignments,omitempty" gorm:"foreignKey:RunID"`
+}
+
+// ScheduleAssignment is one meeting placed by a timetable run
+type ScheduleAssignment struct {
+	Base
+	RunID     uuid.UUID    `json:"run_id" gorm:"type:uuid;not null;index"`
+	SectionID uuid.UUID    `json:"section_id" gorm:"type:uuid;not null"`
+	Section   *Section     `json:"section,omitempty"`
+	Day       time.Weekday `json:"day"`
+	StartTime string       `json:"start_time" gorm:"size:5"`
+	EndTime   string       `json:"end_time" gorm:"size:5"`
+	RoomID    *uuid.UUID   `json:"room_id" gorm:"type:uuid"`
+	Room      *Room        `json:"room,omitempty"`
+}
+
+// TeacherAvailability is a weekly window in which a teacher can teach.
+// A teacher without windows is treated as always available.
+type TeacherAvailability struct {
+	Base
+	TeacherID uuid.UUID    `json:"teacher_id" gorm:"type:uuid;not null;index"`
+	Day       time.Weekday `json:"day"`
+	StartTime string       `json:"start_time" gorm:"size:5"`
+	EndTime   string       `json:"end_time" gorm:"size:5"`
+}
+
+// Validate checks that the window has a valid day and time range
+func (a *TeacherAvailability) Validate() error {
+	meeting := SectionMeeting{Day: a.Day, StartTime: a.StartTime, EndTime: a.EndTime}
+	return meeting.Validate()
+}
+
+// CourseRequest is a student's request to take a course in a term, used when generating timetables
+type CourseRequest struct {
+	Base
+	StudentID uuid.UUID `json:"student_id" gorm:"type:uuid;not null;uniqueIndex:idx_course_requests_student_course_term"`
+	CourseID  uuid.UUID `json:"course_id" gorm:"type:uuid;not null;uniqueIndex:idx_course_requests_student_course_term"`
+	Course    *Course   `json:"course,omitempty"`
+	TermID    uuid.UUID `json:"term_id" gorm:"type:uuid;not null;uniqueIndex:idx_course_requests_student_course_term;index"`
+	Term      *Term     `json:"term,omitempty"`
+}
@@ -0,0 +1,227 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Section is a scheduled offering of a catalog course in a term, e.g. ALG1 period 2
+type Section struct {
+	Base
+	CourseID uuid.UUID        `json:"course_id" gorm:"type:uuid;not null;uniqueIndex:idx_sections_course_term_code"`
+	Course   *Course          `json:"course,omitempty"`
+	TermID   uuid.UUID        `json:"term_id" gorm:"type:uuid;not null;uniqueIndex:idx_sections_course_term_code;index"`
+	Term     *Term            `json:"term,omitempty"`
+	Code     string           `json:"code" gorm:"size:10;not null;uniqueIndex:idx_sections_course_term_code"`
+	RoomID   *uuid.UUID       `json:"room_id" gorm:"type:uuid;index"`
+	Room     *Room            `json:"room,omitempty"`
+	RoomType RoomType         `json:"room_type,omitempty" gorm:"size:20"` // Kind of room the section needs, empty for any
+	Capacity int              `json:"capacity"`                           // 0 means no limit
+	Meetings []SectionMeeting `json:"meetings" gorm:"foreignKey:SectionID"`
+	Teachers []Teacher        `json:"teachers,omitempty" gorm:"many2many:section_teachers;"`
+	Students []Student        `json:"students,omitempty" gorm:"many2many:section_students;"`
+}
+
+// SectionMeeting is one weekly meeting of a section
+type SectionMeeting struct {
+	Base
+	SectionID uuid.UUID    `json:"section_id" gorm:"type:uuid;not null;index"`
+	Day       time.Weekday `json:"day"`                            // 0 = Sunday ... 6 = Saturday
+	StartTime string       `json:"start_time" gorm:"size:5"`       // HH:MM
+	EndTime   string       `json:"end_time" gorm:"size:5"`         // HH:MM
+	RoomID    *uuid.UUID   `json:"room_id" gorm:"type:uuid;index"` // Nil means the section's room
+	Room      *Room        `json:"room,omitempty"`
+}
+
+// SectionStudent is the section_students join table, i.e. a section roster entry
+type SectionStudent struct {
+	SectionID uuid.UUID `json:"section_id" gorm:"type:uuid;primaryKey"`
+	StudentID uuid.UUID `json:"student_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SectionTeacher is the section_teachers join table
+type SectionTeacher struct {
+	SectionID uuid.UUID `json:"section_id" gorm:"type:uuid;primaryKey"`
+	TeacherID uuid.UUID `json:"teacher_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the section code, capacity and meeting pattern
+func (s *Section) Validate() error {
+	if s.Code == "" {
+		return errors.New("section code is required")
+	}
+	if s.Capacity < 0 {
+		return errors.New("capacity cannot be negative")
+	}
+	if s.RoomType != "" && !s.RoomType.IsValid() {
+		return errors.New("room type must be classroom, lab, gym, hall or other")
+	}
+	for i := range s.Meetings {
+		if err := s.Meetings[i].Validate(); err != nil {
+			return fmt.Errorf("meeting %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the meeting has a valid day and time range
+func (m *SectionMeeting) Validate() error {
+	if m.Day < time.Sunday || m.Day > time.Saturday {
+		return errors.New("day must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	start, err := ParseClock(m.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %w", err)
+	}
+	end, err := ParseClock(m.EndTime)
+	if err != nil {
+		return fmt.Errorf("invalid end time: %w", err)
+	}
+	if end <= start {
+		return errors.New("end time must be after start time")
+	}
+	return nil
+}
+
+// Overlaps reports whether two meetings fall on the same weekday with overlapping times
+func (m *SectionMeeting) Overlaps(other *SectionMeeting) bool {
+	if m.Day != other.Day {
+		return false
+	}
+	start, errStart := ParseClock(m.StartTime)
+	end, errEnd := ParseClock(m.EndTime)
+	otherStart, errOtherStart := ParseClock(other.StartTime)
+	otherEnd, errOtherEnd := ParseClock(other.EndTime)
+	if errStart != nil || errEnd != nil || errOtherStart != nil || errOtherEnd != nil {
+		return false
+	}
+	return start < otherEnd && otherStart < end
+}
+
+// RoomFor returns the ID of the room a meeting of the section is held in, or nil when it has none
+func (s *Section) RoomFor(meeting *SectionMeeting) *uuid.UUID {
+	if meeting.RoomID != nil {
+		return meeting.RoomID
+	}
+	return s.RoomID
+}
+
+// RoomNameFor returns the name of the room a meeting of the section is held in, when it is loaded
+func (s *Section) RoomNameFor(meeting *SectionMeeting) string {
+	if meeting.RoomID != nil {
+		if meeting.Room != nil {
+			return meeting.Room.Name
+		}
+		return ""
+	}
+	if s.Room != nil {
+		return s.Room.Name
+	}
+	return ""
+}
+
+// ConflictWith returns the first overlapping meeting of another section, or nil when the two
+// sections can be attended together. With sameRoom only meetings held in the same room clash.
+func (s *Section) ConflictWith(other *Section, sameRoom bool) *ScheduleConflict {
+	for i := range s.Meetings {
+		for j := range other.Meetings {
+			if !s.Meetings[i].Overlaps(&other.Meetings[j]) {
+				continue
+			}
+			room := other.RoomFor(&other.Meetings[j])
+			if sameRoom && (room == nil || !sameID(s.RoomFor(&s.Meetings[i]), room)) {
+				continue
+			}
+
+			conflict := &ScheduleConflict{
+				SectionID:   other.ID,
+				SectionCode: other.Code,
+				Day:         other.Meetings[j].Day,
+				StartTime:   other.Meetings[j].StartTime,
+				EndTime:     other.Meetings[j].EndTime,
+				Room:        other.RoomNameFor(&other.Meetings[j]),
+			}
+			if other.Course != nil {
+				conflict.CourseCode = other.Course.Code
+			}
+			return conflict
+		}
+	}
+	return nil
+}
+
+// Meeting returns the section's meeting with an ID, or nil when it has none
+func (s *Section) Meeting(id uuid.UUID) *SectionMeeting {
+	for i := range s.Meetings {
+		if s.Meetings[i].ID == id {
+			return &s.Meetings[i]
+		}
+	}
+	return nil
+}
+
+// MeetingOn returns the meeting a period refers to on a weekday: the period-th meeting of the day by
+// start time, or for period 0 the day's only meeting. It returns nil when there is no such meeting.
+func (s *Section) MeetingOn(day time.Weekday, period int) *SectionMeeting {
+	var meetings []*SectionMeeting
+	for i := range s.Meetings {
+		if s.Meetings[i].Day == day {
+			meetings = append(meetings, &s.Meetings[i])
+		}
+	}
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].StartTime < meetings[j].StartTime
+	})
+
+	switch {
+	case period == 0 && len(meetings) == 1:
+		return meetings[0]
+	case period > 0 && period <= len(meetings):
+		return meetings[period-1]
+	}
+	return nil
+}
+
+// PeriodOf returns the period that refers to a meeting on its weekday, the inverse of MeetingOn: 0 when
+// it is the day's only meeting, otherwise its position among the day's meetings by start time
+func (s *Section) PeriodOf(meeting *SectionMeeting) int {
+	count, position := 0, 0
+	for i := range s.Meetings {
+		if s.Meetings[i].Day != meeting.Day {
+			continue
+		}
+		count++
+		if s.Meetings[i].StartTime < meeting.StartTime {
+			position++
+		}
+	}
+	if count <= 1 {
+		return 0
+	}
+	return position + 1
+}
+
+// sameID reports whether two optional IDs are both set and equal
+func sameID(a, b *uuid.UUID) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// ParseClock converts an HH:MM time of day into minutes after midnight
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock converts minutes after midnight into an HH:MM time of day
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
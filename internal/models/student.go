@@ -9,30 +9,33 @@ import (
 // Student represents a student in the school
 type Student struct {
 	Base
-	FirstName      string      `json:"first_name"`
-	LastName       string      `json:"last_name"`
-	Email          string      `json:"email" gorm:"uniqueIndex"`
-	DateOfBirth    time.Time   `json:"date_of_birth"`
-	Gender         string      `json:"gender"`
-	Address        string      `json:"address"`
-	Phone          string      `json:"phone"`
-	EnrollmentDate time.Time   `json:"enrollment_date"`
-	GradeLevel     string      `json:"grade_level"`
-	CourseIDs      []uuid.UUID `json:"course_ids" gorm:"-"`
-	Courses        []Course    `json:"courses" gorm:"many2many:student_courses;"`
+	FirstName      string          `json:"first_name"`
+	LastName       string          `json:"last_name"`
+	Email          string          `json:"email" gorm:"uniqueIndex"`
+	DateOfBirth    time.Time       `json:"date_of_birth"`
+	Gender         string          `json:"gender"`
+	Address        string          `json:"address"`
+	Phone          string          `json:"phone"`
+	EnrollmentDate time.Time       `json:"enrollment_date"`
+	GradeLevel     string          `json:"grade_level"`
+	CounselorID    *uuid.UUID      `json:"counselor_id" gorm:"type:uuid;index"` // User following up the student's attendance alerts
+	CourseIDs      []uuid.UUID     `json:"course_ids" gorm:"-"`
+	Courses        []Course        `json:"courses" gorm:"many2many:student_courses;"`
+	Account        *AccountRequest `json:"account,omitempty" gorm:"-"` // Login to provision when the student is created
 }
 
 // StudentResponse is the API response structure for students
 type StudentResponse struct {
-	ID             uuid.UUID `json:"id"`
-	FirstName      string    `json:"first_name"`
-	LastName       string    `json:"last_name"`
-	Email          string    `json:"email"`
-	DateOfBirth    time.Time `json:"date_of_birth"`
-	Gender         string    `json:"gender"`
-	Address        string    `json:"address"`
-	Phone          string    `json:"phone"`
-	EnrollmentDate time.Time `json:"enrollment_date"`
-	GradeLevel     string    `json:"grade_level"`
-	Courses        []Course  `json:"courses,omitempty"`
+	ID             uuid.UUID  `json:"id"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Email          string     `json:"email"`
+	DateOfBirth    time.Time  `json:"date_of_birth"`
+	Gender         string     `json:"gender"`
+	Address        string     `json:"address"`
+	Phone          string     `json:"phone"`
+	EnrollmentDate time.Time  `json:"enrollment_date"`
+	GradeLevel     string     `json:"grade_level"`
+	CounselorID    *uuid.UUID `json:"counselor_id"`
+	Courses        []Course   `json:"courses,omitempty"`
 }
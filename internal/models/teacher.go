@@ -7,14 +7,15 @@ import (
 // Teacher represents a teacher in the school
 type Teacher struct {
 	Base
-	FirstName      string      `json:"first_name"`
-	LastName       string      `json:"last_name"`
-	Email          string      `json:"email" gorm:"uniqueIndex"`
-	Specialization string      `json:"specialization"`
-	Phone          string      `json:"phone"`
-	Address        string      `json:"address"`
-	CourseIDs      []uuid.UUID `json:"course_ids" gorm:"-"`
-	Courses        []Course    `json:"courses" gorm:"many2many:teacher_courses;"`
+	FirstName      string          `json:"first_name"`
+	LastName       string          `json:"last_name"`
+	Email          string          `json:"email" gorm:"uniqueIndex"`
+	Specialization string          `json:"specialization"`
+	Phone          string          `json:"phone"`
+	Address        string          `json:"address"`
+	CourseIDs      []uuid.UUID     `json:"course_ids" gorm:"-"`
+	Courses        []Course        `json:"courses" gorm:"many2many:teacher_courses;"`
+	Account        *AccountRequest `json:"account,omitempty" gorm:"-"` // Login to provision when the teacher is created
 }
 
 // TeacherResponse is the API response structure for teachers
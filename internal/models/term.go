@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AcademicYear represents a school year such as 2024-2025
+type AcademicYear struct {
+	Base
+	Name      string    `json:"name" gorm:"uniqueIndex:idx_academic_years_active_name,where:deleted_at IS NULL;size:20"`
+	StartDate time.Time `json:"start_date" gorm:"type:date;not null"`
+	EndDate   time.Time `json:"end_date" gorm:"type:date;not null"`
+	Terms     []Term    `json:"terms,omitempty" gorm:"foreignKey:AcademicYearID"`
+}
+
+// Term represents a grading period within an academic year, e.g. Fall 2024
+type Term struct {
+	Base
+	AcademicYearID uuid.UUID `json:"academic_year_id" gorm:"type:uuid;not null;index"`
+	Name           string    `json:"name" gorm:"uniqueIndex:idx_terms_active_name,where:deleted_at IS NULL;size:20"` // Matches Grade.Term
+	StartDate      time.Time `json:"start_date" gorm:"type:date;not null"`
+	EndDate        time.Time `json:"end_date" gorm:"type:date;not null"`
+	IsCurrent      bool      `json:"is_current"`
+}
+
+// Validate checks that the academic year has a name and a valid date range
+func (y *AcademicYear) Validate() error {
+	if y.Name == "" {
+		return errors.New("academic year name is required")
+	}
+	if !y.EndDate.After(y.StartDate) {
+		return errors.New("academic year must end after it starts")
+	}
+	return nil
+}
+
+// Validate checks that the term has a name and a date range inside its academic year
+func (t *Term) Validate(year *AcademicYear) error {
+	if t.Name == "" {
+		return errors.New("term name is required")
+	}
+	if !t.EndDate.After(t.StartDate) {
+		return errors.New("term must end after it starts")
+	}
+	if t.StartDate.Before(year.StartDate) || t.EndDate.After(year.EndDate) {
+		return errors.New("term dates must fall within the academic year")
+	}
+	return nil
+}
+
+// Overlaps reports whether the term shares any day with another term
+func (t *Term) Overlaps(other *Term) bool {
+	return !t.EndDate.Before(other.StartDate) && !other.EndDate.Before(t.StartDate)
+}
+
+// Contains reports whether a date falls within the term
+func (t *Term) Contains(date time.Time) bool {
+	day := date.Truncate(24 * time.Hour)
+	return !day.Before(t.StartDate) && !day.After(t.EndDate)
+}
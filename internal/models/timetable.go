@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimetableEntry is one weekly meeting on a student, teacher or room timetable
+type TimetableEntry struct {
+	Day         time.Weekday `json:"day"`
+	DayName     string       `json:"day_name"`
+	StartTime   string       `json:"start_time"`
+	EndTime     string       `json:"end_time"`
+	SectionID   uuid.UUID    `json:"section_id"`
+	SectionCode string       `json:"section_code"`
+	CourseID    uuid.UUID    `json:"course_id"`
+	CourseCode  string       `json:"course_code"`
+	CourseName  string       `json:"course_name"`
+	RoomID      *uuid.UUID   `json:"room_id"`
+	Room        string       `json:"room"`
+}
+
+// Timetable is the weekly schedule of a student, teacher or room in a term
+type Timetable struct {
+	TermID   uuid.UUID        `json:"term_id"`
+	TermName string           `json:"term_name"`
+	Entries  []TimetableEntry `json:"entries"`
+}
+
+// ScheduleConflict describes a meeting that overlaps with a requested section
+type ScheduleConflict struct {
+	SectionID   uuid.UUID    `json:"section_id"`
+	SectionCode string       `json:"section_code"`
+	CourseCode  string       `json:"course_code"`
+	Day         time.Weekday `json:"day"`
+	StartTime   string       `json:"start_time"`
+	EndTime     string       `json:"end_time"`
+	Room        string       `json:"room,omitempty"`
+}
+
+// String describes the conflict, e.g. "ALG1-02 on Monday 09:00-09:50"
+func (c *ScheduleConflict) String() string {
+	name := c.SectionCode
+	if c.CourseCode != "" {
+		name = c.CourseCode + "-" + c.SectionCode
+	}
+	desc := fmt.Sprintf("%s on %s %s-%s", name, c.Day, c.StartTime, c.EndTime)
+	if c.Room != "" {
+		desc += " in " + c.Room
+	}
+	return desc
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a long-lived token a client trades for a new access token. Only a hash of it is stored.
+// Each use replaces it with a new token of the same family, so a token that is used twice, as a stolen
+// copy would be, revokes the whole family.
+type RefreshToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FamilyID   uuid.UUID  `gorm:"type:uuid;not null;index"` // Tokens descended from the same login
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt  time.Time  `gorm:"not null;index"`
+	RevokedAt  *time.Time // Set when the token is used, or its family is logged out
+	ReplacedBy *uuid.UUID `gorm:"type:uuid"` // Token it was exchanged for
+	CreatedAt  time.Time
+}
+
+// RevokedToken denies an access token, by its ID, until it expires
+type RevokedToken struct {
+	TokenID   string    `gorm:"size:36;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// TokenPurpose is what a user token can be used for
+type TokenPurpose string
+
+// Purposes of user tokens
+const (
+	PurposePasswordReset     TokenPurpose = "password_reset"
+	PurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// UserToken is a single-use token emailed to a user to reset their password or verify their email
+// address. Only a hash of it is stored.
+type UserToken struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID    `gorm:"type:uuid;not null;index"`
+	Purpose   TokenPurpose `gorm:"size:20;not null"`
+	TokenHash string       `gorm:"size:64;not null;uniqueIndex"`
+	Email     string       // Address the token was sent to
+	ExpiresAt time.Time    `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// RefreshRequest exchanges a refresh token for new tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest ends a session, revoking the refresh token given with it
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ForgotPasswordRequest asks for a password reset link to be emailed
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ResetPasswordRequest sets a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmailRequest verifies an email address with a verification token
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
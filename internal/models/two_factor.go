@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a single-use code a user can log in with in place of one from their authenticator app,
+// such as when they have lost their phone. Only a hash of it is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TwoFactorPolicy requires the users of a role to turn on two-factor authentication
+type TwoFactorPolicy struct {
+	Role      string    `json:"role" gorm:"size:50;primaryKey"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TwoFactorSetup is what a user needs to add their account to an authenticator app
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRCode          string `json:"qr_code,omitempty"` // PNG of the provisioning URI as a data URI
+}
+
+// TwoFactorCodeRequest carries a code from an authenticator app, or a recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// LoginTwoFactorRequest finishes a login with the challenge token and a code
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorPolicyRequest sets the roles required to use two-factor authentication
+type TwoFactorPolicyRequest struct {
+	RequiredRoles []string `json:"required_roles"`
+}
@@ -6,26 +6,55 @@ import (
 	"github.com/google/uuid"
 )
 
-// User represents a system user (admin, staff)
+// User represents a system user (admin, staff), optionally the login of a teacher, student or guardian
 type User struct {
 	Base
-	Username  string `json:"username" gorm:"uniqueIndex"`
-	Email     string `json:"email" gorm:"uniqueIndex"`
-	Password  string `json:"-"` // Never return password in JSON
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Role      string `json:"role"` // Admin, Staff, etc.
+	Username           string     `json:"username" gorm:"uniqueIndex"`
+	Email              string     `json:"email" gorm:"uniqueIndex"`
+	Password           string     `json:"-"` // Never return password in JSON
+	FirstName          string     `json:"first_name"`
+	LastName           string     `json:"last_name"`
+	Role               string     `json:"role"`                                                               // Admin, Staff, etc.
+	TeacherID          *uuid.UUID `json:"teacher_id" gorm:"type:uuid;uniqueIndex:,where:deleted_at IS NULL"`  // Teacher this is the login of
+	StudentID          *uuid.UUID `json:"student_id" gorm:"type:uuid;uniqueIndex:,where:deleted_at IS NULL"`  // Student this is the login of
+	GuardianID         *uuid.UUID `json:"guardian_id" gorm:"type:uuid;uniqueIndex:,where:deleted_at IS NULL"` // Guardian this is the login of
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`                                                  // When the user proved they receive mail at their email address
+	TOTPSecret         string     `json:"-"`                                                                  // Shared with the user's authenticator app
+	TOTPLastCounter    int64      `json:"-"`                                                                  // Time step of the last code used, which cannot be used again
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`                                              // When the user turned on two-factor authentication
+	// Access tokens issued before this time are refused, as after logging out everywhere or a role or password change
+	TokensValidAfter *time.Time `json:"-"`
 }
 
 // UserResponse is the API response structure for users
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                 uuid.UUID  `json:"id"`
+	Username           string     `json:"username"`
+	Email              string     `json:"email"`
+	FirstName          string     `json:"first_name"`
+	LastName           string     `json:"last_name"`
+	Role               string     `json:"role"`
+	TeacherID          *uuid.UUID `json:"teacher_id"`
+	StudentID          *uuid.UUID `json:"student_id"`
+	GuardianID         *uuid.UUID `json:"guardian_id"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// AccountRequest sets up the login provisioned for a teacher, student or guardian
+type AccountRequest struct {
+	Username string `json:"username"` // Defaults to the person's email address
+	Password string `json:"password"` // Generated when empty
+	Skip     bool   `json:"skip"`     // Create the record without a login
+}
+
+// ProvisionedAccount is the login provisioned for a teacher, student or guardian. A generated password is only
+// ever returned here, once; an existing login with the person's email is linked instead of created.
+type ProvisionedAccount struct {
+	User              UserResponse `json:"user"`
+	Linked            bool         `json:"linked"`
+	TemporaryPassword string       `json:"temporary_password,omitempty"`
 }
 
 // LoginRequest represents the login request structure
@@ -34,8 +63,17 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse represents the login response structure
+// LoginResponse represents the login response structure. The token is a short-lived access token; the
+// refresh token is exchanged for new tokens before it expires. Users with two-factor authentication
+// turned on are instead given a challenge token, which they send with a code to finish logging in.
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token                  string        `json:"token,omitempty"`
+	ExpiresAt              *time.Time    `json:"expires_at,omitempty"`
+	RefreshToken           string        `json:"refresh_token,omitempty"`
+	RefreshExpiresAt       *time.Time    `json:"refresh_expires_at,omitempty"`
+	TwoFactorRequired      bool          `json:"two_factor_required,omitempty"`
+	ChallengeToken         string        `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt     *time.Time    `json:"challenge_expires_at,omitempty"`
+	TwoFactorSetupRequired bool          `json:"two_factor_setup_required,omitempty"` // The token only allows setting up two-factor authentication
+	User                   *UserResponse `json:"user,omitempty"`
 }
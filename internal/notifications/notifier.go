@@ -0,0 +1,36 @@
+// Package notifications delivers messages about events in the school to the staff who act on them.
+package notifications
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// Notification is a message for one user
+type Notification struct {
+	UserID  uuid.UUID
+	Email   string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes notifications to the application log, for deployments without a delivery channel
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier
+func NewLogNotifier() Notifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the notification
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	log.Printf("Notification for %s <%s>: %s: %s", notification.UserID, notification.Email, notification.Subject, notification.Body)
+	return nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AlertFilter narrows a list of attendance alerts; zero values match every alert
+type AlertFilter struct {
+	CounselorID *uuid.UUID
+	StudentID   *uuid.UUID
+	Status      models.AlertStatus
+}
+
+// AlertRepository defines the interface for attendance alert rule and alert repository
+type AlertRepository interface {
+	CreateRule(ctx context.Context, rule *models.AttendanceAlertRule) error
+	GetRuleByID(ctx context.Context, id uuid.UUID) (*models.AttendanceAlertRule, error)
+	GetRules(ctx context.Context, activeOnly bool) ([]models.AttendanceAlertRule, error)
+	UpdateRule(ctx context.Context, rule *models.AttendanceAlertRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	CreateAlert(ctx context.Context, alert *models.AttendanceAlert) (bool, error)
+	GetAlertByID(ctx context.Context, id uuid.UUID) (*models.AttendanceAlert, error)
+	GetAlerts(ctx context.Context, filter AlertFilter) ([]models.AttendanceAlert, error)
+	UpdateAlert(ctx context.Context, alert *models.AttendanceAlert) error
+	FindLatestAlert(ctx context.Context, studentID, ruleID uuid.UUID) (*models.AttendanceAlert, error)
+	FindAttendanceSince(ctx context.Context, studentID uuid.UUID, since time.Time) ([]models.Attendance, error)
+	FindStudentsWithAttendanceSince(ctx context.Context, since time.Time) ([]uuid.UUID, error)
+}
+
+// AlertRepositoryImpl implements the AlertRepository interface
+type AlertRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAlertRepository creates a new instance of AlertRepositoryImpl
+func NewAlertRepository(db *gorm.DB) AlertRepository {
+	return &AlertRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateRule creates a new alert rule
+func (r *AlertRepositoryImpl) CreateRule(ctx context.Context, rule *models.AttendanceAlertRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// GetRuleByID retrieves an alert rule by ID
+func (r *AlertRepositoryImpl) GetRuleByID(ctx context.Context, id uuid.UUID) (*models.AttendanceAlertRule, error) {
+	var rule models.AttendanceAlertRule
+	err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error
+	return &rule, err
+}
+
+// GetRules retrieves the alert rules, optionally only those that are enabled
+func (r *AlertRepositoryImpl) GetRules(ctx context.Context, activeOnly bool) ([]models.AttendanceAlertRule, error) {
+	var rules []models.AttendanceAlertRule
+	query := r.db.WithContext(ctx)
+	if activeOnly {
+		query = query.Where("disabled = ?", false)
+	}
+	err := query.Order("name").Find(&rules).Error
+	return rules, err
+}
+
+// UpdateRule updates an alert rule
+func (r *AlertRepositoryImpl) UpdateRule(ctx context.Context, rule *models.AttendanceAlertRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// DeleteRule deletes an alert rule
+func (r *AlertRepositoryImpl) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.AttendanceAlertRule{}, "id = ?", id).Error
+}
+
+// CreateAlert creates a new attendance alert unless the rule already has an unresolved alert for the
+// student. It reports whether the alert was created.
+func (r *AlertRepositoryImpl) CreateAlert(ctx context.Context, alert *models.AttendanceAlert) (bool, error) {
+	result := r.db.WithContext(ctx).Omit("Student", "Rule").Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetAlertByID retrieves an attendance alert with its student and rule
+func (r *AlertRepositoryImpl) GetAlertByID(ctx context.Context, id uuid.UUID) (*models.AttendanceAlert, error) {
+	var alert models.AttendanceAlert
+	err := r.db.WithContext(ctx).Preload("Student").Preload("Rule").First(&alert, "id = ?", id).Error
+	return &alert, err
+}
+
+// GetAlerts retrieves the alerts matching a filter, newest first
+func (r *AlertRepositoryImpl) GetAlerts(ctx context.Context, filter AlertFilter) ([]models.AttendanceAlert, error) {
+	var alerts []models.AttendanceAlert
+	query := r.db.WithContext(ctx).Preload("Student").Preload("Rule")
+	if filter.CounselorID != nil {
+		// Alerts follow the student's current counselor
+		query = query.Where("student_id IN (?)",
+			r.db.Model(&models.Student{}).Select("id").Where("counselor_id = ?", *filter.CounselorID))
+	}
+	if filter.StudentID != nil {
+		query = query.Where("student_id = ?", *filter.StudentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	err := query.Order("triggered_on DESC, created_at DESC").Find(&alerts).Error
+	return alerts, err
+}
+
+// UpdateAlert updates an attendance alert
+func (r *AlertRepositoryImpl) UpdateAlert(ctx context.Context, alert *models.AttendanceAlert) error {
+	return r.db.WithContext(ctx).Omit("Student", "Rule").Save(alert).Error
+}
+
+// FindLatestAlert finds the most recent alert a rule raised for a student
+func (r *AlertRepositoryImpl) FindLatestAlert(ctx context.Context, studentID, ruleID uuid.UUID) (*models.AttendanceAlert, error) {
+	var alert models.AttendanceAlert
+	err := r.db.WithContext(ctx).
+		Where("student_id = ? AND rule_id = ?", studentID, ruleID).
+		Order("created_at DESC").
+		First(&alert).Error
+	return &alert, err
+}
+
+// FindAttendanceSince finds a student's attendance records from a date on
+func (r *AlertRepositoryImpl) FindAttendanceSince(ctx context.Context, studentID uuid.UUID, since time.Time) ([]models.Attendance, error) {
+	var attendances []models.Attendance
+	err := r.db.WithContext(ctx).
+		Where("student_id = ? AND date >= ?", studentID, since.Format("2006-01-02")).
+		Order("date, period").
+		Find(&attendances).Error
+	return attendances, err
+}
+
+// FindStudentsWithAttendanceSince finds the students with attendance recorded from a date on
+func (r *AlertRepositoryImpl) FindStudentsWithAttendanceSince(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.Attendance{}).
+		Where("date >= ?", since.Format("2006-01-02")).
+		Distinct().
+		Pluck("student_id", &ids).Error
+	return ids, err
+}
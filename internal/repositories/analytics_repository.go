@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+
+	"school-management-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// breakdownCounts selects the attendance counts of an AttendanceBreakdown
+const breakdownCounts = "COUNT(*) AS total, " +
+	"COALESCE(SUM(CASE WHEN a.status = 'present' THEN 1 ELSE 0 END), 0) AS present, " +
+	"COALESCE(SUM(CASE WHEN a.status = 'late' THEN 1 ELSE 0 END), 0) AS late, " +
+	"COALESCE(SUM(CASE WHEN a.status = 'absent' THEN 1 ELSE 0 END), 0) AS absent, " +
+	"COALESCE(SUM(CASE WHEN a.status = 'excused' THEN 1 ELSE 0 END), 0) AS excused"
+
+// AnalyticsRepository defines the interface for aggregated attendance analytics
+type AnalyticsRepository interface {
+	GetAttendanceTotal(ctx context.Context, filter models.AttendanceAnalyticsFilter) (*models.AttendanceBreakdown, error)
+	GetAttendanceByDay(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error)
+	GetAttendanceByWeek(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error)
+	GetAttendanceByCourse(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error)
+	GetAttendanceByGradeLevel(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error)
+	GetAttendanceByTeacher(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error)
+}
+
+// AnalyticsRepositoryImpl implements the AnalyticsRepository interface
+type AnalyticsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new instance of AnalyticsRepositoryImpl
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &AnalyticsRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetAttendanceTotal counts all the attendance matching a filter
+func (r *AnalyticsRepositoryImpl) GetAttendanceTotal(ctx context.Context, filter models.AttendanceAnalyticsFilter) (*models.AttendanceBreakdown, error) {
+	var total models.AttendanceBreakdown
+	err := r.attendance(ctx, filter).Select(breakdownCounts).Scan(&total).Error
+	return &total, err
+}
+
+// GetAttendanceByDay counts the attendance matching a filter for each date
+func (r *AnalyticsRepositoryImpl) GetAttendanceByDay(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error) {
+	var breakdowns []models.AttendanceBreakdown
+	err := r.attendance(ctx, filter).
+		Select("TO_CHAR(a.date, 'YYYY-MM-DD') AS key, " + breakdownCounts).
+		Group("a.date").
+		Order("a.date").
+		Scan(&breakdowns).Error
+	return breakdowns, err
+}
+
+// GetAttendanceByWeek counts the attendance matching a filter for each week, keyed by its Monday
+func (r *AnalyticsRepositoryImpl) GetAttendanceByWeek(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error) {
+	var breakdowns []models.AttendanceBreakdown
+	err := r.attendance(ctx, filter).
+		Select("TO_CHAR(DATE_TRUNC('week', a.date), 'YYYY-MM-DD') AS key, " + breakdownCounts).
+		Group("DATE_TRUNC('week', a.date)").
+		Order("DATE_TRUNC('week', a.date)").
+		Scan(&breakdowns).Error
+	return breakdowns, err
+}
+
+// GetAttendanceByCourse counts the attendance matching a filter for each course
+func (r *AnalyticsRepositoryImpl) GetAttendanceByCourse(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error) {
+	var breakdowns []models.AttendanceBreakdown
+	err := r.attendance(ctx, filter).
+		Joins("JOIN courses c ON c.id = a.course_id").
+		Select("CAST(c.id AS TEXT) AS key, c.code AS label, " + breakdownCounts).
+		Group("c.id, c.code").
+		Order("c.code").
+		Scan(&breakdowns).Error
+	return breakdowns, err
+}
+
+// GetAttendanceByGradeLevel counts the attendance matching a filter for each grade level
+func (r *AnalyticsRepositoryImpl) GetAttendanceByGradeLevel(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error) {
+	var breakdowns []models.AttendanceBreakdown
+	err := r.attendance(ctx, filter).
+		Joins("JOIN students s ON s.id = a.student_id").
+		Select("s.grade_level AS key, " + breakdownCounts).
+		Group("s.grade_level").
+		Order("s.grade_level").
+		Scan(&breakdowns).Error
+	return breakdowns, err
+}
+
+// GetAttendanceByTeacher counts the attendance matching a filter for each teacher, attributing each
+// record to the teachers of the student's section of the course in the term the date falls in
+func (r *AnalyticsRepositoryImpl) GetAttendanceByTeacher(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error) {
+	var breakdowns []models.AttendanceBreakdown
+	err := r.attendance(ctx, filter).
+		Joins("JOIN section_students ss ON ss.student_id = a.student_id").
+		Joins("JOIN sections sec ON sec.id = ss.section_id AND sec.course_id = a.course_id AND sec.deleted_at IS NULL").
+		Joins("JOIN terms t ON t.id = sec.term_id AND a.date BETWEEN t.start_date AND t.end_date").
+		Joins("JOIN section_teachers st ON st.section_id = sec.id").
+		Joins("JOIN teachers te ON te.id = st.teacher_id").
+		Select("CAST(te.id AS TEXT) AS key, te.first_name || ' ' || te.last_name AS label, " + breakdownCounts).
+		Group("te.id, te.first_name, te.last_name").
+		Order("te.last_name, te.first_name").
+		Scan(&breakdowns).Error
+	return breakdowns, err
+}
+
+// attendance starts a query over the attendance records matching a filter, aliased as a
+func (r *AnalyticsRepositoryImpl) attendance(ctx context.Context, filter models.AttendanceAnalyticsFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Table("attendances AS a").
+		Where("a.deleted_at IS NULL").
+		Where("a.date BETWEEN ? AND ?", filter.From, filter.To)
+	if filter.CourseID != nil {
+		query = query.Where("a.course_id = ?", *filter.CourseID)
+	}
+	if filter.GradeLevel != "" {
+		query = query.Where("a.student_id IN (?)",
+			r.db.Model(&models.Student{}).Select("id").Where("grade_level = ?", filter.GradeLevel))
+	}
+	if filter.TeacherID != nil {
+		query = query.Where(`EXISTS (SELECT 1 FROM section_teachers ft
+			JOIN sections fs ON fs.id = ft.section_id AND fs.deleted_at IS NULL
+			JOIN section_students fss ON fss.section_id = fs.id
+			JOIN terms ftm ON ftm.id = fs.term_id
+			WHERE ft.teacher_id = ? AND fs.course_id = a.course_id AND fss.student_id = a.student_id
+				AND a.date BETWEEN ftm.start_date AND ftm.end_date)`, *filter.TeacherID)
+	}
+	return query
+}
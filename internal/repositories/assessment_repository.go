@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AssessmentRepository defines the interface for assessment repository
+type AssessmentRepository interface {
+	Create(ctx context.Context, assessment *models.Assessment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Assessment, error)
+	Update(ctx context.Context, assessment *models.Assessment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.Assessment, error)
+	GetWeights(ctx context.Context, courseID uuid.UUID) ([]models.AssessmentWeight, error)
+	ReplaceWeights(ctx context.Context, courseID uuid.UUID, weights []models.AssessmentWeight) error
+	SaveScores(ctx context.Context, scores []models.AssessmentScore) error
+	FindScoresByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.AssessmentScore, error)
+}
+
+// AssessmentRepositoryImpl implements the AssessmentRepository interface
+type AssessmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAssessmentRepository creates a new instance of AssessmentRepositoryImpl
+func NewAssessmentRepository(db *gorm.DB) AssessmentRepository {
+	return &AssessmentRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create creates a new assessment
+func (r *AssessmentRepositoryImpl) Create(ctx context.Context, assessment *models.Assessment) error {
+	return r.db.WithContext(ctx).Create(assessment).Error
+}
+
+// GetByID retrieves an assessment by its ID
+func (r *AssessmentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Assessment, error) {
+	var assessment models.Assessment
+	err := r.db.WithContext(ctx).First(&assessment, "id = ?", id).Error
+	return &assessment, err
+}
+
+// Update updates an assessment
+func (r *AssessmentRepositoryImpl) Update(ctx context.Context, assessment *models.Assessment) error {
+	return r.db.WithContext(ctx).Save(assessment).Error
+}
+
+// Delete deletes an assessment and its scores
+func (r *AssessmentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("assessment_id = ?", id).Delete(&models.AssessmentScore{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Assessment{}, "id = ?", id).Error
+	})
+}
+
+// FindByCourse finds the assessments of a course, optionally limited to a term
+func (r *AssessmentRepositoryImpl) FindByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.Assessment, error) {
+	var assessments []models.Assessment
+	query := r.db.WithContext(ctx).Where("course_id = ?", courseID)
+	if term != "" {
+		query = query.Where("term = ?", term)
+	}
+	err := query.Order("due_date, created_at").Find(&assessments).Error
+	return assessments, err
+}
+
+// GetWeights gets the category weights of a course
+func (r *AssessmentRepositoryImpl) GetWeights(ctx context.Context, courseID uuid.UUID) ([]models.AssessmentWeight, error) {
+	var weights []models.AssessmentWeight
+	err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Order("type").Find(&weights).Error
+	return weights, err
+}
+
+// ReplaceWeights replaces all category weights of a course
+func (r *AssessmentRepositoryImpl) ReplaceWeights(ctx context.Context, courseID uuid.UUID, weights []models.AssessmentWeight) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("course_id = ?", courseID).Delete(&models.AssessmentWeight{}).Error; err != nil {
+			return err
+		}
+		if len(weights) == 0 {
+			return nil
+		}
+		return tx.Create(&weights).Error
+	})
+}
+
+// SaveScores creates or updates scores keyed by assessment and student
+func (r *AssessmentRepositoryImpl) SaveScores(ctx context.Context, scores []models.AssessmentScore) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range scores {
+			var existing models.AssessmentScore
+			err := tx.Where("assessment_id = ? AND student_id = ?", scores[i].AssessmentID, scores[i].StudentID).
+				First(&existing).Error
+			switch {
+			case err == nil:
+				scores[i].ID = existing.ID
+				scores[i].CreatedAt = existing.CreatedAt
+				if err := tx.Save(&scores[i]).Error; err != nil {
+					return err
+				}
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := tx.Create(&scores[i]).Error; err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindScoresByCourse finds all scores for a course's assessments, optionally limited to a term
+func (r *AssessmentRepositoryImpl) FindScoresByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.AssessmentScore, error) {
+	var scores []models.AssessmentScore
+	query := r.db.WithContext(ctx).
+		Joins("JOIN assessments ON assessments.id = assessment_scores.assessment_id AND assessments.deleted_at IS NULL").
+		Where("assessments.course_id = ?", courseID)
+	if term != "" {
+		query = query.Where("assessments.term = ?", term)
+	}
+	err := query.Find(&scores).Error
+	return scores, err
+}
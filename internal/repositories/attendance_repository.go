@@ -1,11 +1,14 @@
 package repositories
 
 import (
+	"errors"
 	"school-management-api/internal/models"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AttendanceRepository defines methods for attendance management
@@ -14,14 +17,21 @@ type AttendanceRepository interface {
 	Update(attendance *models.Attendance) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*models.Attendance, error)
-	FindAll() ([]models.Attendance, error)
-	FindByStudent(studentID uuid.UUID) ([]models.Attendance, error)
-	FindByCourse(courseID uuid.UUID) ([]models.Attendance, error)
-	FindByDate(date time.Time) ([]models.Attendance, error)
-	FindByDateRange(startDate, endDate time.Time) ([]models.Attendance, error)
-	FindByCourseAndDate(courseID uuid.UUID, date time.Time) ([]models.Attendance, error)
-	GetStudentAttendanceReport(studentID uuid.UUID) (map[string]int, error)
+	FindAll(term *models.Term, scope *RecordScope) ([]models.Attendance, error)
+	FindByStudent(studentID uuid.UUID, term *models.Term, scope *RecordScope) ([]models.Attendance, error)
+	FindByCourse(courseID uuid.UUID, term *models.Term, scope *RecordScope) ([]models.Attendance, error)
+	FindByDate(date time.Time, scope *RecordScope) ([]models.Attendance, error)
+	FindByDateRange(startDate, endDate time.Time, scope *RecordScope) ([]models.Attendance, error)
+	FindByCourseAndDate(courseID uuid.UUID, date time.Time, scope *RecordScope) ([]models.Attendance, error)
+	GetStudentAttendanceReport(studentID uuid.UUID, scope *RecordScope) (map[string]int, error)
 	GetCourseAttendanceReport(courseID uuid.UUID) (map[string]map[string]int, error)
+	GetStudentAttendanceRates(studentID uuid.UUID, term *models.Term, calendar *models.SchoolCalendar, through time.Time) ([]models.AttendanceRate, error)
+	GetCourseAttendanceRates(courseID uuid.UUID, term *models.Term, calendar *models.SchoolCalendar, through time.Time) ([]models.AttendanceRate, error)
+	FindSessionsWithoutAttendance(teacherID uuid.UUID, term *models.Term, calendar *models.SchoolCalendar, from, to time.Time) ([]models.ExpectedSession, error)
+	FindByKey(studentID, courseID uuid.UUID, date time.Time, period int) (*models.Attendance, error)
+	Upsert(attendance *models.Attendance, recordedBy uuid.UUID) (bool, error)
+	CreateIfAbsent(attendance *models.Attendance, recordedBy uuid.UUID) (bool, error)
+	SaveRoll(records []models.Attendance, takenBy uuid.UUID) error
 }
 
 // AttendanceRepositoryImpl implements the AttendanceRepository interface
@@ -41,7 +51,7 @@ func (r *AttendanceRepositoryImpl) Create(attendance *models.Attendance) error {
 
 // Update updates an attendance record
 func (r *AttendanceRepositoryImpl) Update(attendance *models.Attendance) error {
-	return r.DB.Save(attendance).Error
+	return r.DB.Omit("Student", "Course", "Meeting").Save(attendance).Error
 }
 
 // Delete deletes an attendance record
@@ -52,49 +62,52 @@ func (r *AttendanceRepositoryImpl) Delete(id uuid.UUID) error {
 // FindByID finds an attendance record by ID
 func (r *AttendanceRepositoryImpl) FindByID(id uuid.UUID) (*models.Attendance, error) {
 	var attendance models.Attendance
-	err := r.DB.Preload("Student").Preload("Course").First(&attendance, id).Error
+	// The meeting may since have been removed from its section
+	err := r.DB.Preload("Student").Preload("Course").Preload("Meeting", func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped()
+	}).First(&attendance, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &attendance, nil
 }
 
-// FindAll finds all attendance records
-func (r *AttendanceRepositoryImpl) FindAll() ([]models.Attendance, error) {
+// FindAll finds all attendance records in a scope, optionally limited to a term
+func (r *AttendanceRepositoryImpl) FindAll(term *models.Term, scope *RecordScope) ([]models.Attendance, error) {
 	var attendances []models.Attendance
-	err := r.DB.Preload("Student").Preload("Course").Find(&attendances).Error
+	err := scope.apply(filterByTermDates(r.DB, term)).Preload("Student").Preload("Course").Find(&attendances).Error
 	if err != nil {
 		return nil, err
 	}
 	return attendances, nil
 }
 
-// FindByStudent finds attendance records by student ID
-func (r *AttendanceRepositoryImpl) FindByStudent(studentID uuid.UUID) ([]models.Attendance, error) {
+// FindByStudent finds attendance records in a scope by student ID, optionally limited to a term
+func (r *AttendanceRepositoryImpl) FindByStudent(studentID uuid.UUID, term *models.Term, scope *RecordScope) ([]models.Attendance, error) {
 	var attendances []models.Attendance
-	err := r.DB.Preload("Course").Where("student_id = ?", studentID).Find(&attendances).Error
+	err := scope.apply(filterByTermDates(r.DB, term)).Preload("Course").Where("student_id = ?", studentID).Find(&attendances).Error
 	if err != nil {
 		return nil, err
 	}
 	return attendances, nil
 }
 
-// FindByCourse finds attendance records by course ID
-func (r *AttendanceRepositoryImpl) FindByCourse(courseID uuid.UUID) ([]models.Attendance, error) {
+// FindByCourse finds attendance records in a scope by course ID, optionally limited to a term
+func (r *AttendanceRepositoryImpl) FindByCourse(courseID uuid.UUID, term *models.Term, scope *RecordScope) ([]models.Attendance, error) {
 	var attendances []models.Attendance
-	err := r.DB.Preload("Student").Where("course_id = ?", courseID).Find(&attendances).Error
+	err := scope.apply(filterByTermDates(r.DB, term)).Preload("Student").Where("course_id = ?", courseID).Find(&attendances).Error
 	if err != nil {
 		return nil, err
 	}
 	return attendances, nil
 }
 
-// FindByDate finds attendance records by date
-func (r *AttendanceRepositoryImpl) FindByDate(date time.Time) ([]models.Attendance, error) {
+// FindByDate finds attendance records in a scope by date
+func (r *AttendanceRepositoryImpl) FindByDate(date time.Time, scope *RecordScope) ([]models.Attendance, error) {
 	var attendances []models.Attendance
 	// Format date to match database format
 	formattedDate := date.Format("2006-01-02")
-	err := r.DB.Preload("Student").Preload("Course").
+	err := scope.apply(r.DB).Preload("Student").Preload("Course").
 		Where("DATE(date) = ?", formattedDate).
 		Find(&attendances).Error
 	if err != nil {
@@ -103,13 +116,13 @@ func (r *AttendanceRepositoryImpl) FindByDate(date time.Time) ([]models.Attendan
 	return attendances, nil
 }
 
-// FindByDateRange finds attendance records within a date range
-func (r *AttendanceRepositoryImpl) FindByDateRange(startDate, endDate time.Time) ([]models.Attendance, error) {
+// FindByDateRange finds attendance records in a scope within a date range
+func (r *AttendanceRepositoryImpl) FindByDateRange(startDate, endDate time.Time, scope *RecordScope) ([]models.Attendance, error) {
 	var attendances []models.Attendance
 	// Format dates to match database format
 	formattedStartDate := startDate.Format("2006-01-02")
 	formattedEndDate := endDate.Format("2006-01-02")
-	err := r.DB.Preload("Student").Preload("Course").
+	err := scope.apply(r.DB).Preload("Student").Preload("Course").
 		Where("DATE(date) BETWEEN ? AND ?", formattedStartDate, formattedEndDate).
 		Find(&attendances).Error
 	if err != nil {
@@ -118,12 +131,12 @@ func (r *AttendanceRepositoryImpl) FindByDateRange(startDate, endDate time.Time)
 	return attendances, nil
 }
 
-// FindByCourseAndDate finds attendance records by course ID and date
-func (r *AttendanceRepositoryImpl) FindByCourseAndDate(courseID uuid.UUID, date time.Time) ([]models.Attendance, error) {
+// FindByCourseAndDate finds attendance records in a scope by course ID and date
+func (r *AttendanceRepositoryImpl) FindByCourseAndDate(courseID uuid.UUID, date time.Time, scope *RecordScope) ([]models.Attendance, error) {
 	var attendances []models.Attendance
 	// Format date to match database format
 	formattedDate := date.Format("2006-01-02")
-	err := r.DB.Preload("Student").
+	err := scope.apply(r.DB).Preload("Student").
 		Where("course_id = ? AND DATE(date) = ?", courseID, formattedDate).
 		Find(&attendances).Error
 	if err != nil {
@@ -132,8 +145,8 @@ func (r *AttendanceRepositoryImpl) FindByCourseAndDate(courseID uuid.UUID, date
 	return attendances, nil
 }
 
-// GetStudentAttendanceReport gets a report of a student's attendance
-func (r *AttendanceRepositoryImpl) GetStudentAttendanceReport(studentID uuid.UUID) (map[string]int, error) {
+// GetStudentAttendanceReport gets a report of a student's attendance in a scope
+func (r *AttendanceRepositoryImpl) GetStudentAttendanceReport(studentID uuid.UUID, scope *RecordScope) (map[string]int, error) {
 	// Initialize report
 	report := map[string]int{
 		"present": 0,
@@ -144,7 +157,7 @@ func (r *AttendanceRepositoryImpl) GetStudentAttendanceReport(studentID uuid.UUI
 	}
 
 	// Query to count attendance by status
-	rows, err := r.DB.Model(&models.Attendance{}).
+	rows, err := scope.apply(r.DB.Model(&models.Attendance{})).
 		Select("status, COUNT(*) as count").
 		Where("student_id = ?", studentID).
 		Group("status").
@@ -168,56 +181,355 @@ func (r *AttendanceRepositoryImpl) GetStudentAttendanceReport(studentID uuid.UUI
 	return report, nil
 }
 
-// GetCourseAttendanceReport gets a report of attendance for a course
+// GetCourseAttendanceReport gets a report of attendance for a course, keyed by student name
 func (r *AttendanceRepositoryImpl) GetCourseAttendanceReport(courseID uuid.UUID) (map[string]map[string]int, error) {
 	// Initialize report
 	report := make(map[string]map[string]int)
 
-	// Get all students in this course
-	var students []models.Student
-	if err := r.DB.Joins("JOIN course_students ON students.id = course_students.student_id").
-		Where("course_students.course_id = ?", courseID).
-		Find(&students).Error; err != nil {
+	// Count each enrolled student's attendance in this course by status in one query
+	rows, err := r.DB.Model(&models.Student{}).
+		Select("students.first_name, students.last_name, attendances.status, COUNT(attendances.id) as count").
+		Joins("JOIN student_courses ON students.id = student_courses.student_id").
+		Joins("LEFT JOIN attendances ON attendances.student_id = students.id AND attendances.course_id = ? AND attendances.deleted_at IS NULL", courseID).
+		Where("student_courses.course_id = ?", courseID).
+		Group("students.id, students.first_name, students.last_name, attendances.status").
+		Rows()
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// For each student, get their attendance
-	for _, student := range students {
-		studentID := student.ID
-		studentName := student.FirstName + " " + student.LastName
+	// Process results
+	for rows.Next() {
+		var firstName, lastName string
+		var status *string
+		var count int
+		if err := rows.Scan(&firstName, &lastName, &status, &count); err != nil {
+			return nil, err
+		}
 
-		// Initialize student report
-		report[studentName] = map[string]int{
-			"present": 0,
-			"absent":  0,
-			"late":    0,
-			"excused": 0,
-			"total":   0,
+		studentName := firstName + " " + lastName
+		if _, ok := report[studentName]; !ok {
+			report[studentName] = map[string]int{
+				"present": 0,
+				"absent":  0,
+				"late":    0,
+				"excused": 0,
+				"total":   0,
+			}
+		}
+		if status != nil {
+			report[studentName][*status] += count
+			report[studentName]["total"] += count
 		}
+	}
+
+	return report, rows.Err()
+}
 
-		// Query to count attendance by status for this student in this course
-		rows, err := r.DB.Model(&models.Attendance{}).
-			Select("status, COUNT(*) as count").
-			Where("student_id = ? AND course_id = ?", studentID, courseID).
-			Group("status").
-			Rows()
-		if err != nil {
+// GetStudentAttendanceRates gets a student's attendance in each of their sections of a term measured
+// against the sessions the school calendar held from when they joined the section up to a date
+func (r *AttendanceRepositoryImpl) GetStudentAttendanceRates(studentID uuid.UUID, term *models.Term, calendar *models.SchoolCalendar, through time.Time) ([]models.AttendanceRate, error) {
+	sections, err := r.termSections(term.ID, "id IN (?)",
+		r.DB.Model(&models.SectionStudent{}).Select("section_id").Where("student_id = ?", studentID))
+	if err != nil {
+		return nil, err
+	}
+	rosters, err := r.sectionRosters(sections)
+	if err != nil {
+		return nil, err
+	}
+	records, err := r.FindByStudent(studentID, term, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]models.AttendanceRate, 0, len(sections))
+	for i := range sections {
+		section := &sections[i]
+		for _, entry := range rosters[section.ID] {
+			if entry.StudentID != studentID {
+				continue
+			}
+			rate := sessionRate(calendar, section, term, entry.CreatedAt, through, records)
+			courseID := section.CourseID
+			rate.CourseID = &courseID
+			if section.Course != nil {
+				rate.CourseCode = section.Course.Code
+			}
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+// GetCourseAttendanceRates gets the attendance of each student in a course's sections of a term measured
+// against the sessions the school calendar held from when they joined the section up to a date
+func (r *AttendanceRepositoryImpl) GetCourseAttendanceRates(courseID uuid.UUID, term *models.Term, calendar *models.SchoolCalendar, through time.Time) ([]models.AttendanceRate, error) {
+	sections, err := r.termSections(term.ID, "course_id = ?", courseID)
+	if err != nil {
+		return nil, err
+	}
+	rosters, err := r.sectionRosters(sections)
+	if err != nil {
+		return nil, err
+	}
+	records, err := r.FindByCourse(courseID, term, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byStudent := make(map[uuid.UUID][]models.Attendance)
+	for _, record := range records {
+		byStudent[record.StudentID] = append(byStudent[record.StudentID], record)
+	}
+
+	var studentIDs []uuid.UUID
+	for _, roster := range rosters {
+		for _, entry := range roster {
+			studentIDs = append(studentIDs, entry.StudentID)
+		}
+	}
+	var students []models.Student
+	if len(studentIDs) > 0 {
+		if err := r.DB.Where("id IN ?", studentIDs).Find(&students).Error; err != nil {
 			return nil, err
 		}
+	}
+	names := make(map[uuid.UUID]*models.Student, len(students))
+	for i := range students {
+		names[students[i].ID] = &students[i]
+	}
 
-		// Process results
-		for rows.Next() {
-			var status string
-			var count int
-			if err := rows.Scan(&status, &count); err != nil {
-				rows.Close()
-				return nil, err
+	var rates []models.AttendanceRate
+	for i := range sections {
+		section := &sections[i]
+		for _, entry := range rosters[section.ID] {
+			rate := sessionRate(calendar, section, term, entry.CreatedAt, through, byStudent[entry.StudentID])
+			studentID := entry.StudentID
+			rate.StudentID = &studentID
+			rate.CourseID = &section.CourseID
+			if student, ok := names[studentID]; ok {
+				rate.FirstName = student.FirstName
+				rate.LastName = student.LastName
 			}
-			report[studentName][status] = count
-			report[studentName]["total"] += count
+			rates = append(rates, rate)
 		}
-		rows.Close()
 	}
+	return rates, nil
+}
 
-	return report, nil
+// FindSessionsWithoutAttendance finds the sessions of a teacher's sections the school calendar held
+// between two dates for which no attendance was recorded for any student on the roster
+func (r *AttendanceRepositoryImpl) FindSessionsWithoutAttendance(teacherID uuid.UUID, term *models.Term, calendar *models.SchoolCalendar, from, to time.Time) ([]models.ExpectedSession, error) {
+	sections, err := r.termSections(term.ID, "id IN (?)",
+		r.DB.Model(&models.SectionTeacher{}).Select("section_id").Where("teacher_id = ?", teacherID))
+	if err != nil {
+		return nil, err
+	}
+	if len(sections) == 0 {
+		return []models.ExpectedSession{}, nil
+	}
+	rosters, err := r.sectionRosters(sections)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.Before(term.StartDate) {
+		from = term.StartDate
+	}
+	if to.After(term.EndDate) {
+		to = term.EndDate
+	}
+	courseIDs := make([]uuid.UUID, 0, len(sections))
+	for _, section := range sections {
+		courseIDs = append(courseIDs, section.CourseID)
+	}
+	var records []models.Attendance
+	if err := r.DB.Where("course_id IN ? AND DATE(date) BETWEEN ? AND ?", courseIDs, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	missing := []models.ExpectedSession{}
+	for i := range sections {
+		section := &sections[i]
+		// Attendance cannot be taken for a section nobody is enrolled in
+		if len(rosters[section.ID]) == 0 {
+			continue
+		}
+		onRoster := make(map[uuid.UUID]bool, len(rosters[section.ID]))
+		for _, entry := range rosters[section.ID] {
+			onRoster[entry.StudentID] = true
+		}
+
+		for _, session := range calendar.Sessions(section, from, to) {
+			taken := false
+			for j := range records {
+				if onRoster[records[j].StudentID] && session.CoveredBy(&records[j]) {
+					taken = true
+					break
+				}
+			}
+			if !taken {
+				missing = append(missing, session)
+			}
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Date != missing[j].Date {
+			return missing[i].Date < missing[j].Date
+		}
+		return missing[i].StartTime < missing[j].StartTime
+	})
+	return missing, nil
+}
+
+// termSections loads a term's sections matching a condition with their course and meetings
+func (r *AttendanceRepositoryImpl) termSections(termID uuid.UUID, query interface{}, args ...interface{}) ([]models.Section, error) {
+	var sections []models.Section
+	err := r.DB.Preload("Course").Preload("Meetings").
+		Where("term_id = ?", termID).
+		Where(query, args...).
+		Order("code").
+		Find(&sections).Error
+	return sections, err
+}
+
+// sectionRosters loads the roster entries of sections, which record when each student joined
+func (r *AttendanceRepositoryImpl) sectionRosters(sections []models.Section) (map[uuid.UUID][]models.SectionStudent, error) {
+	rosters := make(map[uuid.UUID][]models.SectionStudent, len(sections))
+	if len(sections) == 0 {
+		return rosters, nil
+	}
+	ids := make([]uuid.UUID, 0, len(sections))
+	for _, section := range sections {
+		ids = append(ids, section.ID)
+	}
+
+	var entries []models.SectionStudent
+	if err := r.DB.Where("section_id IN ?", ids).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		rosters[entry.SectionID] = append(rosters[entry.SectionID], entry)
+	}
+	return rosters, nil
+}
+
+// sessionRate counts a student's attendance records against the sessions of a section the calendar
+// held between the later of the term start and the day they joined, and the earlier of the term end and through
+func sessionRate(calendar *models.SchoolCalendar, section *models.Section, term *models.Term, joined, through time.Time, records []models.Attendance) models.AttendanceRate {
+	from := models.DateOnly(joined)
+	if from.Before(term.StartDate) {
+		from = term.StartDate
+	}
+	to := through
+	if to.After(term.EndDate) {
+		to = term.EndDate
+	}
+
+	var rate models.AttendanceRate
+	for _, session := range calendar.Sessions(section, from, to) {
+		var marked *models.Attendance
+		for i := range records {
+			if session.CoveredBy(&records[i]) {
+				marked = &records[i]
+				break
+			}
+		}
+		rate.Count(marked)
+	}
+	return rate
+}
+
+// FindByKey finds a student's attendance record for a course, date and period
+func (r *AttendanceRepositoryImpl) FindByKey(studentID, courseID uuid.UUID, date time.Time, period int) (*models.Attendance, error) {
+	var attendance models.Attendance
+	err := r.DB.Where("student_id = ? AND course_id = ? AND DATE(date) = ? AND period = ?",
+		studentID, courseID, date.Format("2006-01-02"), period).
+		First(&attendance).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
+
+// Upsert records an attendance mark, updating the student's existing record for the course, date and
+// period instead of adding a second one. It reports whether a new record was created.
+func (r *AttendanceRepositoryImpl) Upsert(attendance *models.Attendance, recordedBy uuid.UUID) (bool, error) {
+	var created bool
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = upsertAttendance(tx, attendance, recordedBy)
+		return err
+	})
+	return created, err
+}
+
+// CreateIfAbsent records an attendance mark unless the student already has a record for the course, date
+// and period, which is left as it is. It reports whether the record was created.
+func (r *AttendanceRepositoryImpl) CreateIfAbsent(attendance *models.Attendance, recordedBy uuid.UUID) (bool, error) {
+	attendance.CreatedBy = recordedBy
+	result := r.DB.Omit("Student", "Course", "Meeting").Clauses(clause.OnConflict{DoNothing: true}).Create(attendance)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SaveRoll records a course's attendance marks for a session in one transaction
+func (r *AttendanceRepositoryImpl) SaveRoll(records []models.Attendance, takenBy uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range records {
+			if _, err := upsertAttendance(tx, &records[i], takenBy); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// upsertAttendance creates a record or updates the status, times and notes of the existing one with the same
+// student, course, date and period; the existing record's ID and timestamps are copied back
+func upsertAttendance(tx *gorm.DB, attendance *models.Attendance, recordedBy uuid.UUID) (bool, error) {
+	var existing models.Attendance
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("student_id = ? AND course_id = ? AND DATE(date) = ? AND period = ?",
+			attendance.StudentID, attendance.CourseID, attendance.Date.Format("2006-01-02"), attendance.Period).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		attendance.CreatedBy = recordedBy
+		return true, tx.Omit("Student", "Course", "Meeting").Create(attendance).Error
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Model(&existing).Updates(map[string]interface{}{
+		"status":             attendance.Status,
+		"meeting_id":         attendance.MeetingID,
+		"arrival_time":       attendance.ArrivalTime,
+		"departure_time":     attendance.DepartureTime,
+		"scheduled_minutes":  attendance.ScheduledMinutes,
+		"minutes_late":       attendance.MinutesLate,
+		"minutes_left_early": attendance.MinutesLeftEarly,
+		"notes":              attendance.Notes,
+		"excuse_request_id":  attendance.ExcuseRequestID,
+		"updated_by":         recordedBy,
+	}).Error; err != nil {
+		return false, err
+	}
+	attendance.ID = existing.ID
+	attendance.CreatedAt = existing.CreatedAt
+	attendance.CreatedBy = existing.CreatedBy
+	attendance.UpdatedAt = existing.UpdatedAt
+	attendance.UpdatedBy = recordedBy
+	return false, nil
+}
+
+// filterByTermDates limits an attendance query to the dates of a term when one is given
+func filterByTermDates(db *gorm.DB, term *models.Term) *gorm.DB {
+	if term == nil {
+		return db
+	}
+	return db.Where("DATE(date) BETWEEN ? AND ?", term.StartDate.Format("2006-01-02"), term.EndDate.Format("2006-01-02"))
 }
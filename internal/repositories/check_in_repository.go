@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckInRepository defines the interface for self-service check-in repository
+type CheckInRepository interface {
+	Create(ctx context.Context, checkIn *models.CheckIn) (bool, error)
+	SetAttendance(ctx context.Context, id, attendanceID uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// CheckInRepositoryImpl implements the CheckInRepository interface
+type CheckInRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCheckInRepository creates a new instance of CheckInRepositoryImpl
+func NewCheckInRepository(db *gorm.DB) CheckInRepository {
+	return &CheckInRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create records a check-in unless the student has already checked in to the session. It reports
+// whether the check-in was recorded.
+func (r *CheckInRepositoryImpl) Create(ctx context.Context, checkIn *models.CheckIn) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(checkIn)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetAttendance links a check-in to the attendance record it produced
+func (r *CheckInRepositoryImpl) SetAttendance(ctx context.Context, id, attendanceID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.CheckIn{}).Where("id = ?", id).Update("attendance_id", attendanceID).Error
+}
+
+// Delete permanently removes a check-in, so the student can try again
+func (r *CheckInRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.CheckIn{}, "id = ?", id).Error
+}
@@ -19,6 +19,7 @@ type CourseRepository interface {
 	FindByCode(ctx context.Context, code string) (*models.Course, error)
 	GetStudents(ctx context.Context, courseID uuid.UUID) ([]models.Student, error)
 	GetTeachers(ctx context.Context, courseID uuid.UUID) ([]models.Teacher, error)
+	HasStudent(ctx context.Context, courseID, studentID uuid.UUID) (bool, error)
 }
 
 // CourseRepositoryImpl implements the CourseRepository interface
@@ -87,6 +88,15 @@ func (r *CourseRepositoryImpl) GetStudents(ctx context.Context, courseID uuid.UU
 	return students, err
 }
 
+// HasStudent reports whether a student is enrolled in a course
+func (r *CourseRepositoryImpl) HasStudent(ctx context.Context, courseID, studentID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.StudentCourse{}).
+		Where("course_id = ? AND student_id = ?", courseID, studentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // GetTeachers gets all teachers for a course
 func (r *CourseRepositoryImpl) GetTeachers(ctx context.Context, courseID uuid.UUID) ([]models.Teacher, error) {
 	var teachers []models.Teacher
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyReviewed is returned when an excuse request was approved or rejected by another reviewer first
+var ErrAlreadyReviewed = errors.New("excuse request has already been reviewed")
+
+// ExcuseFilter narrows a list of excuse requests; zero values match every request
+type ExcuseFilter struct {
+	StudentID   *uuid.UUID
+	SubmittedBy *uuid.UUID
+	Status      models.ExcuseStatus
+}
+
+// ExcuseRepository defines the interface for absence excuse request repository
+type ExcuseRepository interface {
+	Create(ctx context.Context, request *models.ExcuseRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ExcuseRequest, error)
+	GetAll(ctx context.Context, filter ExcuseFilter) ([]models.ExcuseRequest, error)
+	Approve(ctx context.Context, request *models.ExcuseRequest) error
+	Reject(ctx context.Context, request *models.ExcuseRequest) error
+	FindApproved(ctx context.Context, studentID uuid.UUID, date time.Time) (*models.ExcuseRequest, error)
+}
+
+// ExcuseRepositoryImpl implements the ExcuseRepository interface
+type ExcuseRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewExcuseRepository creates a new instance of ExcuseRepositoryImpl
+func NewExcuseRepository(db *gorm.DB) ExcuseRepository {
+	return &ExcuseRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create creates a new excuse request
+func (r *ExcuseRepositoryImpl) Create(ctx context.Context, request *models.ExcuseRequest) error {
+	return r.db.WithContext(ctx).Omit("Student").Create(request).Error
+}
+
+// GetByID retrieves an excuse request by ID with its student
+func (r *ExcuseRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.ExcuseRequest, error) {
+	var request models.ExcuseRequest
+	err := r.db.WithContext(ctx).Preload("Student").First(&request, "id = ?", id).Error
+	return &request, err
+}
+
+// GetAll retrieves the excuse requests matching a filter, newest first
+func (r *ExcuseRepositoryImpl) GetAll(ctx context.Context, filter ExcuseFilter) ([]models.ExcuseRequest, error) {
+	var requests []models.ExcuseRequest
+	query := r.db.WithContext(ctx).Preload("Student")
+	if filter.StudentID != nil {
+		query = query.Where("student_id = ?", *filter.StudentID)
+	}
+	if filter.SubmittedBy != nil {
+		query = query.Where("submitted_by = ?", *filter.SubmittedBy)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	err := query.Order("created_at DESC").Find(&requests).Error
+	return requests, err
+}
+
+// Approve saves an approved request and, in the same transaction, converts the student's absences in its
+// date range to excused, linking each to the request. The number converted is stored on the request.
+func (r *ExcuseRepositoryImpl) Approve(ctx context.Context, request *models.ExcuseRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveReview(tx, request); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Attendance{}).
+			Where("student_id = ? AND status = ? AND DATE(date) BETWEEN ? AND ?",
+				request.StudentID, models.Absent, request.FromDate.Format("2006-01-02"), request.ToDate.Format("2006-01-02")).
+			Updates(map[string]interface{}{
+				"status":            models.Excused,
+				"excuse_request_id": request.ID,
+				"updated_by":        *request.ReviewedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		request.ExcusedCount = int(result.RowsAffected)
+		return tx.Model(&models.ExcuseRequest{}).Where("id = ?", request.ID).Update("excused_count", request.ExcusedCount).Error
+	})
+}
+
+// Reject saves a rejected request
+func (r *ExcuseRepositoryImpl) Reject(ctx context.Context, request *models.ExcuseRequest) error {
+	return saveReview(r.db.WithContext(ctx), request)
+}
+
+// saveReview records the reviewer's decision on a request that is still pending, returning
+// ErrAlreadyReviewed when another reviewer decided on it first
+func saveReview(tx *gorm.DB, request *models.ExcuseRequest) error {
+	result := tx.Model(&models.ExcuseRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.ExcusePending).
+		Updates(map[string]interface{}{
+			"status":       request.Status,
+			"reviewed_by":  request.ReviewedBy,
+			"reviewed_at":  request.ReviewedAt,
+			"review_notes": request.ReviewNotes,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyReviewed
+	}
+	return nil
+}
+
+// FindApproved finds an approved excuse request of a student that covers a date
+func (r *ExcuseRepositoryImpl) FindApproved(ctx context.Context, studentID uuid.UUID, date time.Time) (*models.ExcuseRequest, error) {
+	var request models.ExcuseRequest
+	day := date.Format("2006-01-02")
+	err := r.db.WithContext(ctx).
+		Where("student_id = ? AND status = ? AND from_date <= ? AND to_date >= ?", studentID, models.ExcuseApproved, day, day).
+		Order("reviewed_at DESC").
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
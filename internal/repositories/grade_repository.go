@@ -13,12 +13,12 @@ type GradeRepository interface {
 	Update(grade *models.Grade) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*models.Grade, error)
-	FindAll() ([]models.Grade, error)
-	FindByStudent(studentID uuid.UUID) ([]models.Grade, error)
-	FindByCourse(courseID uuid.UUID) ([]models.Grade, error)
+	FindAll(term string, scope *RecordScope) ([]models.Grade, error)
+	FindByStudent(studentID uuid.UUID, term string, scope *RecordScope) ([]models.Grade, error)
+	FindByCourse(courseID uuid.UUID, term string, scope *RecordScope) ([]models.Grade, error)
 	FindByStudentAndCourse(studentID, courseID uuid.UUID) ([]models.Grade, error)
-	FindByTerm(term string) ([]models.Grade, error)
-	GetStudentGPA(studentID uuid.UUID) (float64, error)
+	FindByTerm(term string, scope *RecordScope) ([]models.Grade, error)
+	FindByStudentCourseAndTerm(studentID, courseID uuid.UUID, term string) (*models.Grade, error)
 	GetCourseGradeDistribution(courseID uuid.UUID) (map[string]int, error)
 }
 
@@ -57,30 +57,30 @@ func (r *GradeRepositoryImpl) FindByID(id uuid.UUID) (*models.Grade, error) {
 	return &grade, nil
 }
 
-// FindAll finds all grades
-func (r *GradeRepositoryImpl) FindAll() ([]models.Grade, error) {
+// FindAll finds all grades in a scope, optionally limited to a term
+func (r *GradeRepositoryImpl) FindAll(term string, scope *RecordScope) ([]models.Grade, error) {
 	var grades []models.Grade
-	err := r.DB.Preload("Student").Preload("Course").Find(&grades).Error
+	err := scope.apply(filterByTerm(r.DB, term)).Preload("Student").Preload("Course").Find(&grades).Error
 	if err != nil {
 		return nil, err
 	}
 	return grades, nil
 }
 
-// FindByStudent finds grades by student ID
-func (r *GradeRepositoryImpl) FindByStudent(studentID uuid.UUID) ([]models.Grade, error) {
+// FindByStudent finds grades in a scope by student ID, optionally limited to a term
+func (r *GradeRepositoryImpl) FindByStudent(studentID uuid.UUID, term string, scope *RecordScope) ([]models.Grade, error) {
 	var grades []models.Grade
-	err := r.DB.Preload("Course").Where("student_id = ?", studentID).Find(&grades).Error
+	err := scope.apply(filterByTerm(r.DB, term)).Preload("Course").Where("student_id = ?", studentID).Find(&grades).Error
 	if err != nil {
 		return nil, err
 	}
 	return grades, nil
 }
 
-// FindByCourse finds grades by course ID
-func (r *GradeRepositoryImpl) FindByCourse(courseID uuid.UUID) ([]models.Grade, error) {
+// FindByCourse finds grades in a scope by course ID, optionally limited to a term
+func (r *GradeRepositoryImpl) FindByCourse(courseID uuid.UUID, term string, scope *RecordScope) ([]models.Grade, error) {
 	var grades []models.Grade
-	err := r.DB.Preload("Student").Where("course_id = ?", courseID).Find(&grades).Error
+	err := scope.apply(filterByTerm(r.DB, term)).Preload("Student").Where("course_id = ?", courseID).Find(&grades).Error
 	if err != nil {
 		return nil, err
 	}
@@ -97,81 +97,57 @@ func (r *GradeRepositoryImpl) FindByStudentAndCourse(studentID, courseID uuid.UU
 	return grades, nil
 }
 
-// FindByTerm finds grades by term
-func (r *GradeRepositoryImpl) FindByTerm(term string) ([]models.Grade, error) {
+// FindByTerm finds grades in a scope by term
+func (r *GradeRepositoryImpl) FindByTerm(term string, scope *RecordScope) ([]models.Grade, error) {
 	var grades []models.Grade
-	err := r.DB.Preload("Student").Preload("Course").Where("term = ?", term).Find(&grades).Error
+	err := scope.apply(r.DB).Preload("Student").Preload("Course").Where("term = ?", term).Find(&grades).Error
 	if err != nil {
 		return nil, err
 	}
 	return grades, nil
 }
 
-// GetStudentGPA calculates a student's GPA
-func (r *GradeRepositoryImpl) GetStudentGPA(studentID uuid.UUID) (float64, error) {
-	// Logic to calculate GPA based on letter grades
-	type Result struct {
-		TotalPoints  float64
-		TotalCourses int
-	}
-
-	var result Result
-
-	// This is a simplified GPA calculation
-	err := r.DB.Raw(`
-		SELECT 
-			SUM(CASE 
-				WHEN grade = 'A+' THEN 4.0
-				WHEN grade = 'A' THEN 4.0
-				WHEN grade = 'A-' THEN 3.7
-				WHEN grade = 'B+' THEN 3.3
-				WHEN grade = 'B' THEN 3.0
-				WHEN grade = 'B-' THEN 2.7
-				WHEN grade = 'C+' THEN 2.3
-				WHEN grade = 'C' THEN 2.0
-				WHEN grade = 'C-' THEN 1.7
-				WHEN grade = 'D+' THEN 1.3
-				WHEN grade = 'D' THEN 1.0
-				WHEN grade = 'D-' THEN 0.7
-				ELSE 0
-			END) as total_points,
-			COUNT(*) as total_courses
-		FROM grades
-		WHERE student_id = ? AND deleted_at IS NULL
-	`, studentID).Scan(&result).Error
-
+// FindByStudentCourseAndTerm finds a student's grade for a course in a term
+func (r *GradeRepositoryImpl) FindByStudentCourseAndTerm(studentID, courseID uuid.UUID, term string) (*models.Grade, error) {
+	var grade models.Grade
+	err := r.DB.Where("student_id = ? AND course_id = ? AND term = ?", studentID, courseID, term).
+		Order("created_at DESC").
+		First(&grade).Error
 	if err != nil {
-		return 0, err
-	}
-
-	if result.TotalCourses == 0 {
-		return 0, nil
+		return nil, err
 	}
-
-	return result.TotalPoints / float64(result.TotalCourses), nil
+	return &grade, nil
 }
 
-// GetCourseGradeDistribution returns the distribution of letter grades for a course
+// GetCourseGradeDistribution returns the number of grades per letter for a course
 func (r *GradeRepositoryImpl) GetCourseGradeDistribution(courseID uuid.UUID) (map[string]int, error) {
-	var grades []models.Grade
-	err := r.DB.Where("course_id = ?", courseID).Find(&grades).Error
+	rows, err := r.DB.Model(&models.Grade{}).
+		Select("grade, COUNT(*) as count").
+		Where("course_id = ?", courseID).
+		Group("grade").
+		Rows()
 	if err != nil {
 		return nil, err
 	}
-
-	// Initialize the distribution map with all possible grades
-	distribution := map[string]int{
-		"A+": 0, "A": 0, "A-": 0,
-		"B+": 0, "B": 0, "B-": 0,
-		"C+": 0, "C": 0, "C-": 0,
-		"D+": 0, "D": 0, "D-": 0,
-		"F": 0,
-	}
-
-	// Count the occurrences of each grade
-	for _, grade := range grades {
-		distribution[grade.Grade]++
+	defer rows.Close()
+
+	distribution := make(map[string]int)
+	for rows.Next() {
+		var letter string
+		var count int
+		if err := rows.Scan(&letter, &count); err != nil {
+			return nil, err
+		}
+		distribution[letter] = count
 	}
 
 	return distribution, nil
 }
+
+// filterByTerm limits a grade query to a term when one is given
+func filterByTerm(db *gorm.DB, term string) *gorm.DB {
+	if term == "" {
+		return db
+	}
+	return db.Where("term = ?", term)
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GradingScaleRepository defines the interface for grading scale repository
+type GradingScaleRepository interface {
+	Create(ctx context.Context, scale *models.GradingScale) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.GradingScale, error)
+	GetAll(ctx context.Context) ([]models.GradingScale, error)
+	Update(ctx context.Context, scale *models.GradingScale) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindDefault(ctx context.Context) (*models.GradingScale, error)
+	CreateAssignment(ctx context.Context, assignment *models.GradingScaleAssignment) error
+	DeleteAssignment(ctx context.Context, id uuid.UUID) error
+	GetAssignments(ctx context.Context, scaleID uuid.UUID) ([]models.GradingScaleAssignment, error)
+	GetAllAssignments(ctx context.Context) ([]models.GradingScaleAssignment, error)
+}
+
+// GradingScaleRepositoryImpl implements the GradingScaleRepository interface
+type GradingScaleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGradingScaleRepository creates a new instance of GradingScaleRepositoryImpl
+func NewGradingScaleRepository(db *gorm.DB) GradingScaleRepository {
+	return &GradingScaleRepositoryImpl{
+		db: db,
+	}
+}
+
+// preloadBands loads a scale's bands from the highest band to the lowest
+func preloadBands(db *gorm.DB) *gorm.DB {
+	return db.Preload("Bands", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("min_score DESC")
+	})
+}
+
+// Create creates a new grading scale together with its bands
+func (r *GradingScaleRepositoryImpl) Create(ctx context.Context, scale *models.GradingScale) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if scale.IsDefault {
+			if err := clearDefaultScale(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(scale).Error
+	})
+}
+
+// GetByID retrieves a grading scale by its ID
+func (r *GradingScaleRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.GradingScale, error) {
+	var scale models.GradingScale
+	err := preloadBands(r.db.WithContext(ctx)).First(&scale, "id = ?", id).Error
+	return &scale, err
+}
+
+// GetAll retrieves all grading scales
+func (r *GradingScaleRepositoryImpl) GetAll(ctx context.Context) ([]models.GradingScale, error) {
+	var scales []models.GradingScale
+	err := preloadBands(r.db.WithContext(ctx)).Order("name").Find(&scales).Error
+	return scales, err
+}
+
+// Update updates a grading scale and replaces its bands
+func (r *GradingScaleRepositoryImpl) Update(ctx context.Context, scale *models.GradingScale) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if scale.IsDefault {
+			if err := clearDefaultScale(tx); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Where("scale_id = ?", scale.ID).Delete(&models.GradingBand{}).Error; err != nil {
+			return err
+		}
+		for i := range scale.Bands {
+			scale.Bands[i].ID = uuid.Nil
+			scale.Bands[i].ScaleID = scale.ID
+		}
+
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(scale).Error
+	})
+}
+
+// Delete deletes a grading scale and its assignments
+func (r *GradingScaleRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scale_id = ?", id).Delete(&models.GradingScaleAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.GradingScale{}, "id = ?", id).Error
+	})
+}
+
+// FindDefault finds the scale marked as the school default
+func (r *GradingScaleRepositoryImpl) FindDefault(ctx context.Context) (*models.GradingScale, error) {
+	var scale models.GradingScale
+	err := preloadBands(r.db.WithContext(ctx)).Where("is_default = ?", true).First(&scale).Error
+	return &scale, err
+}
+
+// CreateAssignment assigns a grading scale to a course, department or term
+func (r *GradingScaleRepositoryImpl) CreateAssignment(ctx context.Context, assignment *models.GradingScaleAssignment) error {
+	return r.db.WithContext(ctx).Create(assignment).Error
+}
+
+// DeleteAssignment removes a grading scale assignment
+func (r *GradingScaleRepositoryImpl) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.GradingScaleAssignment{}, "id = ?", id).Error
+}
+
+// GetAssignments gets all assignments for a grading scale
+func (r *GradingScaleRepositoryImpl) GetAssignments(ctx context.Context, scaleID uuid.UUID) ([]models.GradingScaleAssignment, error) {
+	var assignments []models.GradingScaleAssignment
+	err := r.db.WithContext(ctx).Where("scale_id = ?", scaleID).Find(&assignments).Error
+	return assignments, err
+}
+
+// GetAllAssignments gets every grading scale assignment
+func (r *GradingScaleRepositoryImpl) GetAllAssignments(ctx context.Context) ([]models.GradingScaleAssignment, error) {
+	var assignments []models.GradingScaleAssignment
+	err := r.db.WithContext(ctx).Find(&assignments).Error
+	return assignments, err
+}
+
+// clearDefaultScale unsets the default flag so only one scale is the default
+func clearDefaultScale(tx *gorm.DB) error {
+	return tx.Model(&models.GradingScale{}).Where("is_default = ?", true).Update("is_default", false).Error
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GuardianRepository defines the interface for guardian repository
+type GuardianRepository interface {
+	Create(ctx context.Context, guardian *models.Guardian) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Guardian, error)
+	GetAll(ctx context.Context, page, pageSize int) ([]models.Guardian, int64, error)
+	Update(ctx context.Context, guardian *models.Guardian) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByEmail(ctx context.Context, email string) (*models.Guardian, error)
+	SaveLink(ctx context.Context, link *models.StudentGuardian) error
+	DeleteLink(ctx context.Context, guardianID, studentID uuid.UUID) error
+	FindLink(ctx context.Context, guardianID, studentID uuid.UUID) (*models.StudentGuardian, error)
+	GetStudentLinks(ctx context.Context, guardianID uuid.UUID) ([]models.StudentGuardian, error)
+	GetGuardianLinks(ctx context.Context, studentID uuid.UUID) ([]models.StudentGuardian, error)
+}
+
+// GuardianRepositoryImpl implements the GuardianRepository interface
+type GuardianRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGuardianRepository creates a new instance of GuardianRepositoryImpl
+func NewGuardianRepository(db *gorm.DB) GuardianRepository {
+	return &GuardianRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create creates a new guardian
+func (r *GuardianRepositoryImpl) Create(ctx context.Context, guardian *models.Guardian) error {
+	return r.db.WithContext(ctx).Omit("Students").Create(guardian).Error
+}
+
+// GetByID retrieves a guardian by their ID together with the students they are a guardian of
+func (r *GuardianRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Guardian, error) {
+	var guardian models.Guardian
+	err := r.db.WithContext(ctx).Preload("Students.Student").First(&guardian, "id = ?", id).Error
+	return &guardian, err
+}
+
+// GetAll retrieves all guardians with pagination
+func (r *GuardianRepositoryImpl) GetAll(ctx context.Context, page, pageSize int) ([]models.Guardian, int64, error) {
+	var guardians []models.Guardian
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	// Count total records
+	if err := r.db.WithContext(ctx).Model(&models.Guardian{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Fetch records with pagination
+	err := r.db.WithContext(ctx).Order("last_name, first_name").Offset(offset).Limit(pageSize).Find(&guardians).Error
+	return guardians, total, err
+}
+
+// Update updates a guardian's details, leaving the students they are linked to alone
+func (r *GuardianRepositoryImpl) Update(ctx context.Context, guardian *models.Guardian) error {
+	return r.db.WithContext(ctx).Omit("Students").Save(guardian).Error
+}
+
+// Delete deletes a guardian and their links to students
+func (r *GuardianRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("guardian_id = ?", id).Delete(&models.StudentGuardian{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Guardian{}, "id = ?", id).Error
+	})
+}
+
+// FindByEmail finds a guardian by their email
+func (r *GuardianRepositoryImpl) FindByEmail(ctx context.Context, email string) (*models.Guardian, error) {
+	var guardian models.Guardian
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&guardian).Error
+	return &guardian, err
+}
+
+// SaveLink links a guardian to a student, or updates the link when they are already linked
+func (r *GuardianRepositoryImpl) SaveLink(ctx context.Context, link *models.StudentGuardian) error {
+	return r.db.WithContext(ctx).Omit("Student", "Guardian").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "student_id"}, {Name: "guardian_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"relationship", "has_custody", "can_pick_up", "emergency_contact", "primary_contact", "records_withheld", "updated_at",
+		}),
+	}).Create(link).Error
+}
+
+// DeleteLink unlinks a guardian from a student
+func (r *GuardianRepositoryImpl) DeleteLink(ctx context.Context, guardianID, studentID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("guardian_id = ? AND student_id = ?", guardianID, studentID).Delete(&models.StudentGuardian{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindLink finds the link between a guardian and a student
+func (r *GuardianRepositoryImpl) FindLink(ctx context.Context, guardianID, studentID uuid.UUID) (*models.StudentGuardian, error) {
+	var link models.StudentGuardian
+	err := r.db.WithContext(ctx).Preload("Student").
+		Where("guardian_id = ? AND student_id = ?", guardianID, studentID).
+		First(&link).Error
+	return &link, err
+}
+
+// GetStudentLinks gets a guardian's links to the students they are a guardian of
+func (r *GuardianRepositoryImpl) GetStudentLinks(ctx context.Context, guardianID uuid.UUID) ([]models.StudentGuardian, error) {
+	var links []models.StudentGuardian
+	err := r.db.WithContext(ctx).Preload("Student").
+		Joins("JOIN students ON students.id = student_guardians.student_id AND students.deleted_at IS NULL").
+		Where("student_guardians.guardian_id = ?", guardianID).
+		Order("students.last_name, students.first_name").
+		Find(&links).Error
+	return links, err
+}
+
+// GetGuardianLinks gets a student's links to their guardians, primary contacts first
+func (r *GuardianRepositoryImpl) GetGuardianLinks(ctx context.Context, studentID uuid.UUID) ([]models.StudentGuardian, error) {
+	var links []models.StudentGuardian
+	err := r.db.WithContext(ctx).Preload("Guardian").
+		Joins("JOIN guardians ON guardians.id = student_guardians.guardian_id AND guardians.deleted_at IS NULL").
+		Where("student_guardians.student_id = ?", studentID).
+		Order("student_guardians.primary_contact DESC, guardians.last_name, guardians.first_name").
+		Find(&links).Error
+	return links, err
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository defines the interface for the history of login attempts
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *models.LoginAttempt) error
+	FindByUser(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]models.LoginAttempt, int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+// LoginAttemptRepositoryImpl implements the LoginAttemptRepository interface
+type LoginAttemptRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new instance of LoginAttemptRepositoryImpl
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &LoginAttemptRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create records a login attempt
+func (r *LoginAttemptRepositoryImpl) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// FindByUser retrieves the login attempts of a user with pagination, latest first
+func (r *LoginAttemptRepositoryImpl) FindByUser(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]models.LoginAttempt, int64, error) {
+	var attempts []models.LoginAttempt
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	// Count total records
+	query := r.db.WithContext(ctx).Model(&models.LoginAttempt{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Fetch records with pagination
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Offset(offset).Limit(pageSize).
+		Find(&attempts).Error
+	return attempts, total, err
+}
+
+// DeleteBefore removes the login attempts made before a time
+func (r *LoginAttemptRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.LoginAttempt{}).Error
+}
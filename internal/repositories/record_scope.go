@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecordScope limits grade and attendance queries to the records a caller may see. A nil scope sees
+// every record.
+type RecordScope struct {
+	CourseIDs  []uuid.UUID // Only records in these courses, when not nil
+	StudentIDs []uuid.UUID // Only records of these students, when not nil
+}
+
+// Allows reports whether the scope includes the record of a student in a course
+func (s *RecordScope) Allows(studentID, courseID uuid.UUID) bool {
+	if s == nil {
+		return true
+	}
+	return (s.CourseIDs == nil || containsID(s.CourseIDs, courseID)) &&
+		(s.StudentIDs == nil || containsID(s.StudentIDs, studentID))
+}
+
+// AllowsCourse reports whether the scope includes any records of a course
+func (s *RecordScope) AllowsCourse(courseID uuid.UUID) bool {
+	return s == nil || s.CourseIDs == nil || containsID(s.CourseIDs, courseID)
+}
+
+// AllowsStudent reports whether the scope includes any records of a student
+func (s *RecordScope) AllowsStudent(studentID uuid.UUID) bool {
+	return s == nil || s.StudentIDs == nil || containsID(s.StudentIDs, studentID)
+}
+
+// apply limits a query over a table with student_id and course_id columns to the scope
+func (s *RecordScope) apply(db *gorm.DB) *gorm.DB {
+	if s == nil {
+		return db
+	}
+	if s.CourseIDs != nil {
+		db = db.Where("course_id IN ?", s.CourseIDs)
+	}
+	if s.StudentIDs != nil {
+		db = db.Where("student_id IN ?", s.StudentIDs)
+	}
+	return db
+}
+
+// containsID reports whether ids includes id
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
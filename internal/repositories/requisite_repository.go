@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequisiteRepository defines the interface for course prerequisite and corequisite repository
+type RequisiteRepository interface {
+	GetGroups(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteGroup, error)
+	GetAllGroups(ctx context.Context, kind models.RequisiteKind) ([]models.RequisiteGroup, error)
+	ReplaceGroups(ctx context.Context, courseID uuid.UUID, groups []models.RequisiteGroup) error
+	GetOverrides(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteOverride, error)
+}
+
+// RequisiteRepositoryImpl implements the RequisiteRepository interface
+type RequisiteRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRequisiteRepository creates a new instance of RequisiteRepositoryImpl
+func NewRequisiteRepository(db *gorm.DB) RequisiteRepository {
+	return &RequisiteRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetGroups gets the requisite groups of a course with their options
+func (r *RequisiteRepositoryImpl) GetGroups(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteGroup, error) {
+	var groups []models.RequisiteGroup
+	err := r.db.WithContext(ctx).Preload("Options.RequiredCourse").
+		Where("course_id = ?", courseID).
+		Order("kind, created_at").
+		Find(&groups).Error
+	return groups, err
+}
+
+// GetAllGroups gets every requisite group of a kind across all courses
+func (r *RequisiteRepositoryImpl) GetAllGroups(ctx context.Context, kind models.RequisiteKind) ([]models.RequisiteGroup, error) {
+	var groups []models.RequisiteGroup
+	err := r.db.WithContext(ctx).Preload("Options").Where("kind = ?", kind).Find(&groups).Error
+	return groups, err
+}
+
+// ReplaceGroups replaces all requisite groups of a course
+func (r *RequisiteRepositoryImpl) ReplaceGroups(ctx context.Context, courseID uuid.UUID, groups []models.RequisiteGroup) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("group_id IN (?)", tx.Model(&models.RequisiteGroup{}).Select("id").Where("course_id = ?", courseID)).
+			Delete(&models.RequisiteOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("course_id = ?", courseID).Delete(&models.RequisiteGroup{}).Error; err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return nil
+		}
+		return tx.Create(&groups).Error
+	})
+}
+
+// GetOverrides gets the requisite overrides granted for a course, newest first
+func (r *RequisiteRepositoryImpl) GetOverrides(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteOverride, error) {
+	var overrides []models.RequisiteOverride
+	err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Order("created_at DESC").Find(&overrides).Error
+	return overrides, err
+}
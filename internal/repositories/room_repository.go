@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoomFilter narrows a room search; zero values match every room
+type RoomFilter struct {
+	Type        models.RoomType
+	Building    string
+	MinCapacity int
+	Equipment   []string
+}
+
+// RoomRepository defines the interface for room and room booking repository
+type RoomRepository interface {
+	Create(ctx context.Context, room *models.Room) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Room, error)
+	GetAll(ctx context.Context, filter RoomFilter) ([]models.Room, error)
+	Update(ctx context.Context, room *models.Room) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	IsInUse(ctx context.Context, id uuid.UUID) (bool, error)
+	CreateBooking(ctx context.Context, booking *models.RoomBooking) (*models.RoomBooking, error)
+	GetBookingByID(ctx context.Context, id uuid.UUID) (*models.RoomBooking, error)
+	GetBookings(ctx context.Context, roomID uuid.UUID, from, to *time.Time) ([]models.RoomBooking, error)
+	DeleteBooking(ctx context.Context, id uuid.UUID) error
+}
+
+// RoomRepositoryImpl implements the RoomRepository interface
+type RoomRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRoomRepository creates a new instance of RoomRepositoryImpl
+func NewRoomRepository(db *gorm.DB) RoomRepository {
+	return &RoomRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create creates a new room
+func (r *RoomRepositoryImpl) Create(ctx context.Context, room *models.Room) error {
+	return r.db.WithContext(ctx).Create(room).Error
+}
+
+// GetByID retrieves a room by ID
+func (r *RoomRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Room, error) {
+	var room models.Room
+	err := r.db.WithContext(ctx).First(&room, "id = ?", id).Error
+	return &room, err
+}
+
+// GetAll retrieves the rooms matching a filter, ordered by building and name
+func (r *RoomRepositoryImpl) GetAll(ctx context.Context, filter RoomFilter) ([]models.Room, error) {
+	var rooms []models.Room
+	query := r.db.WithContext(ctx)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Building != "" {
+		query = query.Where("building = ?", filter.Building)
+	}
+	if filter.MinCapacity > 0 {
+		query = query.Where("capacity >= ?", filter.MinCapacity)
+	}
+	if err := query.Order("building, name").Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+
+	// Equipment is stored as a JSON list, so match the tags here
+	if len(filter.Equipment) == 0 {
+		return rooms, nil
+	}
+	matched := make([]models.Room, 0, len(rooms))
+	for _, room := range rooms {
+		if room.HasEquipment(filter.Equipment) {
+			matched = append(matched, room)
+		}
+	}
+	return matched, nil
+}
+
+// Update updates a room
+func (r *RoomRepositoryImpl) Update(ctx context.Context, room *models.Room) error {
+	return r.db.WithContext(ctx).Save(room).Error
+}
+
+// Delete deletes a room and its bookings
+func (r *RoomRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("room_id = ?", id).Delete(&models.RoomBooking{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Room{}, "id = ?", id).Error
+	})
+}
+
+// IsInUse reports whether any section or section meeting is held in the room
+func (r *RoomRepositoryImpl) IsInUse(ctx context.Context, id uuid.UUID) (bool, error) {
+	db := r.db.WithContext(ctx)
+
+	var count int64
+	if err := db.Model(&models.Section{}).Where("room_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := db.Model(&models.SectionMeeting{}).Where("room_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateBooking books a room unless another booking overlaps it, in which case that booking is returned.
+// The room row is locked for the duration so concurrent requests cannot double-book it.
+func (r *RoomRepositoryImpl) CreateBooking(ctx context.Context, booking *models.RoomBooking) (*models.RoomBooking, error) {
+	var conflict *models.RoomBooking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&room, "id = ?", booking.RoomID).Error; err != nil {
+			return err
+		}
+
+		var sameDay []models.RoomBooking
+		if err := tx.Where("room_id = ? AND date = ?", booking.RoomID, booking.Date).Find(&sameDay).Error; err != nil {
+			return err
+		}
+		for i := range sameDay {
+			if booking.Overlaps(&models.SectionMeeting{
+				Day:       sameDay[i].Date.Weekday(),
+				StartTime: sameDay[i].StartTime,
+				EndTime:   sameDay[i].EndTime,
+			}) {
+				conflict = &sameDay[i]
+				return nil
+			}
+		}
+
+		return tx.Omit("Room").Create(booking).Error
+	})
+	return conflict, err
+}
+
+// GetBookingByID retrieves a room booking by ID
+func (r *RoomRepositoryImpl) GetBookingByID(ctx context.Context, id uuid.UUID) (*models.RoomBooking, error) {
+	var booking models.RoomBooking
+	err := r.db.WithContext(ctx).Preload("Room").First(&booking, "id = ?", id).Error
+	return &booking, err
+}
+
+// GetBookings retrieves a room's bookings in date order, optionally within a date range
+func (r *RoomRepositoryImpl) GetBookings(ctx context.Context, roomID uuid.UUID, from, to *time.Time) ([]models.RoomBooking, error) {
+	var bookings []models.RoomBooking
+	query := r.db.WithContext(ctx).Where("room_id = ?", roomID)
+	if from != nil {
+		query = query.Where("date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("date <= ?", *to)
+	}
+	err := query.Order("date, start_time").Find(&bookings).Error
+	return bookings, err
+}
+
+// DeleteBooking cancels a room booking
+func (r *RoomRepositoryImpl) DeleteBooking(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.RoomBooking{}, "id = ?", id).Error
+}
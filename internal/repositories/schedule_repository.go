@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduleRepository defines the interface for timetable generation repository
+type ScheduleRepository interface {
+	CreateRun(ctx context.Context, run *models.ScheduleRun) (bool, error)
+	GetRunByID(ctx context.Context, id uuid.UUID) (*models.ScheduleRun, error)
+	GetRuns(ctx context.Context, termID *uuid.UUID) ([]models.ScheduleRun, error)
+	UpdateRun(ctx context.Context, run *models.ScheduleRun) error
+	SaveResult(ctx context.Context, run *models.ScheduleRun, assignments []models.ScheduleAssignment) error
+	Publish(ctx context.Context, run *models.ScheduleRun, check PublishCheck) error
+	GetAvailability(ctx context.Context, teacherID uuid.UUID) ([]models.TeacherAvailability, error)
+	GetAllAvailability(ctx context.Context) ([]models.TeacherAvailability, error)
+	ReplaceAvailability(ctx context.Context, teacherID uuid.UUID, windows []models.TeacherAvailability) error
+	CreateRequest(ctx context.Context, request *models.CourseRequest) error
+	GetRequestByID(ctx context.Context, id uuid.UUID) (*models.CourseRequest, error)
+	GetRequests(ctx context.Context, studentID uuid.UUID, termID *uuid.UUID) ([]models.CourseRequest, error)
+	GetTermRequests(ctx context.Context, termID uuid.UUID) ([]models.CourseRequest, error)
+	DeleteRequest(ctx context.Context, id uuid.UUID) error
+}
+
+// PublishCheck vets the meetings a published run gives its sections. It runs with every section of the
+// run locked, and is given the sections with their new meetings.
+type PublishCheck func(ctx context.Context, sections []models.Section) error
+
+// ScheduleRepositoryImpl implements the ScheduleRepository interface
+type ScheduleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository creates a new instance of ScheduleRepositoryImpl
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &ScheduleRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateRun creates a new timetable run unless its term already has a pending or running one. It
+// reports whether the run was created.
+func (r *ScheduleRepositoryImpl) CreateRun(ctx context.Context, run *models.ScheduleRun) (bool, error) {
+	result := r.db.WithContext(ctx).Omit("Term", "Assignments").Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetRunByID retrieves a timetable run with its draft assignments
+func (r *ScheduleRepositoryImpl) GetRunByID(ctx context.Context, id uuid.UUID) (*models.ScheduleRun, error) {
+	var run models.ScheduleRun
+	err := r.db.WithContext(ctx).
+		Preload("Term").
+		Preload("Assignments", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("day, start_time")
+		}).
+		Preload("Assignments.Section.Course").
+		Preload("Assignments.Room").
+		First(&run, "id = ?", id).Error
+	return &run, err
+}
+
+// GetRuns retrieves timetable runs, newest first, optionally limited to a term
+func (r *ScheduleRepositoryImpl) GetRuns(ctx context.Context, termID *uuid.UUID) ([]models.ScheduleRun, error) {
+	var runs []models.ScheduleRun
+	query := r.db.WithContext(ctx).Preload("Term")
+	if termID != nil {
+		query = query.Where("term_id = ?", *termID)
+	}
+	err := query.Order("created_at DESC").Find(&runs).Error
+	return runs, err
+}
+
+// UpdateRun saves a run's status and outcome
+func (r *ScheduleRepositoryImpl) UpdateRun(ctx context.Context, run *models.ScheduleRun) error {
+	return r.db.WithContext(ctx).Omit("Term", "Assignments").Save(run).Error
+}
+
+// SaveResult stores a run's draft assignments together with its outcome
+func (r *ScheduleRepositoryImpl) SaveResult(ctx context.Context, run *models.ScheduleRun, assignments []models.ScheduleAssignment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("run_id = ?", run.ID).Delete(&models.ScheduleAssignment{}).Error; err != nil {
+			return err
+		}
+		if len(assignments) > 0 {
+			if err := tx.Omit("Section", "Room").Create(&assignments).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Term", "Assignments").Save(run).Error
+	})
+}
+
+// Publish makes the draft assignments of the run the meetings of its sections, once the check accepts
+// them. Meetings the run leaves unchanged keep their IDs.
+func (r *ScheduleRepositoryImpl) Publish(ctx context.Context, run *models.ScheduleRun, check PublishCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		meetings := make(map[uuid.UUID][]models.SectionMeeting)
+		for _, assignment := range run.Assignments {
+			meetings[assignment.SectionID] = append(meetings[assignment.SectionID], models.SectionMeeting{
+				SectionID: assignment.SectionID,
+				Day:       assignment.Day,
+				StartTime: assignment.StartTime,
+				EndTime:   assignment.EndTime,
+				RoomID:    assignment.RoomID,
+			})
+		}
+
+		// Sections are locked in a fixed order so concurrent publishes cannot deadlock
+		sectionIDs := make([]uuid.UUID, 0, len(meetings))
+		for sectionID := range meetings {
+			sectionIDs = append(sectionIDs, sectionID)
+		}
+		sort.Slice(sectionIDs, func(i, j int) bool {
+			return sectionIDs[i].String() < sectionIDs[j].String()
+		})
+		sections := make([]models.Section, 0, len(sectionIDs))
+		for _, sectionID := range sectionIDs {
+			section, err := lockSection(tx, sectionID)
+			if err != nil {
+				return err
+			}
+			section.Meetings = meetings[sectionID]
+			sections = append(sections, *section)
+		}
+		if err := check(ctx, sections); err != nil {
+			return err
+		}
+
+		for _, sectionID := range sectionIDs {
+			if err := replaceMeetings(tx, sectionID, meetings[sectionID]); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		run.Status = models.ScheduleRunPublished
+		run.PublishedAt = &now
+		return tx.Omit("Term", "Assignments").Save(run).Error
+	})
+}
+
+// GetAvailability gets a teacher's availability windows
+func (r *ScheduleRepositoryImpl) GetAvailability(ctx context.Context, teacherID uuid.UUID) ([]models.TeacherAvailability, error) {
+	var windows []models.TeacherAvailability
+	err := r.db.WithContext(ctx).Where("teacher_id = ?", teacherID).Order("day, start_time").Find(&windows).Error
+	return windows, err
+}
+
+// GetAllAvailability gets the availability windows of every teacher
+func (r *ScheduleRepositoryImpl) GetAllAvailability(ctx context.Context) ([]models.TeacherAvailability, error) {
+	var windows []models.TeacherAvailability
+	err := r.db.WithContext(ctx).Find(&windows).Error
+	return windows, err
+}
+
+// ReplaceAvailability replaces a teacher's availability windows
+func (r *ScheduleRepositoryImpl) ReplaceAvailability(ctx context.Context, teacherID uuid.UUID, windows []models.TeacherAvailability) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("teacher_id = ?", teacherID).Delete(&models.TeacherAvailability{}).Error; err != nil {
+			return err
+		}
+		if len(windows) == 0 {
+			return nil
+		}
+		return tx.Create(&windows).Error
+	})
+}
+
+// CreateRequest creates a student course request
+func (r *ScheduleRepositoryImpl) CreateRequest(ctx context.Context, request *models.CourseRequest) error {
+	return r.db.WithContext(ctx).Omit("Course", "Term").Create(request).Error
+}
+
+// GetRequestByID retrieves a course request
+func (r *ScheduleRepositoryImpl) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.CourseRequest, error) {
+	var request models.CourseRequest
+	err := r.db.WithContext(ctx).First(&request, "id = ?", id).Error
+	return &request, err
+}
+
+// GetRequests gets a student's course requests, optionally limited to a term
+func (r *ScheduleRepositoryImpl) GetRequests(ctx context.Context, studentID uuid.UUID, termID *uuid.UUID) ([]models.CourseRequest, error) {
+	var requests []models.CourseRequest
+	query := r.db.WithContext(ctx).Preload("Course").Preload("Term").Where("student_id = ?", studentID)
+	if termID != nil {
+		query = query.Where("term_id = ?", *termID)
+	}
+	err := query.Order("created_at").Find(&requests).Error
+	return requests, err
+}
+
+// GetTermRequests gets every course request for a term
+func (r *ScheduleRepositoryImpl) GetTermRequests(ctx context.Context, termID uuid.UUID) ([]models.CourseRequest, error) {
+	var requests []models.CourseRequest
+	err := r.db.WithContext(ctx).Where("term_id = ?", termID).Find(&requests).Error
+	return requests, err
+}
+
+// DeleteRequest deletes a course request
+func (r *ScheduleRepositoryImpl) DeleteRequest(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.CourseRequest{}, "id = ?", id).Error
+}
@@ -0,0 +1,546 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SectionRepository defines the interface for section repository
+type SectionRepository interface {
+	Create(ctx context.Context, section *models.Section) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Section, error)
+	GetAll(ctx context.Context, courseID, termID *uuid.UUID) ([]models.Section, error)
+	Update(ctx context.Context, section *models.Section, check EnrollmentCheck) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Enroll(ctx context.Context, sectionID, studentID uuid.UUID, check EnrollmentCheck, override *models.RequisiteOverride) (*models.EnrollmentResult, error)
+	Drop(ctx context.Context, sectionID, studentID uuid.UUID, check EnrollmentCheck) error
+	GetSeatCount(ctx context.Context, sectionID uuid.UUID) (*models.SeatCount, error)
+	GetWaitlist(ctx context.Context, sectionID uuid.UUID) ([]models.WaitlistEntry, error)
+	FindWaitlistByStudent(ctx context.Context, studentID uuid.UUID) ([]models.WaitlistEntry, error)
+	AddTeacher(ctx context.Context, section *models.Section, teacherID uuid.UUID) error
+	RemoveTeacher(ctx context.Context, section *models.Section, teacherID uuid.UUID) error
+	FindByStudent(ctx context.Context, studentID uuid.UUID, termID *uuid.UUID) ([]models.Section, error)
+	FindByTeacher(ctx context.Context, teacherID uuid.UUID, termID *uuid.UUID) ([]models.Section, error)
+	FindStudentSection(ctx context.Context, studentID, courseID, termID uuid.UUID) (*models.Section, error)
+	FindByRoom(ctx context.Context, roomID uuid.UUID, termID uuid.UUID) ([]models.Section, error)
+	FindByMeeting(ctx context.Context, meetingID uuid.UUID) (*models.Section, error)
+}
+
+// ErrSeatRefused marks the errors of an EnrollmentCheck that refuse a student a seat, as opposed to
+// failing to check
+var ErrSeatRefused = errors.New("seat refused")
+
+// EnrollmentCheck refuses a student a seat in a section, as when it clashes with their timetable. It runs
+// while the section and the student are locked, with the section's meetings loaded, so neither the section's
+// roster nor the student's sections can change meanwhile. The repository's lookups of a student's sections
+// made with its context read from the enrollment's transaction.
+type EnrollmentCheck func(ctx context.Context, section *models.Section, studentID uuid.UUID) error
+
+// enrollmentTx is the context key of the transaction an EnrollmentCheck runs in
+type enrollmentTx struct{}
+
+// conn returns the enrollment transaction of the context, if any, or the repository's database
+func (r *SectionRepositoryImpl) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(enrollmentTx{}).(*gorm.DB); ok {
+		return tx
+	}
+	return r.db.WithContext(ctx)
+}
+
+// runCheck locks a student and runs an EnrollmentCheck for them within a transaction. Locking the student
+// serializes their enrollments, so two requests for sections of the same course cannot both pass the check.
+func runCheck(ctx context.Context, tx *gorm.DB, check EnrollmentCheck, section *models.Section, studentID uuid.UUID) error {
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Find(&models.Student{}, "id = ?", studentID).Error; err != nil {
+		return err
+	}
+	return check(context.WithValue(ctx, enrollmentTx{}, tx), section, studentID)
+}
+
+// SectionRepositoryImpl implements the SectionRepository interface
+type SectionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSectionRepository creates a new instance of SectionRepositoryImpl
+func NewSectionRepository(db *gorm.DB) SectionRepository {
+	return &SectionRepositoryImpl{
+		db: db,
+	}
+}
+
+// preloadSection loads a section's course, term, room and meetings
+func preloadSection(db *gorm.DB) *gorm.DB {
+	return db.Preload("Course").Preload("Term").Preload("Room").
+		Preload("Meetings", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("day, start_time")
+		}).
+		Preload("Meetings.Room")
+}
+
+// Create creates a new section together with its meetings
+func (r *SectionRepositoryImpl) Create(ctx context.Context, section *models.Section) error {
+	return r.db.WithContext(ctx).Omit("Course", "Term", "Room", "Teachers", "Students").Create(section).Error
+}
+
+// GetByID retrieves a section with its meetings, teachers and roster
+func (r *SectionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Section, error) {
+	var section models.Section
+	err := preloadSection(r.db.WithContext(ctx)).
+		Preload("Teachers").
+		Preload("Students").
+		First(&section, "id = ?", id).Error
+	return &section, err
+}
+
+// GetAll retrieves sections, optionally limited to a course and term
+func (r *SectionRepositoryImpl) GetAll(ctx context.Context, courseID, termID *uuid.UUID) ([]models.Section, error) {
+	var sections []models.Section
+	query := preloadSection(r.db.WithContext(ctx)).Preload("Teachers")
+	if courseID != nil {
+		query = query.Where("course_id = ?", *courseID)
+	}
+	if termID != nil {
+		query = query.Where("term_id = ?", *termID)
+	}
+	err := query.Order("code").Find(&sections).Error
+	return sections, err
+}
+
+// Update updates a section and its meetings
+func (r *SectionRepositoryImpl) Update(ctx context.Context, section *models.Section, check EnrollmentCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockSection(tx, section.ID); err != nil {
+			return err
+		}
+		if err := replaceMeetings(tx, section.ID, section.Meetings); err != nil {
+			return err
+		}
+		if err := tx.Omit("Course", "Term", "Room", "Meetings", "Teachers", "Students").Save(section).Error; err != nil {
+			return err
+		}
+
+		// A larger capacity frees seats for waitlisted students
+		return promoteWaitlist(ctx, tx, section, check)
+	})
+}
+
+// Delete deletes a section, its meetings, waitlist and teacher assignments
+func (r *SectionRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("section_id = ?", id).Delete(&models.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("section_id = ?", id).Delete(&models.SectionTeacher{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("section_id = ?", id).Delete(&models.SectionMeeting{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Section{}, "id = ?", id).Error
+	})
+}
+
+// Enroll gives a student who passes the check a seat in a section, or a place on its waitlist when the
+// section is full. The section row is locked for the duration so concurrent requests cannot oversell seats.
+// A requisite override the check left with unmet requisites is recorded with the enrollment.
+func (r *SectionRepositoryImpl) Enroll(ctx context.Context, sectionID, studentID uuid.UUID, check EnrollmentCheck, override *models.RequisiteOverride) (*models.EnrollmentResult, error) {
+	result := &models.EnrollmentResult{SectionID: sectionID, StudentID: studentID}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		section, err := lockSection(tx, sectionID)
+		if err != nil {
+			return err
+		}
+		if err := runCheck(ctx, tx, check, section, studentID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.SectionStudent{}).
+			Where("section_id = ? AND student_id = ?", sectionID, studentID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("student is already enrolled in this section")
+		}
+		if err := tx.Model(&models.WaitlistEntry{}).
+			Where("section_id = ? AND student_id = ?", sectionID, studentID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("student is already on the waitlist for this section")
+		}
+
+		enrolled, err := countEnrolled(tx, sectionID)
+		if err != nil {
+			return err
+		}
+
+		// Seats go to the waitlist first, so a newcomer only gets one if nobody is waiting
+		waiting, err := countWaitlisted(tx, sectionID)
+		if err != nil {
+			return err
+		}
+		if waiting == 0 && (section.Capacity == 0 || enrolled < int64(section.Capacity)) {
+			result.Status = models.EnrollmentEnrolled
+			if err := addToRoster(tx, section, studentID); err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Create(&models.WaitlistEntry{SectionID: sectionID, StudentID: studentID}).Error; err != nil {
+				return err
+			}
+			result.Status = models.EnrollmentWaitlisted
+			result.Position = int(waiting) + 1
+		}
+
+		if override == nil || len(override.Unmet) == 0 {
+			return nil
+		}
+		return tx.Create(override).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Drop removes a student from a section roster or waitlist and promotes the waitlisted students who pass
+// the check into any freed seat
+func (r *SectionRepositoryImpl) Drop(ctx context.Context, sectionID, studentID uuid.UUID, check EnrollmentCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		section, err := lockSection(tx, sectionID)
+		if err != nil {
+			return err
+		}
+
+		// Leaving the waitlist frees no seat
+		waitlisted := tx.Where("section_id = ? AND student_id = ?", sectionID, studentID).Delete(&models.WaitlistEntry{})
+		if waitlisted.Error != nil {
+			return waitlisted.Error
+		}
+		if waitlisted.RowsAffected > 0 {
+			return nil
+		}
+
+		removed := tx.Where("section_id = ? AND student_id = ?", sectionID, studentID).Delete(&models.SectionStudent{})
+		if removed.Error != nil {
+			return removed.Error
+		}
+		if removed.RowsAffected == 0 {
+			return errors.New("student is not enrolled in this section")
+		}
+		if err := tx.Where("student_id = ? AND course_id = ? AND term_id = ?", studentID, section.CourseID, section.TermID).
+			Delete(&models.StudentCourse{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("student_id = ? AND course_id = ? AND term_id = ?", studentID, section.CourseID, section.TermID).
+			Delete(&models.CourseEnrollment{}).Error; err != nil {
+			return err
+		}
+
+		return promoteWaitlist(ctx, tx, section, check)
+	})
+}
+
+// GetSeatCount counts the enrolled and waitlisted students of a section
+func (r *SectionRepositoryImpl) GetSeatCount(ctx context.Context, sectionID uuid.UUID) (*models.SeatCount, error) {
+	db := r.db.WithContext(ctx)
+
+	var section models.Section
+	if err := db.First(&section, "id = ?", sectionID).Error; err != nil {
+		return nil, err
+	}
+	enrolled, err := countEnrolled(db, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	waiting, err := countWaitlisted(db, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := &models.SeatCount{
+		SectionID:  sectionID,
+		Capacity:   section.Capacity,
+		Enrolled:   int(enrolled),
+		Waitlisted: int(waiting),
+	}
+	if section.Capacity > 0 {
+		available := section.Capacity - int(enrolled)
+		if available < 0 {
+			available = 0
+		}
+		seats.Available = &available
+	}
+	return seats, nil
+}
+
+// GetWaitlist gets a section's waitlist in the order seats will be offered
+func (r *SectionRepositoryImpl) GetWaitlist(ctx context.Context, sectionID uuid.UUID) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.WithContext(ctx).Where("section_id = ?", sectionID).
+		Order("created_at, student_id").Find(&entries).Error
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	return entries, err
+}
+
+// FindWaitlistByStudent finds the waitlists a student is on, with their position in each
+func (r *SectionRepositoryImpl) FindWaitlistByStudent(ctx context.Context, studentID uuid.UUID) ([]models.WaitlistEntry, error) {
+	db := r.db.WithContext(ctx)
+
+	var entries []models.WaitlistEntry
+	if err := db.Preload("Section.Course").Preload("Section.Term").Where("student_id = ?", studentID).
+		Order("created_at").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		var ahead int64
+		if err := db.Model(&models.WaitlistEntry{}).
+			Where("section_id = ? AND (created_at < ? OR (created_at = ? AND student_id < ?))",
+				entries[i].SectionID, entries[i].CreatedAt, entries[i].CreatedAt, studentID).
+			Count(&ahead).Error; err != nil {
+			return nil, err
+		}
+		entries[i].Position = int(ahead) + 1
+	}
+	return entries, nil
+}
+
+// lockSection loads a section and its meetings with a row lock held until the transaction ends
+func lockSection(tx *gorm.DB, sectionID uuid.UUID) (*models.Section, error) {
+	var section models.Section
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Meetings").First(&section, "id = ?", sectionID).Error
+	return &section, err
+}
+
+// countEnrolled counts the students on a section roster
+func countEnrolled(db *gorm.DB, sectionID uuid.UUID) (int64, error) {
+	var count int64
+	err := db.Model(&models.SectionStudent{}).Where("section_id = ?", sectionID).Count(&count).Error
+	return count, err
+}
+
+// countWaitlisted counts the students waiting for a seat in a section
+func countWaitlisted(db *gorm.DB, sectionID uuid.UUID) (int64, error) {
+	var count int64
+	err := db.Model(&models.WaitlistEntry{}).Where("section_id = ?", sectionID).Count(&count).Error
+	return count, err
+}
+
+// addToRoster adds a student to a section roster and records the course enrollment for the term
+func addToRoster(tx *gorm.DB, section *models.Section, studentID uuid.UUID) error {
+	if err := tx.Create(&models.SectionStudent{SectionID: section.ID, StudentID: studentID}).Error; err != nil {
+		return err
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.CourseEnrollment{
+		StudentID: studentID,
+		CourseID:  section.CourseID,
+		TermID:    section.TermID,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "course_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"term_id"}),
+	}).Create(&models.StudentCourse{
+		StudentID: studentID,
+		CourseID:  section.CourseID,
+		TermID:    &section.TermID,
+	}).Error
+}
+
+// replaceMeetings makes the given meetings a section's meetings. Meetings keep their IDs, which attendance
+// records and check-ins refer to: a meeting with the ID of one of the section's meetings, or else the same
+// day and times, is updated in place; others are added, and the section's remaining meetings are soft
+// deleted. The caller must hold the section lock.
+func replaceMeetings(tx *gorm.DB, sectionID uuid.UUID, meetings []models.SectionMeeting) error {
+	var existing []models.SectionMeeting
+	if err := tx.Where("section_id = ?", sectionID).Find(&existing).Error; err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]bool, len(existing))
+	for _, meeting := range existing {
+		byID[meeting.ID] = true
+	}
+
+	kept := make(map[uuid.UUID]bool, len(meetings))
+	for i := range meetings {
+		meeting := &meetings[i]
+		meeting.SectionID = sectionID
+		if !byID[meeting.ID] || kept[meeting.ID] {
+			meeting.ID = uuid.Nil
+			for _, other := range existing {
+				if !kept[other.ID] && other.Day == meeting.Day && other.StartTime == meeting.StartTime && other.EndTime == meeting.EndTime {
+					meeting.ID = other.ID
+					break
+				}
+			}
+		}
+
+		if meeting.ID == uuid.Nil {
+			if err := tx.Omit("Room").Create(meeting).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Model(&models.SectionMeeting{}).Where("id = ?", meeting.ID).
+			Select("day", "start_time", "end_time", "room_id").
+			Updates(meeting).Error; err != nil {
+			return err
+		}
+		kept[meeting.ID] = true
+	}
+
+	removed := make([]uuid.UUID, 0, len(existing))
+	for _, meeting := range existing {
+		if !kept[meeting.ID] {
+			removed = append(removed, meeting.ID)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	return tx.Where("id IN ?", removed).Delete(&models.SectionMeeting{}).Error
+}
+
+// promoteWaitlist moves waitlisted students onto the roster, in waitlist order, while seats are available.
+// Students the check refuses a seat, as when they joined another section of the course since, are passed
+// over and stay on the waitlist. The caller must hold the section lock.
+func promoteWaitlist(ctx context.Context, tx *gorm.DB, section *models.Section, check EnrollmentCheck) error {
+	enrolled, err := countEnrolled(tx, section.ID)
+	if err != nil {
+		return err
+	}
+	if section.Capacity > 0 && enrolled >= int64(section.Capacity) {
+		return nil
+	}
+
+	var waiting []models.WaitlistEntry
+	if err := tx.Where("section_id = ?", section.ID).Order("created_at, student_id").Find(&waiting).Error; err != nil {
+		return err
+	}
+	for _, next := range waiting {
+		if section.Capacity > 0 && enrolled >= int64(section.Capacity) {
+			return nil
+		}
+
+		err := runCheck(ctx, tx, check, section, next.StudentID)
+		if errors.Is(err, ErrSeatRefused) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("section_id = ? AND student_id = ?", section.ID, next.StudentID).
+			Delete(&models.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		if err := addToRoster(tx, section, next.StudentID); err != nil {
+			return err
+		}
+		enrolled++
+	}
+	return nil
+}
+
+// AddTeacher assigns a teacher to a section and records that the teacher teaches the course
+func (r *SectionRepositoryImpl) AddTeacher(ctx context.Context, section *models.Section, teacherID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.SectionTeacher{SectionID: section.ID, TeacherID: teacherID}).Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO teacher_courses (teacher_id, course_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			teacherID, section.CourseID).Error
+	})
+}
+
+// RemoveTeacher removes a teacher from a section, and from the course once they teach no other section of it
+func (r *SectionRepositoryImpl) RemoveTeacher(ctx context.Context, section *models.Section, teacherID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("section_id = ? AND teacher_id = ?", section.ID, teacherID).
+			Delete(&models.SectionTeacher{}).Error; err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&models.SectionTeacher{}).
+			Joins("JOIN sections ON sections.id = section_teachers.section_id AND sections.deleted_at IS NULL").
+			Where("section_teachers.teacher_id = ? AND sections.course_id = ?", teacherID, section.CourseID).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return nil
+		}
+		return tx.Exec("DELETE FROM teacher_courses WHERE teacher_id = ? AND course_id = ?", teacherID, section.CourseID).Error
+	})
+}
+
+// FindByStudent finds the sections a student is enrolled in, optionally limited to a term
+func (r *SectionRepositoryImpl) FindByStudent(ctx context.Context, studentID uuid.UUID, termID *uuid.UUID) ([]models.Section, error) {
+	var sections []models.Section
+	query := preloadSection(r.conn(ctx)).Preload("Teachers").
+		Joins("JOIN section_students ON section_students.section_id = sections.id").
+		Where("section_students.student_id = ?", studentID)
+	if termID != nil {
+		query = query.Where("sections.term_id = ?", *termID)
+	}
+	err := query.Find(&sections).Error
+	return sections, err
+}
+
+// FindByTeacher finds the sections a teacher is assigned to, optionally limited to a term
+func (r *SectionRepositoryImpl) FindByTeacher(ctx context.Context, teacherID uuid.UUID, termID *uuid.UUID) ([]models.Section, error) {
+	var sections []models.Section
+	query := preloadSection(r.db.WithContext(ctx)).
+		Joins("JOIN section_teachers ON section_teachers.section_id = sections.id").
+		Where("section_teachers.teacher_id = ?", teacherID)
+	if termID != nil {
+		query = query.Where("sections.term_id = ?", *termID)
+	}
+	err := query.Find(&sections).Error
+	return sections, err
+}
+
+// FindStudentSection finds the section of a course a student is enrolled in for a term
+func (r *SectionRepositoryImpl) FindStudentSection(ctx context.Context, studentID, courseID, termID uuid.UUID) (*models.Section, error) {
+	var section models.Section
+	err := r.conn(ctx).
+		Joins("JOIN section_students ON section_students.section_id = sections.id").
+		Where("section_students.student_id = ? AND sections.course_id = ? AND sections.term_id = ?", studentID, courseID, termID).
+		First(&section).Error
+	return &section, err
+}
+
+// FindByRoom finds the sections of a term that meet in a room, either by default or for some meetings
+func (r *SectionRepositoryImpl) FindByRoom(ctx context.Context, roomID uuid.UUID, termID uuid.UUID) ([]models.Section, error) {
+	var sections []models.Section
+	db := r.db.WithContext(ctx)
+	err := preloadSection(db).
+		Where("term_id = ?", termID).
+		Where(db.Where("room_id = ?", roomID).
+			Or("id IN (?)", db.Model(&models.SectionMeeting{}).Select("section_id").Where("room_id = ?", roomID))).
+		Find(&sections).Error
+	return sections, err
+}
+
+// FindByMeeting finds the section a meeting belongs to, with its meetings and teachers
+func (r *SectionRepositoryImpl) FindByMeeting(ctx context.Context, meetingID uuid.UUID) (*models.Section, error) {
+	var section models.Section
+	db := r.db.WithContext(ctx)
+	err := preloadSection(db).Preload("Teachers").
+		Where("id IN (?)", db.Model(&models.SectionMeeting{}).Select("section_id").Where("id = ?", meetingID)).
+		First(&section).Error
+	return &section, err
+}
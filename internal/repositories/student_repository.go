@@ -17,9 +17,7 @@ type StudentRepository interface {
 	Update(ctx context.Context, student *models.Student) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByEmail(ctx context.Context, email string) (*models.Student, error)
-	AddCourse(ctx context.Context, studentID, courseID uuid.UUID) error
-	RemoveCourse(ctx context.Context, studentID, courseID uuid.UUID) error
-	GetCourses(ctx context.Context, studentID uuid.UUID) ([]models.Course, error)
+	GetCourses(ctx context.Context, studentID uuid.UUID, termID *uuid.UUID) ([]models.Course, error)
 }
 
 // StudentRepositoryImpl implements the StudentRepository interface
@@ -80,20 +78,18 @@ func (r *StudentRepositoryImpl) FindByEmail(ctx context.Context, email string) (
 	return &student, err
 }
 
-// AddCourse adds a course to a student
-func (r *StudentRepositoryImpl) AddCourse(ctx context.Context, studentID, courseID uuid.UUID) error {
-	return r.db.WithContext(ctx).Exec("INSERT INTO student_courses (student_id, course_id) VALUES (?, ?)", studentID, courseID).Error
-}
-
-// RemoveCourse removes a course from a student
-func (r *StudentRepositoryImpl) RemoveCourse(ctx context.Context, studentID, courseID uuid.UUID) error {
-	return r.db.WithContext(ctx).Exec("DELETE FROM student_courses WHERE student_id = ? AND course_id = ?", studentID, courseID).Error
-}
-
-// GetCourses gets all courses for a student
-func (r *StudentRepositoryImpl) GetCourses(ctx context.Context, studentID uuid.UUID) ([]models.Course, error) {
+// GetCourses gets all courses for a student, optionally limited to a term
+func (r *StudentRepositoryImpl) GetCourses(ctx context.Context, studentID uuid.UUID, termID *uuid.UUID) ([]models.Course, error) {
 	var courses []models.Course
+	if termID != nil {
+		// Earlier terms are kept in the enrollment history, even for courses retaken since
+		err := r.db.WithContext(ctx).Joins("JOIN course_enrollments ON course_enrollments.course_id = courses.id").
+			Where("course_enrollments.student_id = ? AND course_enrollments.term_id = ?", studentID, *termID).
+			Find(&courses).Error
+		return courses, err
+	}
 	err := r.db.WithContext(ctx).Joins("JOIN student_courses ON student_courses.course_id = courses.id").
-		Where("student_courses.student_id = ?", studentID).Find(&courses).Error
+		Where("student_courses.student_id = ?", studentID).
+		Find(&courses).Error
 	return courses, err
 }
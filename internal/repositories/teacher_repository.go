@@ -17,8 +17,6 @@ type TeacherRepository interface {
 	Update(ctx context.Context, teacher *models.Teacher) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByEmail(ctx context.Context, email string) (*models.Teacher, error)
-	AddCourse(ctx context.Context, teacherID, courseID uuid.UUID) error
-	RemoveCourse(ctx context.Context, teacherID, courseID uuid.UUID) error
 	GetCourses(ctx context.Context, teacherID uuid.UUID) ([]models.Course, error)
 }
 
@@ -80,16 +78,6 @@ func (r *TeacherRepositoryImpl) FindByEmail(ctx context.Context, email string) (
 	return &teacher, err
 }
 
-// AddCourse adds a course to a teacher
-func (r *TeacherRepositoryImpl) AddCourse(ctx context.Context, teacherID, courseID uuid.UUID) error {
-	return r.db.WithContext(ctx).Exec("INSERT INTO teacher_courses (teacher_id, course_id) VALUES (?, ?)", teacherID, courseID).Error
-}
-
-// RemoveCourse removes a course from a teacher
-func (r *TeacherRepositoryImpl) RemoveCourse(ctx context.Context, teacherID, courseID uuid.UUID) error {
-	return r.db.WithContext(ctx).Exec("DELETE FROM teacher_courses WHERE teacher_id = ? AND course_id = ?", teacherID, courseID).Error
-}
-
 // GetCourses gets all courses for a teacher
 func (r *TeacherRepositoryImpl) GetCourses(ctx context.Context, teacherID uuid.UUID) ([]models.Course, error) {
 	var courses []models.Course
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TermRepository defines the interface for academic year and term repository
+type TermRepository interface {
+	CreateYear(ctx context.Context, year *models.AcademicYear) error
+	GetYearByID(ctx context.Context, id uuid.UUID) (*models.AcademicYear, error)
+	GetAllYears(ctx context.Context) ([]models.AcademicYear, error)
+	UpdateYear(ctx context.Context, year *models.AcademicYear) error
+	DeleteYear(ctx context.Context, id uuid.UUID) error
+	Create(ctx context.Context, term *models.Term) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Term, error)
+	GetAll(ctx context.Context) ([]models.Term, error)
+	Update(ctx context.Context, term *models.Term) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	IsInUse(ctx context.Context, term *models.Term) (bool, error)
+	FindByName(ctx context.Context, name string) (*models.Term, error)
+	FindCurrent(ctx context.Context) (*models.Term, error)
+	FindByDate(ctx context.Context, date time.Time) (*models.Term, error)
+	SetCurrent(ctx context.Context, id uuid.UUID) error
+	GetCalendar(ctx context.Context, termID uuid.UUID) (*models.SchoolCalendar, error)
+	SaveCalendar(ctx context.Context, calendar *models.SchoolCalendar) error
+}
+
+// TermRepositoryImpl implements the TermRepository interface
+type TermRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTermRepository creates a new instance of TermRepositoryImpl
+func NewTermRepository(db *gorm.DB) TermRepository {
+	return &TermRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateYear creates a new academic year
+func (r *TermRepositoryImpl) CreateYear(ctx context.Context, year *models.AcademicYear) error {
+	return r.db.WithContext(ctx).Omit("Terms").Create(year).Error
+}
+
+// GetYearByID retrieves an academic year and its terms by ID
+func (r *TermRepositoryImpl) GetYearByID(ctx context.Context, id uuid.UUID) (*models.AcademicYear, error) {
+	var year models.AcademicYear
+	err := r.db.WithContext(ctx).Preload("Terms", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("start_date")
+	}).First(&year, "id = ?", id).Error
+	return &year, err
+}
+
+// GetAllYears retrieves all academic years, newest first
+func (r *TermRepositoryImpl) GetAllYears(ctx context.Context) ([]models.AcademicYear, error) {
+	var years []models.AcademicYear
+	err := r.db.WithContext(ctx).Order("start_date DESC").Find(&years).Error
+	return years, err
+}
+
+// UpdateYear updates an academic year
+func (r *TermRepositoryImpl) UpdateYear(ctx context.Context, year *models.AcademicYear) error {
+	return r.db.WithContext(ctx).Omit("Terms").Save(year).Error
+}
+
+// DeleteYear deletes an academic year
+func (r *TermRepositoryImpl) DeleteYear(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.AcademicYear{}, "id = ?", id).Error
+}
+
+// Create creates a new term
+func (r *TermRepositoryImpl) Create(ctx context.Context, term *models.Term) error {
+	return r.db.WithContext(ctx).Create(term).Error
+}
+
+// GetByID retrieves a term by its ID
+func (r *TermRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Term, error) {
+	var term models.Term
+	err := r.db.WithContext(ctx).First(&term, "id = ?", id).Error
+	return &term, err
+}
+
+// GetAll retrieves all terms in date order
+func (r *TermRepositoryImpl) GetAll(ctx context.Context) ([]models.Term, error) {
+	var terms []models.Term
+	err := r.db.WithContext(ctx).Order("start_date").Find(&terms).Error
+	return terms, err
+}
+
+// Update updates a term
+func (r *TermRepositoryImpl) Update(ctx context.Context, term *models.Term) error {
+	return r.db.WithContext(ctx).Save(term).Error
+}
+
+// Delete deletes a term
+func (r *TermRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Term{}, "id = ?", id).Error
+}
+
+// IsInUse reports whether any grade or section belongs to the term. Grades name their term rather than
+// pointing at it.
+func (r *TermRepositoryImpl) IsInUse(ctx context.Context, term *models.Term) (bool, error) {
+	db := r.db.WithContext(ctx)
+
+	var count int64
+	if err := db.Model(&models.Grade{}).Where("term = ?", term.Name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := db.Model(&models.Section{}).Where("term_id = ?", term.ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindByName finds a term by its name
+func (r *TermRepositoryImpl) FindByName(ctx context.Context, name string) (*models.Term, error) {
+	var term models.Term
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&term).Error
+	return &term, err
+}
+
+// FindCurrent finds the term flagged as current
+func (r *TermRepositoryImpl) FindCurrent(ctx context.Context) (*models.Term, error) {
+	var term models.Term
+	err := r.db.WithContext(ctx).Where("is_current = ?", true).First(&term).Error
+	return &term, err
+}
+
+// FindByDate finds the term that contains a date
+func (r *TermRepositoryImpl) FindByDate(ctx context.Context, date time.Time) (*models.Term, error) {
+	var term models.Term
+	day := date.Format("2006-01-02")
+	err := r.db.WithContext(ctx).Where("start_date <= ? AND end_date >= ?", day, day).
+		Order("start_date DESC").First(&term).Error
+	return &term, err
+}
+
+// SetCurrent flags a term as the current term and clears the flag on all others
+func (r *TermRepositoryImpl) SetCurrent(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Term{}).Where("is_current = ?", true).Update("is_current", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Term{}).Where("id = ?", id).Update("is_current", true).Error
+	})
+}
+
+// GetCalendar retrieves a term's school calendar with its days in date order
+func (r *TermRepositoryImpl) GetCalendar(ctx context.Context, termID uuid.UUID) (*models.SchoolCalendar, error) {
+	var calendar models.SchoolCalendar
+	err := r.db.WithContext(ctx).Preload("Days", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("date")
+	}).Where("term_id = ?", termID).First(&calendar).Error
+	return &calendar, err
+}
+
+// SaveCalendar creates or replaces a term's school calendar together with its days
+func (r *TermRepositoryImpl) SaveCalendar(ctx context.Context, calendar *models.SchoolCalendar) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.SchoolCalendar
+		err := tx.Where("term_id = ?", calendar.TermID).First(&existing).Error
+		switch {
+		case err == nil:
+			calendar.ID = existing.ID
+			calendar.CreatedAt = existing.CreatedAt
+			if err := tx.Unscoped().Where("calendar_id = ?", existing.ID).Delete(&models.CalendarDay{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Omit("Days").Save(calendar).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Omit("Days").Create(calendar).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		for i := range calendar.Days {
+			calendar.Days[i].ID = uuid.Nil
+			calendar.Days[i].CalendarID = calendar.ID
+		}
+		if len(calendar.Days) == 0 {
+			return nil
+		}
+		return tx.Create(&calendar.Days).Error
+	})
+}
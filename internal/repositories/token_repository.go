@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRepository defines the interface for refresh tokens and revoked access tokens
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	FindRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, token, next *models.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID, validAfter time.Time) error
+	RevokeAccessToken(ctx context.Context, token *models.RevokedToken) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID string, userID uuid.UUID, issuedAt time.Time) (bool, error)
+	CreateUserToken(ctx context.Context, token *models.UserToken) error
+	UseUserToken(ctx context.Context, tokenHash string, purpose models.TokenPurpose) (*models.UserToken, error)
+	DeleteUserTokens(ctx context.Context, userID uuid.UUID, purpose models.TokenPurpose) error
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// TokenRepositoryImpl implements the TokenRepository interface
+type TokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository creates a new instance of TokenRepositoryImpl
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &TokenRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateRefreshToken stores a new refresh token
+func (r *TokenRepositoryImpl) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindRefreshToken finds a refresh token by its hash
+func (r *TokenRepositoryImpl) FindRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	return &token, err
+}
+
+// RotateRefreshToken revokes a refresh token in favour of the next one of its family. It returns
+// gorm.ErrRecordNotFound when the token was revoked in the meantime, as when it is used twice at once.
+func (r *TokenRepositoryImpl) RotateRefreshToken(ctx context.Context, token, next *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", token.ID).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": next.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeFamily revokes every refresh token descended from the same login
+func (r *TokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserTokens revokes all of a user's refresh tokens and refuses their access tokens issued before validAfter
+func (r *TokenRepositoryImpl) RevokeUserTokens(ctx context.Context, userID uuid.UUID, validAfter time.Time) error {
+	// Tokens record when they were issued to the millisecond, so a token issued at validAfter still works
+	validAfter = validAfter.Truncate(time.Millisecond)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", validAfter).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.User{}).
+			Where("id = ?", userID).
+			UpdateColumn("tokens_valid_after", validAfter).Error
+	})
+}
+
+// RevokeAccessToken denies an access token until it expires
+func (r *TokenRepositoryImpl) RevokeAccessToken(ctx context.Context, token *models.RevokedToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// IsAccessTokenRevoked reports whether an access token was revoked, was issued before its user's tokens were
+// last revoked, or belongs to a user who no longer exists
+func (r *TokenRepositoryImpl) IsAccessTokenRevoked(ctx context.Context, tokenID string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE token_id = ?)
+		OR NOT EXISTS (SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL
+			AND (tokens_valid_after IS NULL OR tokens_valid_after <= ?))`, tokenID, userID, issuedAt).
+		Scan(&revoked).Error
+	return revoked, err
+}
+
+// CreateUserToken stores a new user token
+func (r *TokenRepositoryImpl) CreateUserToken(ctx context.Context, token *models.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// UseUserToken marks an unused, unexpired user token for a purpose as used and returns it. It returns
+// gorm.ErrRecordNotFound when there is no such token, so a token can only be used once.
+func (r *TokenRepositoryImpl) UseUserToken(ctx context.Context, tokenHash string, purpose models.TokenPurpose) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserToken{}).
+			Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("token_hash = ?", tokenHash).First(&token).Error
+	})
+	return &token, err
+}
+
+// DeleteUserTokens removes a user's tokens for a purpose, so the ones already sent can no longer be used
+func (r *TokenRepositoryImpl) DeleteUserTokens(ctx context.Context, userID uuid.UUID, purpose models.TokenPurpose) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND purpose = ?", userID, purpose).Delete(&models.UserToken{}).Error
+}
+
+// DeleteExpired removes the refresh tokens, revoked access tokens and user tokens that expired before a
+// time, which can no longer be used anyway
+func (r *TokenRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", before).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("expires_at < ?", before).Delete(&models.UserToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", before).Delete(&models.RevokedToken{}).Error
+	})
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository defines the interface for two-factor authentication state and policy
+type TwoFactorRepository interface {
+	UseCounter(ctx context.Context, userID uuid.UUID, counter int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []models.RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	Disable(ctx context.Context, userID uuid.UUID) error
+	GetRequiredRoles(ctx context.Context) ([]models.TwoFactorPolicy, error)
+	IsRequired(ctx context.Context, role string) (bool, error)
+	SetRequiredRoles(ctx context.Context, roles []string, updatedBy uuid.UUID) error
+}
+
+// TwoFactorRepositoryImpl implements the TwoFactorRepository interface
+type TwoFactorRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new instance of TwoFactorRepositoryImpl
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &TwoFactorRepositoryImpl{
+		db: db,
+	}
+}
+
+// UseCounter records the time step of a code a user logged in with, reporting false when a code of that
+// or a later time step was already used, so each code works once
+func (r *TwoFactorRepositoryImpl) UseCounter(ctx context.Context, userID uuid.UUID, counter int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_last_counter < ?", userID, counter).
+		UpdateColumn("totp_last_counter", counter)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReplaceRecoveryCodes replaces a user's recovery codes
+func (r *TwoFactorRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []models.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used, reporting false when there is none
+func (r *TwoFactorRepositoryImpl) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// Disable turns off a user's two-factor authentication, forgetting their secret and recovery codes
+func (r *TwoFactorRepositoryImpl) Disable(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumns(map[string]interface{}{
+			"totp_secret":           "",
+			"totp_last_counter":     0,
+			"two_factor_enabled_at": nil,
+		}).Error
+	})
+}
+
+// GetRequiredRoles retrieves the roles required to use two-factor authentication
+func (r *TwoFactorRepositoryImpl) GetRequiredRoles(ctx context.Context) ([]models.TwoFactorPolicy, error) {
+	var policies []models.TwoFactorPolicy
+	err := r.db.WithContext(ctx).Order("role").Find(&policies).Error
+	return policies, err
+}
+
+// IsRequired reports whether the users of a role are required to use two-factor authentication
+func (r *TwoFactorRepositoryImpl) IsRequired(ctx context.Context, role string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TwoFactorPolicy{}).Where("role = ?", role).Count(&count).Error
+	return count > 0, err
+}
+
+// SetRequiredRoles replaces the roles required to use two-factor authentication
+func (r *TwoFactorRepositoryImpl) SetRequiredRoles(ctx context.Context, roles []string, updatedBy uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.TwoFactorPolicy{}).Error; err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return nil
+		}
+		policies := make([]models.TwoFactorPolicy, 0, len(roles))
+		for _, role := range roles {
+			policies = append(policies, models.TwoFactorPolicy{Role: role, UpdatedBy: updatedBy})
+		}
+		return tx.Create(&policies).Error
+	})
+}
@@ -18,6 +18,10 @@ type UserRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByRole(ctx context.Context, role string) ([]models.User, error)
+	FindByTeacher(ctx context.Context, teacherID uuid.UUID) (*models.User, error)
+	FindByStudent(ctx context.Context, studentID uuid.UUID) (*models.User, error)
+	FindByGuardian(ctx context.Context, guardianID uuid.UUID) (*models.User, error)
 }
 
 // UserRepositoryImpl implements the UserRepository interface
@@ -84,3 +88,31 @@ func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*mo
 	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	return &user, err
 }
+
+// FindByRole finds the users with a role
+func (r *UserRepositoryImpl) FindByRole(ctx context.Context, role string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Where("role = ?", role).Order("username").Find(&users).Error
+	return users, err
+}
+
+// FindByTeacher finds the login of a teacher
+func (r *UserRepositoryImpl) FindByTeacher(ctx context.Context, teacherID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("teacher_id = ?", teacherID).First(&user).Error
+	return &user, err
+}
+
+// FindByStudent finds the login of a student
+func (r *UserRepositoryImpl) FindByStudent(ctx context.Context, studentID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("student_id = ?", studentID).First(&user).Error
+	return &user, err
+}
+
+// FindByGuardian finds the login of a guardian
+func (r *UserRepositoryImpl) FindByGuardian(ctx context.Context, guardianID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("guardian_id = ?", guardianID).First(&user).Error
+	return &user, err
+}
@@ -0,0 +1,433 @@
+// Package scheduler builds conflict-free weekly timetables for the sections of a term.
+//
+// The solver is greedy: the most constrained sections are placed first, each meeting goes to the
+// feasible slot and room that clashes with the fewest student course requests, and sections that
+// cannot be placed are reported as unresolved instead of failing the whole run.
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Slot is a weekly time range, in minutes after midnight
+type Slot struct {
+	Day   time.Weekday
+	Start int
+	End   int
+}
+
+// Overlaps reports whether two slots fall on the same weekday with overlapping times
+func (s Slot) Overlaps(other Slot) bool {
+	return s.Day == other.Day && s.Start < other.End && other.Start < s.End
+}
+
+// Within reports whether the slot lies inside another slot
+func (s Slot) Within(other Slot) bool {
+	return s.Day == other.Day && s.Start >= other.Start && s.End <= other.End
+}
+
+// Room is a room the solver may place meetings in
+type Room struct {
+	ID       uuid.UUID
+	Capacity int
+	Type     string
+}
+
+// Section is a section to be scheduled.
+// A section with Fixed meetings keeps them and only blocks its teachers, room and students.
+type Section struct {
+	ID         uuid.UUID
+	CourseID   uuid.UUID
+	TeacherIDs []uuid.UUID
+	Capacity   int       // 0 means no limit
+	Room       uuid.UUID // Nil lets the solver pick a room
+	RoomType   string    // Kind of room the solver must pick, empty for any
+	Meetings   int       // Number of weekly meetings to place, each on a different day
+	Fixed      []Placement
+}
+
+// Request is a student's request to take a course in the term
+type Request struct {
+	StudentID uuid.UUID
+	CourseID  uuid.UUID
+}
+
+// Problem is the input of a scheduling run
+type Problem struct {
+	Sections []Section
+	Slots    []Slot
+	Rooms    []Room
+	// Availability lists the windows each teacher can teach in; teachers without windows are always available
+	Availability map[uuid.UUID][]Slot
+	Requests     []Request
+}
+
+// Placement is one meeting placed by the solver, in no room when Room is nil
+type Placement struct {
+	SectionID uuid.UUID
+	Slot      Slot
+	Room      uuid.UUID
+}
+
+// Unresolved describes a section the solver could not place, or a requested course without sections
+type Unresolved struct {
+	SectionID uuid.UUID // Nil for a requested course without sections
+	CourseID  uuid.UUID
+	Reason    string
+}
+
+// Result is the output of a scheduling run
+type Result struct {
+	Placements []Placement
+	Unresolved []Unresolved
+	// StudentConflicts counts pairs of requested courses whose only sections overlap, once per affected student
+	StudentConflicts int
+}
+
+// placed is a meeting occupying teachers, a room and a slot while the solver runs
+type placed struct {
+	section *Section
+	slot    Slot
+	room    uuid.UUID
+}
+
+// solver holds the working state of a run
+type solver struct {
+	problem      Problem
+	placed       []placed
+	sectionCount map[uuid.UUID]int
+	demand       map[uuid.UUID]int
+	// together counts the students requesting both courses of a pair
+	together map[uuid.UUID]map[uuid.UUID]int
+}
+
+// Solve places the meetings of every non-fixed section and returns the best timetable it finds
+func Solve(problem Problem) *Result {
+	s := &solver{
+		problem:      problem,
+		sectionCount: make(map[uuid.UUID]int),
+		demand:       make(map[uuid.UUID]int),
+		together:     make(map[uuid.UUID]map[uuid.UUID]int),
+	}
+	s.indexRequests()
+
+	result := &Result{Placements: []Placement{}, Unresolved: []Unresolved{}}
+	var pending []*Section
+	for i := range problem.Sections {
+		section := &problem.Sections[i]
+		s.sectionCount[section.CourseID]++
+		if len(section.Fixed) > 0 {
+			for _, meeting := range section.Fixed {
+				room := meeting.Room
+				if room == uuid.Nil {
+					room = section.Room
+				}
+				s.placed = append(s.placed, placed{section: section, slot: meeting.Slot, room: room})
+			}
+			continue
+		}
+		pending = append(pending, section)
+	}
+
+	// Place the sections with the fewest options first
+	options := make(map[uuid.UUID]int, len(pending))
+	for _, section := range pending {
+		options[section.ID] = s.countOptions(section)
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return options[pending[i].ID] < options[pending[j].ID]
+	})
+
+	for _, section := range pending {
+		if section.Meetings <= 0 {
+			continue
+		}
+
+		var meetings []placed
+		for n := 0; n < section.Meetings; n++ {
+			meeting, ok := s.bestPlacement(section, meetings)
+			if !ok {
+				break
+			}
+			meetings = append(meetings, meeting)
+			s.placed = append(s.placed, meeting)
+		}
+
+		if len(meetings) < section.Meetings {
+			// Release a partially placed section so its teachers and room stay free for others
+			s.placed = s.placed[:len(s.placed)-len(meetings)]
+			result.Unresolved = append(result.Unresolved, Unresolved{
+				SectionID: section.ID,
+				CourseID:  section.CourseID,
+				Reason:    s.explain(section, len(meetings)),
+			})
+			continue
+		}
+		for _, meeting := range meetings {
+			result.Placements = append(result.Placements, Placement{
+				SectionID: section.ID,
+				Slot:      meeting.slot,
+				Room:      meeting.room,
+			})
+		}
+	}
+
+	for courseID := range s.demand {
+		if s.sectionCount[courseID] == 0 {
+			result.Unresolved = append(result.Unresolved, Unresolved{
+				CourseID: courseID,
+				Reason:   fmt.Sprintf("requested by %d students but has no section", s.demand[courseID]),
+			})
+		}
+	}
+	result.StudentConflicts = s.studentConflicts()
+	return result
+}
+
+// indexRequests counts demand per course and the students requesting each pair of courses
+func (s *solver) indexRequests() {
+	byStudent := make(map[uuid.UUID][]uuid.UUID)
+	for _, request := range s.problem.Requests {
+		s.demand[request.CourseID]++
+		byStudent[request.StudentID] = append(byStudent[request.StudentID], request.CourseID)
+	}
+	for _, courses := range byStudent {
+		for _, a := range courses {
+			for _, b := range courses {
+				if a == b {
+					continue
+				}
+				if s.together[a] == nil {
+					s.together[a] = make(map[uuid.UUID]int)
+				}
+				s.together[a][b]++
+			}
+		}
+	}
+}
+
+// seatsNeeded is the room size a section needs: its capacity, or its share of the course demand
+func (s *solver) seatsNeeded(section *Section) int {
+	if section.Capacity > 0 {
+		return section.Capacity
+	}
+	count := s.sectionCount[section.CourseID]
+	if count == 0 {
+		return 0
+	}
+	return (s.demand[section.CourseID] + count - 1) / count
+}
+
+// candidateRooms lists the rooms a section may use, smallest first
+func (s *solver) candidateRooms(section *Section) []uuid.UUID {
+	if section.Room != uuid.Nil {
+		return []uuid.UUID{section.Room}
+	}
+	if len(s.problem.Rooms) == 0 {
+		return []uuid.UUID{uuid.Nil}
+	}
+
+	needed := s.seatsNeeded(section)
+	rooms := make([]Room, 0, len(s.problem.Rooms))
+	for _, room := range s.problem.Rooms {
+		if room.Capacity >= needed && (section.RoomType == "" || room.Type == section.RoomType) {
+			rooms = append(rooms, room)
+		}
+	}
+	sort.SliceStable(rooms, func(i, j int) bool { return rooms[i].Capacity < rooms[j].Capacity })
+
+	ids := make([]uuid.UUID, len(rooms))
+	for i, room := range rooms {
+		ids[i] = room.ID
+	}
+	return ids
+}
+
+// teachersAvailable reports whether every teacher of the section can teach in the slot
+func (s *solver) teachersAvailable(section *Section, slot Slot) bool {
+	for _, teacherID := range section.TeacherIDs {
+		windows, ok := s.problem.Availability[teacherID]
+		if !ok || len(windows) == 0 {
+			continue
+		}
+		available := false
+		for _, window := range windows {
+			if slot.Within(window) {
+				available = true
+				break
+			}
+		}
+		if !available {
+			return false
+		}
+	}
+	return true
+}
+
+// feasible reports whether a meeting of the section can go in the slot and room
+func (s *solver) feasible(section *Section, slot Slot, room uuid.UUID, meetings []placed) bool {
+	for _, meeting := range meetings {
+		if meeting.slot.Day == slot.Day {
+			return false
+		}
+	}
+	if !s.teachersAvailable(section, slot) {
+		return false
+	}
+
+	for _, other := range s.placed {
+		if !other.slot.Overlaps(slot) {
+			continue
+		}
+		if room != uuid.Nil && other.room == room {
+			return false
+		}
+		if sharesTeacher(section, other.section) {
+			return false
+		}
+	}
+	return true
+}
+
+// cost weighs the students who requested both this course and a course already meeting in the slot.
+// Clashes with a course that has several sections count less, as students can pick another one.
+func (s *solver) cost(section *Section, slot Slot) float64 {
+	var cost float64
+	for _, other := range s.placed {
+		if other.section.CourseID == section.CourseID || !other.slot.Overlaps(slot) {
+			continue
+		}
+		students := s.together[section.CourseID][other.section.CourseID]
+		if students > 0 {
+			cost += float64(students) / float64(s.sectionCount[other.section.CourseID])
+		}
+	}
+	return cost
+}
+
+// bestPlacement picks the feasible slot and room with the lowest cost, preferring earlier slots
+func (s *solver) bestPlacement(section *Section, meetings []placed) (placed, bool) {
+	var best placed
+	bestCost := -1.0
+	rooms := s.candidateRooms(section)
+	for _, slot := range s.problem.Slots {
+		for _, room := range rooms {
+			if !s.feasible(section, slot, room, meetings) {
+				continue
+			}
+			if cost := s.cost(section, slot); bestCost < 0 || cost < bestCost {
+				best = placed{section: section, slot: slot, room: room}
+				bestCost = cost
+			}
+			break
+		}
+	}
+	return best, bestCost >= 0
+}
+
+// countOptions counts the slots a section could use on an empty timetable
+func (s *solver) countOptions(section *Section) int {
+	if len(s.candidateRooms(section)) == 0 {
+		return 0
+	}
+	count := 0
+	for _, slot := range s.problem.Slots {
+		if s.teachersAvailable(section, slot) {
+			count++
+		}
+	}
+	return count
+}
+
+// explain describes why a section could not be fully placed
+func (s *solver) explain(section *Section, placedMeetings int) string {
+	if len(s.candidateRooms(section)) == 0 {
+		if section.RoomType != "" {
+			return fmt.Sprintf("no %s holds the %d seats the section needs", section.RoomType, s.seatsNeeded(section))
+		}
+		return fmt.Sprintf("no room holds the %d seats the section needs", s.seatsNeeded(section))
+	}
+	if s.countOptions(section) == 0 {
+		return "the section's teachers have no common availability"
+	}
+	return fmt.Sprintf("only %d of %d meetings fit around the section's teachers and rooms",
+		placedMeetings, section.Meetings)
+}
+
+// studentConflicts counts, per pair of requested courses, the students who cannot attend both
+// because every section of one overlaps every section of the other
+func (s *solver) studentConflicts() int {
+	slots := make(map[uuid.UUID]map[uuid.UUID][]Slot)
+	for _, meeting := range s.placed {
+		courseID := meeting.section.CourseID
+		if slots[courseID] == nil {
+			slots[courseID] = make(map[uuid.UUID][]Slot)
+		}
+		slots[courseID][meeting.section.ID] = append(slots[courseID][meeting.section.ID], meeting.slot)
+	}
+
+	conflicts := 0
+	for a, pairs := range s.together {
+		for b, students := range pairs {
+			// Each pair appears twice in together; count it once
+			if a.String() > b.String() || slots[a] == nil || slots[b] == nil {
+				continue
+			}
+			if !compatible(slots[a], slots[b]) {
+				conflicts += students
+			}
+		}
+	}
+	return conflicts
+}
+
+// compatible reports whether some section of one course fits alongside some section of the other
+func compatible(a, b map[uuid.UUID][]Slot) bool {
+	for _, slotsA := range a {
+		for _, slotsB := range b {
+			if !anyOverlap(slotsA, slotsB) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anyOverlap reports whether any slot of one list overlaps any slot of the other
+func anyOverlap(a, b []Slot) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Overlaps(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sharesTeacher reports whether two sections have a teacher in common
+func sharesTeacher(a, b *Section) bool {
+	for _, x := range a.TeacherIDs {
+		for _, y := range b.TeacherIDs {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WeeklySlots builds evenly spaced periods between start and end on each day, in minutes after midnight
+func WeeklySlots(days []time.Weekday, start, end, period, gap int) []Slot {
+	var slots []Slot
+	for _, day := range days {
+		for t := start; t+period <= end; t += period + gap {
+			slots = append(slots, Slot{Day: day, Start: t, End: t + period})
+		}
+	}
+	return slots
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/notifications"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxLookbackDays bounds how far back in the calendar alert rules search for instructional days
+const maxLookbackDays = 366
+
+// AlertService defines the interface for attendance alert rules and alerts
+type AlertService interface {
+	GetRules(ctx context.Context) ([]models.AttendanceAlertRule, error)
+	CreateRule(ctx context.Context, rule *models.AttendanceAlertRule) error
+	UpdateRule(ctx context.Context, rule *models.AttendanceAlertRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	GetAlerts(ctx context.Context, filter repositories.AlertFilter) ([]models.AttendanceAlert, error)
+	GetAlertByID(ctx context.Context, id uuid.UUID) (*models.AttendanceAlert, error)
+	AcknowledgeAlert(ctx context.Context, id, userID uuid.UUID, notes string) (*models.AttendanceAlert, error)
+	ResolveAlert(ctx context.Context, id, userID uuid.UUID, notes string) (*models.AttendanceAlert, error)
+	EvaluateStudent(ctx context.Context, studentID uuid.UUID) ([]models.AttendanceAlert, error)
+	EvaluateAll(ctx context.Context) ([]models.AttendanceAlert, error)
+}
+
+// AlertServiceImpl implements the AlertService interface
+type AlertServiceImpl struct {
+	alertRepo   repositories.AlertRepository
+	studentRepo repositories.StudentRepository
+	userRepo    repositories.UserRepository
+	termService TermService
+	notifier    notifications.Notifier
+}
+
+// NewAlertService creates a new instance of AlertServiceImpl
+func NewAlertService(
+	alertRepo repositories.AlertRepository,
+	studentRepo repositories.StudentRepository,
+	userRepo repositories.UserRepository,
+	termService TermService,
+	notifier notifications.Notifier,
+) AlertService {
+	return &AlertServiceImpl{
+		alertRepo:   alertRepo,
+		studentRepo: studentRepo,
+		userRepo:    userRepo,
+		termService: termService,
+		notifier:    notifier,
+	}
+}
+
+// GetRules retrieves all alert rules
+func (s *AlertServiceImpl) GetRules(ctx context.Context) ([]models.AttendanceAlertRule, error) {
+	return s.alertRepo.GetRules(ctx, false)
+}
+
+// CreateRule creates a new alert rule
+func (s *AlertServiceImpl) CreateRule(ctx context.Context, rule *models.AttendanceAlertRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return s.alertRepo.CreateRule(ctx, rule)
+}
+
+// UpdateRule updates an alert rule
+func (s *AlertServiceImpl) UpdateRule(ctx context.Context, rule *models.AttendanceAlertRule) error {
+	// Check if rule exists
+	existingRule, err := s.alertRepo.GetRuleByID(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	rule.CreatedAt = existingRule.CreatedAt
+	return s.alertRepo.UpdateRule(ctx, rule)
+}
+
+// DeleteRule deletes an alert rule; alerts it already raised are kept
+func (s *AlertServiceImpl) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	// Check if rule exists
+	if _, err := s.alertRepo.GetRuleByID(ctx, id); err != nil {
+		return err
+	}
+	return s.alertRepo.DeleteRule(ctx, id)
+}
+
+// GetAlerts retrieves the alerts matching a filter
+func (s *AlertServiceImpl) GetAlerts(ctx context.Context, filter repositories.AlertFilter) ([]models.AttendanceAlert, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errors.New("status must be open, acknowledged or resolved")
+	}
+	return s.alertRepo.GetAlerts(ctx, filter)
+}
+
+// GetAlertByID retrieves an alert by ID
+func (s *AlertServiceImpl) GetAlertByID(ctx context.Context, id uuid.UUID) (*models.AttendanceAlert, error) {
+	return s.alertRepo.GetAlertByID(ctx, id)
+}
+
+// AcknowledgeAlert records that a counselor has picked up an open alert
+func (s *AlertServiceImpl) AcknowledgeAlert(ctx context.Context, id, userID uuid.UUID, notes string) (*models.AttendanceAlert, error) {
+	alert, err := s.alertRepo.GetAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status != models.AlertOpen {
+		return nil, fmt.Errorf("alert is already %s", alert.Status)
+	}
+
+	now := time.Now()
+	alert.Status = models.AlertAcknowledged
+	alert.AcknowledgedBy = &userID
+	alert.AcknowledgedAt = &now
+	if notes != "" {
+		alert.Notes = notes
+	}
+	if err := s.alertRepo.UpdateAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// ResolveAlert closes an open or acknowledged alert. The rule can raise a new alert for the student
+// once attendance recorded after the resolution crosses its threshold again.
+func (s *AlertServiceImpl) ResolveAlert(ctx context.Context, id, userID uuid.UUID, notes string) (*models.AttendanceAlert, error) {
+	alert, err := s.alertRepo.GetAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status == models.AlertResolved {
+		return nil, errors.New("alert is already resolved")
+	}
+
+	now := time.Now()
+	alert.Status = models.AlertResolved
+	alert.ResolvedBy = &userID
+	alert.ResolvedAt = &now
+	if notes != "" {
+		alert.Notes = notes
+	}
+	if err := s.alertRepo.UpdateAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// EvaluateStudent checks every enabled rule against a student's recent attendance and raises an
+// alert for each rule crossed, notifying the student's counselor. It returns the alerts raised.
+func (s *AlertServiceImpl) EvaluateStudent(ctx context.Context, studentID uuid.UUID) ([]models.AttendanceAlert, error) {
+	rules, err := s.alertRepo.GetRules(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	days, err := s.instructionalDays(ctx, rules, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluate(ctx, studentID, rules, days)
+}
+
+// EvaluateAll checks every enabled rule against each student with recent attendance
+func (s *AlertServiceImpl) EvaluateAll(ctx context.Context) ([]models.AttendanceAlert, error) {
+	rules, err := s.alertRepo.GetRules(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	raised := []models.AttendanceAlert{}
+	if len(rules) == 0 {
+		return raised, nil
+	}
+	days, err := s.instructionalDays(ctx, rules, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs, err := s.alertRepo.FindStudentsWithAttendanceSince(ctx, lookbackStart(rules, days, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	for _, studentID := range studentIDs {
+		alerts, err := s.evaluate(ctx, studentID, rules, days)
+		if err != nil {
+			return nil, err
+		}
+		raised = append(raised, alerts...)
+	}
+	return raised, nil
+}
+
+// evaluate checks rules against one student's attendance over the given instructional days
+func (s *AlertServiceImpl) evaluate(ctx context.Context, studentID uuid.UUID, rules []models.AttendanceAlertRule, days []time.Time) ([]models.AttendanceAlert, error) {
+	today := models.DateOnly(time.Now())
+	records, err := s.alertRepo.FindAttendanceSince(ctx, studentID, lookbackStart(rules, days, today))
+	if err != nil {
+		return nil, err
+	}
+
+	var raised []models.AttendanceAlert
+	for i := range rules {
+		rule := &rules[i]
+		finding := rule.Evaluate(records, days, today)
+		if !finding.Triggered {
+			continue
+		}
+
+		// One alert per rule at a time, and no new alert for attendance already seen when the last was resolved
+		latest, err := s.alertRepo.FindLatestAlert(ctx, studentID, rule.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			if latest.Status != models.AlertResolved {
+				continue
+			}
+			if latest.ResolvedAt != nil && !finding.LastDate.After(models.DateOnly(*latest.ResolvedAt)) {
+				continue
+			}
+		}
+
+		alert := models.AttendanceAlert{
+			StudentID:   studentID,
+			RuleID:      rule.ID,
+			Status:      models.AlertOpen,
+			Value:       finding.Value,
+			Message:     finding.Message,
+			TriggeredOn: today,
+		}
+		// A concurrent evaluation may have raised the alert meanwhile, and notified about it
+		created, err := s.alertRepo.CreateAlert(ctx, &alert)
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			continue
+		}
+		alert.Rule = rule
+		s.notify(ctx, &alert)
+		raised = append(raised, alert)
+	}
+	return raised, nil
+}
+
+// notify tells the student's counselor about a new alert, or the administrators when the student has
+// no counselor. Delivery failures are logged rather than undoing the alert.
+func (s *AlertServiceImpl) notify(ctx context.Context, alert *models.AttendanceAlert) {
+	student, err := s.studentRepo.GetByID(ctx, alert.StudentID)
+	if err != nil {
+		log.Printf("Failed to load student %s for attendance alert %s: %v", alert.StudentID, alert.ID, err)
+		return
+	}
+
+	var recipients []models.User
+	if student.CounselorID != nil {
+		counselor, err := s.userRepo.GetByID(ctx, *student.CounselorID)
+		if err == nil {
+			recipients = append(recipients, *counselor)
+		}
+	}
+	if len(recipients) == 0 {
+		admins, err := s.userRepo.FindByRole(ctx, "Admin")
+		if err != nil {
+			log.Printf("Failed to find administrators for attendance alert %s: %v", alert.ID, err)
+			return
+		}
+		recipients = admins
+	}
+
+	for _, recipient := range recipients {
+		notification := notifications.Notification{
+			UserID:  recipient.ID,
+			Email:   recipient.Email,
+			Subject: fmt.Sprintf("Attendance alert: %s %s (%s)", student.FirstName, student.LastName, alert.Rule.Name),
+			Body:    alert.Message,
+		}
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			log.Printf("Failed to notify %s of attendance alert %s: %v", recipient.Username, alert.ID, err)
+		}
+	}
+}
+
+// instructionalDays lists, in date order, the instructional days up to a date that the rules look back
+// over, following the school calendar of each term and skipping dates outside any term
+func (s *AlertServiceImpl) instructionalDays(ctx context.Context, rules []models.AttendanceAlertRule, until time.Time) ([]time.Time, error) {
+	needed := 0
+	for i := range rules {
+		needed = max(needed, rules[i].LookbackDays())
+	}
+
+	terms, err := s.termService.GetAllTerms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	calendars := make(map[uuid.UUID]*models.SchoolCalendar)
+
+	var days []time.Time
+	date := models.DateOnly(until)
+	for examined := 0; len(days) < needed && examined < maxLookbackDays; examined++ {
+		for i := range terms {
+			if !terms[i].Contains(date) {
+				continue
+			}
+			calendar, ok := calendars[terms[i].ID]
+			if !ok {
+				calendar, err = s.termService.GetCalendar(ctx, terms[i].ID)
+				if err != nil {
+					return nil, err
+				}
+				calendars[terms[i].ID] = calendar
+			}
+			if calendar.IsInstructional(date) {
+				days = append(days, date)
+			}
+			break
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+
+	// Collected newest first
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+	return days, nil
+}
+
+// lookbackStart returns the earliest date whose attendance any of the rules needs
+func lookbackStart(rules []models.AttendanceAlertRule, days []time.Time, today time.Time) time.Time {
+	start := models.DateOnly(today)
+	if len(days) > 0 {
+		start = days[0]
+	}
+	for i := range rules {
+		if rules[i].Type == models.LatesRule {
+			since := models.DateOnly(today).AddDate(0, 0, -rules[i].WindowDays)
+			if since.Before(start) {
+				start = since
+			}
+		}
+	}
+	return start
+}
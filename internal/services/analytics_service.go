@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+)
+
+const (
+	// defaultAnalyticsDays is how many days up to today an analytics report covers when no range is given
+	defaultAnalyticsDays = 30
+	// maxAnalyticsDays bounds the date range of an analytics report
+	maxAnalyticsDays = 366
+)
+
+// ErrInvalidAnalyticsQuery is returned when an analytics report is asked for an invalid date range
+var ErrInvalidAnalyticsQuery = errors.New("invalid analytics query")
+
+// AnalyticsService defines the interface for aggregated attendance analytics
+type AnalyticsService interface {
+	GetAttendanceAnalytics(ctx context.Context, filter models.AttendanceAnalyticsFilter) (*models.AttendanceAnalytics, error)
+}
+
+// AnalyticsServiceImpl implements the AnalyticsService interface
+type AnalyticsServiceImpl struct {
+	analyticsRepo repositories.AnalyticsRepository
+}
+
+// NewAnalyticsService creates a new instance of AnalyticsServiceImpl
+func NewAnalyticsService(analyticsRepo repositories.AnalyticsRepository) AnalyticsService {
+	return &AnalyticsServiceImpl{
+		analyticsRepo: analyticsRepo,
+	}
+}
+
+// GetAttendanceAnalytics breaks down the attendance recorded over a date range by day, week, course,
+// grade level and teacher. Without a range it covers the last 30 days up to today.
+func (s *AnalyticsServiceImpl) GetAttendanceAnalytics(ctx context.Context, filter models.AttendanceAnalyticsFilter) (*models.AttendanceAnalytics, error) {
+	if err := normalizeAnalyticsRange(&filter); err != nil {
+		return nil, err
+	}
+
+	total, err := s.analyticsRepo.GetAttendanceTotal(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	analytics := &models.AttendanceAnalytics{Filter: filter, Total: *total}
+
+	breakdowns := []struct {
+		target *[]models.AttendanceBreakdown
+		query  func(ctx context.Context, filter models.AttendanceAnalyticsFilter) ([]models.AttendanceBreakdown, error)
+	}{
+		{&analytics.ByDay, s.analyticsRepo.GetAttendanceByDay},
+		{&analytics.ByWeek, s.analyticsRepo.GetAttendanceByWeek},
+		{&analytics.ByCourse, s.analyticsRepo.GetAttendanceByCourse},
+		{&analytics.ByGradeLevel, s.analyticsRepo.GetAttendanceByGradeLevel},
+		{&analytics.ByTeacher, s.analyticsRepo.GetAttendanceByTeacher},
+	}
+	for _, breakdown := range breakdowns {
+		rows, err := breakdown.query(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if rows == nil {
+			rows = []models.AttendanceBreakdown{}
+		}
+		for i := range rows {
+			rows[i].UpdateRates()
+		}
+		*breakdown.target = rows
+	}
+	analytics.Total.UpdateRates()
+	return analytics, nil
+}
+
+// normalizeAnalyticsRange checks a report's YYYY-MM-DD date range, filling in whichever end is missing
+func normalizeAnalyticsRange(filter *models.AttendanceAnalyticsFilter) error {
+	to := models.DateOnly(time.Now())
+	if filter.To != "" {
+		date, err := time.Parse("2006-01-02", filter.To)
+		if err != nil {
+			return fmt.Errorf("%w: to must be a YYYY-MM-DD date", ErrInvalidAnalyticsQuery)
+		}
+		to = date
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if filter.From != "" {
+		date, err := time.Parse("2006-01-02", filter.From)
+		if err != nil {
+			return fmt.Errorf("%w: from must be a YYYY-MM-DD date", ErrInvalidAnalyticsQuery)
+		}
+		from = date
+	}
+
+	if to.Before(from) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidAnalyticsQuery)
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return fmt.Errorf("%w: date range must be at most %d days", ErrInvalidAnalyticsQuery, maxAnalyticsDays)
+	}
+	filter.From = from.Format("2006-01-02")
+	filter.To = to.Format("2006-01-02")
+	return nil
+}
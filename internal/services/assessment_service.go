@@ -0,0 +1,476 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidGradebookInput is returned when assessments, weights or scores fail validation
+var ErrInvalidGradebookInput = errors.New("invalid gradebook input")
+
+// AssessmentService defines methods for assessments and the weighted gradebook
+type AssessmentService interface {
+	CreateAssessment(ctx context.Context, assessment *models.Assessment) error
+	UpdateAssessment(ctx context.Context, assessment *models.Assessment, userID uuid.UUID) error
+	DeleteAssessment(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	GetAssessmentByID(ctx context.Context, id uuid.UUID) (*models.Assessment, error)
+	GetCourseAssessments(ctx context.Context, courseID uuid.UUID, term string) ([]models.Assessment, error)
+	GetWeights(ctx context.Context, courseID uuid.UUID) ([]models.AssessmentWeight, error)
+	SetWeights(ctx context.Context, courseID uuid.UUID, weights []models.AssessmentWeight, userID uuid.UUID) error
+	RecordScores(ctx context.Context, assessmentID uuid.UUID, scores []models.AssessmentScore, userID uuid.UUID) error
+	GetGradebook(ctx context.Context, courseID uuid.UUID, term string) (*models.Gradebook, error)
+}
+
+// AssessmentServiceImpl implements the AssessmentService interface
+type AssessmentServiceImpl struct {
+	assessmentRepo repositories.AssessmentRepository
+	courseRepo     repositories.CourseRepository
+	gradeService   GradeService
+	scaleService   GradingScaleService
+	termService    TermService
+	access         recordAccess
+}
+
+// NewAssessmentService creates a new instance of AssessmentServiceImpl
+func NewAssessmentService(
+	assessmentRepo repositories.AssessmentRepository,
+	courseRepo repositories.CourseRepository,
+	teacherRepo repositories.TeacherRepository,
+	guardianRepo repositories.GuardianRepository,
+	gradeService GradeService,
+	scaleService GradingScaleService,
+	termService TermService,
+) AssessmentService {
+	return &AssessmentServiceImpl{
+		assessmentRepo: assessmentRepo,
+		courseRepo:     courseRepo,
+		gradeService:   gradeService,
+		scaleService:   scaleService,
+		termService:    termService,
+		access:         recordAccess{teacherRepo: teacherRepo, guardianRepo: guardianRepo},
+	}
+}
+
+// CreateAssessment creates a new assessment in a course the caller teaches
+func (s *AssessmentServiceImpl) CreateAssessment(ctx context.Context, assessment *models.Assessment) error {
+	if err := s.access.canChange(ctx, assessment.CourseID); err != nil {
+		return err
+	}
+	if err := validateAssessment(assessment); err != nil {
+		return err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, assessment.CourseID); err != nil {
+		return err
+	}
+
+	if err := s.resolveTerm(ctx, assessment); err != nil {
+		return err
+	}
+
+	return s.assessmentRepo.Create(ctx, assessment)
+}
+
+// UpdateAssessment updates an assessment and refreshes the rolled-up grades
+func (s *AssessmentServiceImpl) UpdateAssessment(ctx context.Context, assessment *models.Assessment, userID uuid.UUID) error {
+	// Check if assessment exists
+	existing, err := s.assessmentRepo.GetByID(ctx, assessment.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, existing.CourseID); err != nil {
+		return err
+	}
+
+	if err := validateAssessment(assessment); err != nil {
+		return err
+	}
+
+	if err := s.resolveTerm(ctx, assessment); err != nil {
+		return err
+	}
+
+	// An assessment cannot move between courses
+	assessment.CourseID = existing.CourseID
+	assessment.CreatedBy = existing.CreatedBy
+	assessment.CreatedAt = existing.CreatedAt
+
+	if err := s.assessmentRepo.Update(ctx, assessment); err != nil {
+		return err
+	}
+
+	return s.rollUp(ctx, assessment.CourseID, assessment.Term, nil, userID)
+}
+
+// DeleteAssessment deletes an assessment and refreshes the rolled-up grades
+func (s *AssessmentServiceImpl) DeleteAssessment(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	// Check if assessment exists
+	assessment, err := s.assessmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, assessment.CourseID); err != nil {
+		return err
+	}
+
+	if err := s.assessmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return s.rollUp(ctx, assessment.CourseID, assessment.Term, nil, userID)
+}
+
+// resolveTerm checks that the assessment's term exists and fills in the current term when none is given
+func (s *AssessmentServiceImpl) resolveTerm(ctx context.Context, assessment *models.Assessment) error {
+	term, err := s.termService.ResolveTerm(ctx, assessment.Term)
+	if err != nil {
+		return err
+	}
+
+	assessment.Term = term.Name
+	return nil
+}
+
+// GetAssessmentByID retrieves an assessment by its ID
+func (s *AssessmentServiceImpl) GetAssessmentByID(ctx context.Context, id uuid.UUID) (*models.Assessment, error) {
+	return s.assessmentRepo.GetByID(ctx, id)
+}
+
+// GetCourseAssessments gets the assessments of a course
+func (s *AssessmentServiceImpl) GetCourseAssessments(ctx context.Context, courseID uuid.UUID, term string) ([]models.Assessment, error) {
+	return s.assessmentRepo.FindByCourse(ctx, courseID, term)
+}
+
+// GetWeights gets the category weights of a course
+func (s *AssessmentServiceImpl) GetWeights(ctx context.Context, courseID uuid.UUID) ([]models.AssessmentWeight, error) {
+	return s.assessmentRepo.GetWeights(ctx, courseID)
+}
+
+// SetWeights replaces the category weights of a course and refreshes the rolled-up grades
+func (s *AssessmentServiceImpl) SetWeights(ctx context.Context, courseID uuid.UUID, weights []models.AssessmentWeight, userID uuid.UUID) error {
+	if err := s.access.canChange(ctx, courseID); err != nil {
+		return err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return err
+	}
+
+	seen := make(map[models.AssessmentType]bool)
+	var total float64
+	for i := range weights {
+		weight := &weights[i]
+		if !weight.Type.IsValid() {
+			return fmt.Errorf("%w: unknown assessment type %q", ErrInvalidGradebookInput, weight.Type)
+		}
+		if seen[weight.Type] {
+			return fmt.Errorf("%w: duplicate weight for %q", ErrInvalidGradebookInput, weight.Type)
+		}
+		if weight.Weight < 0 || weight.DropLowest < 0 {
+			return fmt.Errorf("%w: weights and dropped scores cannot be negative", ErrInvalidGradebookInput)
+		}
+		seen[weight.Type] = true
+		total += weight.Weight
+		weight.ID = uuid.Nil
+		weight.CourseID = courseID
+	}
+	if len(weights) > 0 && math.Abs(total-100) > 0.01 {
+		return fmt.Errorf("%w: category weights must add up to 100", ErrInvalidGradebookInput)
+	}
+
+	if err := s.assessmentRepo.ReplaceWeights(ctx, courseID, weights); err != nil {
+		return err
+	}
+
+	return s.rollUp(ctx, courseID, "", nil, userID)
+}
+
+// RecordScores saves scores for an assessment and rolls them up into the students' grades
+func (s *AssessmentServiceImpl) RecordScores(ctx context.Context, assessmentID uuid.UUID, scores []models.AssessmentScore, userID uuid.UUID) error {
+	// Check if assessment exists
+	assessment, err := s.assessmentRepo.GetByID(ctx, assessmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, assessment.CourseID); err != nil {
+		return err
+	}
+
+	// Only students on the course roster can be scored
+	students, err := s.courseRepo.GetStudents(ctx, assessment.CourseID)
+	if err != nil {
+		return err
+	}
+	enrolled := make(map[uuid.UUID]bool)
+	for _, student := range students {
+		enrolled[student.ID] = true
+	}
+
+	studentIDs := make([]uuid.UUID, 0, len(scores))
+	for i := range scores {
+		score := &scores[i]
+		if !enrolled[score.StudentID] {
+			return fmt.Errorf("%w: student %s is not enrolled in the course", ErrInvalidGradebookInput, score.StudentID)
+		}
+		if score.Status == "" {
+			score.Status = models.ScoreGraded
+		}
+		if !score.Status.IsValid() {
+			return fmt.Errorf("%w: unknown score status %q", ErrInvalidGradebookInput, score.Status)
+		}
+		if score.Status == models.ScoreGraded {
+			if score.Points == nil || *score.Points < 0 {
+				return fmt.Errorf("%w: graded scores need non-negative points", ErrInvalidGradebookInput)
+			}
+		} else {
+			score.Points = nil
+		}
+		score.ID = uuid.Nil
+		score.AssessmentID = assessmentID
+		score.UpdatedBy = userID
+		studentIDs = append(studentIDs, score.StudentID)
+	}
+
+	if err := s.assessmentRepo.SaveScores(ctx, scores); err != nil {
+		return err
+	}
+
+	return s.rollUp(ctx, assessment.CourseID, assessment.Term, studentIDs, userID)
+}
+
+// GetGradebook builds the student x assessment matrix for a course
+func (s *AssessmentServiceImpl) GetGradebook(ctx context.Context, courseID uuid.UUID, term string) (*models.Gradebook, error) {
+	if err := s.access.canReadCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	students, err := s.courseRepo.GetStudents(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessments, err := s.assessmentRepo.FindByCourse(ctx, courseID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	weights, err := s.assessmentRepo.GetWeights(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.assessmentRepo.FindScoresByCourse(ctx, courseID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	scale, err := s.scaleService.ResolveCourseScale(ctx, courseID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index scores by student, then by assessment
+	scoresByStudent := make(map[uuid.UUID]map[uuid.UUID]*models.AssessmentScore)
+	for i := range scores {
+		score := &scores[i]
+		if scoresByStudent[score.StudentID] == nil {
+			scoresByStudent[score.StudentID] = make(map[uuid.UUID]*models.AssessmentScore)
+		}
+		scoresByStudent[score.StudentID][score.AssessmentID] = score
+	}
+
+	gradebook := &models.Gradebook{
+		CourseID:    courseID,
+		Term:        term,
+		Weights:     weights,
+		Assessments: assessments,
+		Rows:        make([]models.GradebookRow, 0, len(students)),
+	}
+
+	for _, student := range students {
+		studentScores := scoresByStudent[student.ID]
+		if studentScores == nil {
+			studentScores = make(map[uuid.UUID]*models.AssessmentScore)
+		}
+
+		categoryAverages, weighted := weightedAverage(assessments, studentScores, weights)
+		row := models.GradebookRow{
+			StudentID:        student.ID,
+			FirstName:        student.FirstName,
+			LastName:         student.LastName,
+			Scores:           studentScores,
+			CategoryAverages: categoryAverages,
+			WeightedAverage:  weighted,
+		}
+		if weighted != nil {
+			if band := scale.BandForScore(*weighted); band != nil {
+				row.Grade = band.Letter
+			}
+		}
+		gradebook.Rows = append(gradebook.Rows, row)
+	}
+
+	sort.Slice(gradebook.Rows, func(i, j int) bool {
+		if gradebook.Rows[i].LastName != gradebook.Rows[j].LastName {
+			return gradebook.Rows[i].LastName < gradebook.Rows[j].LastName
+		}
+		return gradebook.Rows[i].FirstName < gradebook.Rows[j].FirstName
+	})
+
+	return gradebook, nil
+}
+
+// rollUp writes the weighted gradebook average into each student's final grade.
+// An empty term rolls up every term that has assessments; nil studentIDs means the whole roster.
+func (s *AssessmentServiceImpl) rollUp(ctx context.Context, courseID uuid.UUID, term string, studentIDs []uuid.UUID, userID uuid.UUID) error {
+	terms := []string{term}
+	if term == "" {
+		assessments, err := s.assessmentRepo.FindByCourse(ctx, courseID, "")
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		terms = terms[:0]
+		for _, assessment := range assessments {
+			if !seen[assessment.Term] {
+				seen[assessment.Term] = true
+				terms = append(terms, assessment.Term)
+			}
+		}
+	}
+
+	var only map[uuid.UUID]bool
+	if studentIDs != nil {
+		only = make(map[uuid.UUID]bool)
+		for _, id := range studentIDs {
+			only[id] = true
+		}
+	}
+
+	for _, t := range terms {
+		gradebook, err := s.GetGradebook(ctx, courseID, t)
+		if err != nil {
+			return err
+		}
+		for _, row := range gradebook.Rows {
+			if row.WeightedAverage == nil || (only != nil && !only[row.StudentID]) {
+				continue
+			}
+			if _, err := s.gradeService.RollUpGrade(ctx, row.StudentID, courseID, t, *row.WeightedAverage, userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAssessment checks the fields of an assessment
+func validateAssessment(assessment *models.Assessment) error {
+	if !assessment.Type.IsValid() {
+		return fmt.Errorf("%w: unknown assessment type %q", ErrInvalidGradebookInput, assessment.Type)
+	}
+	if assessment.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidGradebookInput)
+	}
+	if assessment.MaxPoints <= 0 {
+		return fmt.Errorf("%w: max points must be positive", ErrInvalidGradebookInput)
+	}
+	return nil
+}
+
+// categoryResult is an earned/possible pair for a single assessment
+type categoryResult struct {
+	earned   float64
+	possible float64
+}
+
+// weightedAverage computes per-category percentages and the weighted overall percentage.
+// Missing work counts as zero, excused and ungraded work is left out, and each category
+// drops its lowest scores when enough results remain. Without configured weights the
+// overall percentage is total points earned over total points possible.
+func weightedAverage(
+	assessments []models.Assessment,
+	scores map[uuid.UUID]*models.AssessmentScore,
+	weights []models.AssessmentWeight,
+) (map[models.AssessmentType]float64, *float64) {
+	results := make(map[models.AssessmentType][]categoryResult)
+	for _, assessment := range assessments {
+		score, ok := scores[assessment.ID]
+		if !ok {
+			continue
+		}
+		switch score.Status {
+		case models.ScoreExcused:
+			continue
+		case models.ScoreMissing:
+			results[assessment.Type] = append(results[assessment.Type], categoryResult{possible: assessment.MaxPoints})
+		default:
+			if score.Points == nil {
+				continue
+			}
+			results[assessment.Type] = append(results[assessment.Type], categoryResult{earned: *score.Points, possible: assessment.MaxPoints})
+		}
+	}
+
+	dropLowest := make(map[models.AssessmentType]int)
+	for _, weight := range weights {
+		dropLowest[weight.Type] = weight.DropLowest
+	}
+
+	averages := make(map[models.AssessmentType]float64)
+	var totalEarned, totalPossible float64
+	for category, categoryResults := range results {
+		sort.Slice(categoryResults, func(i, j int) bool {
+			return categoryResults[i].earned/categoryResults[i].possible < categoryResults[j].earned/categoryResults[j].possible
+		})
+		if drop := dropLowest[category]; drop > 0 && len(categoryResults) > drop {
+			categoryResults = categoryResults[drop:]
+		}
+
+		var earned, possible float64
+		for _, result := range categoryResults {
+			earned += result.earned
+			possible += result.possible
+		}
+		averages[category] = earned / possible * 100
+		totalEarned += earned
+		totalPossible += possible
+	}
+
+	if len(averages) == 0 {
+		return averages, nil
+	}
+
+	if len(weights) == 0 {
+		overall := totalEarned / totalPossible * 100
+		return averages, &overall
+	}
+
+	// Renormalize over the categories that have results so far
+	var weighted, weightSum float64
+	for _, weight := range weights {
+		average, ok := averages[weight.Type]
+		if !ok || weight.Weight == 0 {
+			continue
+		}
+		weighted += average * weight.Weight
+		weightSum += weight.Weight
+	}
+	if weightSum == 0 {
+		return averages, nil
+	}
+
+	overall := weighted / weightSum
+	return averages, &overall
+}
@@ -1,95 +1,681 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"school-management-api/internal/models"
 	"school-management-api/internal/repositories"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// ErrInvalidAttendance is returned when an attendance record fails validation or the student is not enrolled
+var ErrInvalidAttendance = errors.New("invalid attendance record")
+
 // AttendanceService defines methods for attendance management
 type AttendanceService interface {
-	CreateAttendance(attendance *models.Attendance) error
-	UpdateAttendance(attendance *models.Attendance) error
-	DeleteAttendance(id uuid.UUID) error
-	GetAttendanceByID(id uuid.UUID) (*models.Attendance, error)
-	GetAllAttendances() ([]models.Attendance, error)
-	GetAttendancesByStudent(studentID uuid.UUID) ([]models.Attendance, error)
-	GetAttendancesByCourse(courseID uuid.UUID) ([]models.Attendance, error)
-	GetAttendancesByDate(date time.Time) ([]models.Attendance, error)
-	GetAttendancesByDateRange(startDate, endDate time.Time) ([]models.Attendance, error)
-	GetAttendancesByCourseAndDate(courseID uuid.UUID, date time.Time) ([]models.Attendance, error)
-	GetStudentAttendanceReport(studentID uuid.UUID) (map[string]int, error)
-	GetCourseAttendanceReport(courseID uuid.UUID) (map[string]map[string]int, error)
+	CreateAttendance(ctx context.Context, attendance *models.Attendance, recordedBy uuid.UUID) (bool, error)
+	RecordIfAbsent(ctx context.Context, attendance *models.Attendance, recordedBy uuid.UUID) (*models.Attendance, error)
+	UpdateAttendance(ctx context.Context, attendance *models.Attendance) error
+	DeleteAttendance(ctx context.Context, id uuid.UUID) error
+	GetAttendanceByID(ctx context.Context, id uuid.UUID) (*models.Attendance, error)
+	GetAllAttendances(ctx context.Context, term string) ([]models.Attendance, error)
+	GetAttendancesByStudent(ctx context.Context, studentID uuid.UUID, term string) ([]models.Attendance, error)
+	GetAttendancesByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.Attendance, error)
+	GetAttendancesByDate(ctx context.Context, date time.Time) ([]models.Attendance, error)
+	GetAttendancesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Attendance, error)
+	GetAttendancesByCourseAndDate(ctx context.Context, courseID uuid.UUID, date time.Time) ([]models.Attendance, error)
+	GetStudentAttendanceReport(ctx context.Context, studentID uuid.UUID) (map[string]int, error)
+	GetCourseAttendanceReport(ctx context.Context, courseID uuid.UUID) (map[string]map[string]int, error)
+	GetStudentAttendanceRates(ctx context.Context, studentID uuid.UUID, term string) (*models.StudentAttendanceRates, error)
+	GetCourseAttendanceRates(ctx context.Context, courseID uuid.UUID, term string) (*models.CourseAttendanceRates, error)
+	GetMissingAttendance(ctx context.Context, teacherID uuid.UUID, term string, from, to *time.Time) ([]models.ExpectedSession, error)
+	GetStudentMinutesReport(ctx context.Context, studentID uuid.UUID, term string) (*models.StudentMinutesReport, error)
+	GetCourseMinutesReport(ctx context.Context, courseID uuid.UUID, term string) (*models.CourseMinutesReport, error)
+	GetRollCall(ctx context.Context, courseID uuid.UUID, date time.Time, period int) (*models.RollCall, error)
+	TakeRollCall(ctx context.Context, courseID uuid.UUID, date time.Time, period int, marks []models.RollCallMark, takenBy uuid.UUID) (*models.RollCall, error)
 }
 
 // AttendanceServiceImpl implements the AttendanceService interface
 type AttendanceServiceImpl struct {
 	attendanceRepo repositories.AttendanceRepository
+	courseRepo     repositories.CourseRepository
+	sectionRepo    repositories.SectionRepository
+	excuseRepo     repositories.ExcuseRepository
+	termService    TermService
+	alertService   AlertService
+	access         recordAccess
 }
 
 // NewAttendanceService creates a new AttendanceService
-func NewAttendanceService(attendanceRepo repositories.AttendanceRepository) AttendanceService {
-	return &AttendanceServiceImpl{attendanceRepo: attendanceRepo}
+func NewAttendanceService(attendanceRepo repositories.AttendanceRepository, courseRepo repositories.CourseRepository, sectionRepo repositories.SectionRepository, excuseRepo repositories.ExcuseRepository, teacherRepo repositories.TeacherRepository, guardianRepo repositories.GuardianRepository, termService TermService, alertService AlertService) AttendanceService {
+	return &AttendanceServiceImpl{attendanceRepo: attendanceRepo, courseRepo: courseRepo, sectionRepo: sectionRepo, excuseRepo: excuseRepo, termService: termService, alertService: alertService, access: recordAccess{teacherRepo: teacherRepo, guardianRepo: guardianRepo}}
+}
+
+// CreateAttendance records a student's attendance in a course the caller teaches. Marking the same
+// student, course, date and period again updates the existing record; the result reports whether a
+// new record was created.
+func (s *AttendanceServiceImpl) CreateAttendance(ctx context.Context, attendance *models.Attendance, recordedBy uuid.UUID) (bool, error) {
+	if err := s.access.canChange(ctx, attendance.CourseID); err != nil {
+		return false, err
+	}
+	if err := s.validate(ctx, attendance); err != nil {
+		return false, err
+	}
+	created, err := s.attendanceRepo.Upsert(attendance, recordedBy)
+	if err != nil {
+		return false, err
+	}
+	s.checkAlerts(attendance.StudentID)
+	return created, nil
+}
+
+// RecordIfAbsent records a student's attendance in a course the caller teaches unless the student's
+// attendance for the course, date and period is already recorded, in which case the existing record is
+// returned unchanged
+func (s *AttendanceServiceImpl) RecordIfAbsent(ctx context.Context, attendance *models.Attendance, recordedBy uuid.UUID) (*models.Attendance, error) {
+	if err := s.access.canChange(ctx, attendance.CourseID); err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, attendance); err != nil {
+		return nil, err
+	}
+	created, err := s.attendanceRepo.CreateIfAbsent(attendance, recordedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return s.attendanceRepo.FindByKey(attendance.StudentID, attendance.CourseID, attendance.Date, attendance.Period)
+	}
+	s.checkAlerts(attendance.StudentID)
+	return attendance, nil
 }
 
-// CreateAttendance creates a new attendance record
-func (s *AttendanceServiceImpl) CreateAttendance(attendance *models.Attendance) error {
-	return s.attendanceRepo.Create(attendance)
+// checkAlerts evaluates the attendance alert rules for students whose attendance changed. It runs in
+// the background so recording attendance does not wait on it.
+func (s *AttendanceServiceImpl) checkAlerts(studentIDs ...uuid.UUID) {
+	if s.alertService == nil || len(studentIDs) == 0 {
+		return
+	}
+	go func() {
+		for _, studentID := range studentIDs {
+			if _, err := s.alertService.EvaluateStudent(context.Background(), studentID); err != nil {
+				log.Printf("Failed to evaluate attendance alerts for student %s: %v", studentID, err)
+			}
+		}
+	}()
 }
 
-// UpdateAttendance updates an attendance record
-func (s *AttendanceServiceImpl) UpdateAttendance(attendance *models.Attendance) error {
-	return s.attendanceRepo.Update(attendance)
+// UpdateAttendance updates an attendance record, which the caller must teach the course of both before
+// and after
+func (s *AttendanceServiceImpl) UpdateAttendance(ctx context.Context, attendance *models.Attendance) error {
+	existing, err := s.attendanceRepo.FindByID(attendance.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, existing.CourseID); err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, attendance.CourseID); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, attendance); err != nil {
+		return err
+	}
+
+	// Moving a record onto another record's student, course, date and period would duplicate it
+	duplicate, err := s.attendanceRepo.FindByKey(attendance.StudentID, attendance.CourseID, attendance.Date, attendance.Period)
+	if err == nil && duplicate.ID != attendance.ID {
+		return fmt.Errorf("%w: attendance is already recorded for this student, course, date and period", ErrInvalidAttendance)
+	}
+
+	if err := s.attendanceRepo.Update(attendance); err != nil {
+		return err
+	}
+	s.checkAlerts(attendance.StudentID)
+	return nil
 }
 
-// DeleteAttendance deletes an attendance record
-func (s *AttendanceServiceImpl) DeleteAttendance(id uuid.UUID) error {
+// validate normalises the record's date and checks its fields and that the student is enrolled in the course
+func (s *AttendanceServiceImpl) validate(ctx context.Context, attendance *models.Attendance) error {
+	attendance.Date = models.DateOnly(attendance.Date)
+	if err := attendance.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAttendance, err)
+	}
+
+	enrolled, err := s.courseRepo.HasStudent(ctx, attendance.CourseID, attendance.StudentID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return fmt.Errorf("%w: student is not enrolled in this course", ErrInvalidAttendance)
+	}
+	if err := s.applyMeeting(ctx, attendance); err != nil {
+		return err
+	}
+	return s.applyExcuse(ctx, attendance)
+}
+
+// applyExcuse excuses an absence on a date covered by one of the student's approved excuse requests,
+// and unlinks a record from its excuse request once it is no longer excused
+func (s *AttendanceServiceImpl) applyExcuse(ctx context.Context, attendance *models.Attendance) error {
+	if attendance.Status != models.Absent {
+		if attendance.Status != models.Excused {
+			attendance.ExcuseRequestID = nil
+		}
+		return nil
+	}
+
+	excuse, err := s.excuseRepo.FindApproved(ctx, attendance.StudentID, attendance.Date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		attendance.ExcuseRequestID = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	attendance.Status = models.Excused
+	attendance.ExcuseRequestID = &excuse.ID
+	return nil
+}
+
+// applyMeeting times a record against the section meeting it refers to, either by ID or as the given
+// period of the day in the student's section of the course. Records with no known meeting keep the
+// minutes they were given.
+func (s *AttendanceServiceImpl) applyMeeting(ctx context.Context, attendance *models.Attendance) error {
+	sections, err := s.sectionRepo.FindByStudent(ctx, attendance.StudentID, nil)
+	if err != nil {
+		return err
+	}
+	var section *models.Section
+	for i := range sections {
+		if sections[i].CourseID == attendance.CourseID && sections[i].Term != nil && sections[i].Term.Contains(attendance.Date) {
+			section = &sections[i]
+			break
+		}
+	}
+
+	var meeting *models.SectionMeeting
+	if attendance.MeetingID != nil {
+		if section == nil {
+			return fmt.Errorf("%w: student has no section of this course on this date", ErrInvalidAttendance)
+		}
+		meeting = section.Meeting(*attendance.MeetingID)
+		if meeting == nil {
+			return fmt.Errorf("%w: meeting is not one of the student's section meetings", ErrInvalidAttendance)
+		}
+		if meeting.Day != attendance.Date.Weekday() {
+			return fmt.Errorf("%w: meeting is not held on a %s", ErrInvalidAttendance, attendance.Date.Weekday())
+		}
+	} else if section != nil {
+		meeting = section.MeetingOn(attendance.Date.Weekday(), attendance.Period)
+	}
+	if meeting != nil {
+		attendance.ApplyMeeting(meeting)
+	}
+
+	// Arriving after the start makes a present student late
+	if attendance.Status == models.Present && attendance.MinutesLate > 0 {
+		attendance.Status = models.Late
+	}
+	return nil
+}
+
+// DeleteAttendance deletes an attendance record in a course the caller teaches
+func (s *AttendanceServiceImpl) DeleteAttendance(ctx context.Context, id uuid.UUID) error {
+	attendance, err := s.attendanceRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, attendance.CourseID); err != nil {
+		return err
+	}
 	return s.attendanceRepo.Delete(id)
 }
 
 // GetAttendanceByID gets an attendance record by ID
-func (s *AttendanceServiceImpl) GetAttendanceByID(id uuid.UUID) (*models.Attendance, error) {
-	return s.attendanceRepo.FindByID(id)
+func (s *AttendanceServiceImpl) GetAttendanceByID(ctx context.Context, id uuid.UUID) (*models.Attendance, error) {
+	attendance, err := s.attendanceRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.canRead(ctx, attendance.StudentID, attendance.CourseID); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+// GetAllAttendances gets all the attendance records the caller can see, optionally limited to a term
+func (s *AttendanceServiceImpl) GetAllAttendances(ctx context.Context, term string) ([]models.Attendance, error) {
+	filter, err := s.termFilter(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := s.access.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.FindAll(filter, scope)
 }
 
-// GetAllAttendances gets all attendance records
-func (s *AttendanceServiceImpl) GetAllAttendances() ([]models.Attendance, error) {
-	return s.attendanceRepo.FindAll()
+// GetAttendancesByStudent gets the attendance records of a student the caller can see, optionally
+// limited to a term
+func (s *AttendanceServiceImpl) GetAttendancesByStudent(ctx context.Context, studentID uuid.UUID, term string) ([]models.Attendance, error) {
+	filter, err := s.termFilter(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := s.access.studentScope(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.FindByStudent(studentID, filter, scope)
 }
 
-// GetAttendancesByStudent gets attendance records by student ID
-func (s *AttendanceServiceImpl) GetAttendancesByStudent(studentID uuid.UUID) ([]models.Attendance, error) {
-	return s.attendanceRepo.FindByStudent(studentID)
+// GetAttendancesByCourse gets the attendance records in a course the caller can see, optionally
+// limited to a term
+func (s *AttendanceServiceImpl) GetAttendancesByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.Attendance, error) {
+	filter, err := s.termFilter(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := s.access.courseScope(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.FindByCourse(courseID, filter, scope)
 }
 
-// GetAttendancesByCourse gets attendance records by course ID
-func (s *AttendanceServiceImpl) GetAttendancesByCourse(courseID uuid.UUID) ([]models.Attendance, error) {
-	return s.attendanceRepo.FindByCourse(courseID)
+// termFilter resolves a term name into the term whose dates limit a query; no name means no limit
+func (s *AttendanceServiceImpl) termFilter(ctx context.Context, term string) (*models.Term, error) {
+	if term == "" {
+		return nil, nil
+	}
+	return s.termService.ResolveTerm(ctx, term)
 }
 
-// GetAttendancesByDate gets attendance records by date
-func (s *AttendanceServiceImpl) GetAttendancesByDate(date time.Time) ([]models.Attendance, error) {
-	return s.attendanceRepo.FindByDate(date)
+// GetAttendancesByDate gets the attendance records on a date the caller can see
+func (s *AttendanceServiceImpl) GetAttendancesByDate(ctx context.Context, date time.Time) ([]models.Attendance, error) {
+	scope, err := s.access.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.FindByDate(date, scope)
 }
 
-// GetAttendancesByDateRange gets attendance records within a date range
-func (s *AttendanceServiceImpl) GetAttendancesByDateRange(startDate, endDate time.Time) ([]models.Attendance, error) {
-	return s.attendanceRepo.FindByDateRange(startDate, endDate)
+// GetAttendancesByDateRange gets the attendance records within a date range the caller can see
+func (s *AttendanceServiceImpl) GetAttendancesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Attendance, error) {
+	scope, err := s.access.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.FindByDateRange(startDate, endDate, scope)
 }
 
-// GetAttendancesByCourseAndDate gets attendance records by course ID and date
-func (s *AttendanceServiceImpl) GetAttendancesByCourseAndDate(courseID uuid.UUID, date time.Time) ([]models.Attendance, error) {
-	return s.attendanceRepo.FindByCourseAndDate(courseID, date)
+// GetAttendancesByCourseAndDate gets the attendance records in a course on a date the caller can see
+func (s *AttendanceServiceImpl) GetAttendancesByCourseAndDate(ctx context.Context, courseID uuid.UUID, date time.Time) ([]models.Attendance, error) {
+	scope, err := s.access.courseScope(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.FindByCourseAndDate(courseID, date, scope)
 }
 
-// GetStudentAttendanceReport gets a report of a student's attendance
-func (s *AttendanceServiceImpl) GetStudentAttendanceReport(studentID uuid.UUID) (map[string]int, error) {
-	return s.attendanceRepo.GetStudentAttendanceReport(studentID)
+// GetStudentAttendanceReport gets a report of a student's attendance in the courses the caller can see
+func (s *AttendanceServiceImpl) GetStudentAttendanceReport(ctx context.Context, studentID uuid.UUID) (map[string]int, error) {
+	scope, err := s.access.studentScope(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attendanceRepo.GetStudentAttendanceReport(studentID, scope)
 }
 
 // GetCourseAttendanceReport gets a report of attendance for a course
-func (s *AttendanceServiceImpl) GetCourseAttendanceReport(courseID uuid.UUID) (map[string]map[string]int, error) {
+func (s *AttendanceServiceImpl) GetCourseAttendanceReport(ctx context.Context, courseID uuid.UUID) (map[string]map[string]int, error) {
+	if err := s.access.canReadCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
 	return s.attendanceRepo.GetCourseAttendanceReport(courseID)
 }
+
+// GetStudentAttendanceRates gets a student's attendance rate in each course of a term the caller can
+// see, the current term when none is given, counting the sessions the school calendar held up to today
+func (s *AttendanceServiceImpl) GetStudentAttendanceRates(ctx context.Context, studentID uuid.UUID, term string) (*models.StudentAttendanceRates, error) {
+	scope, err := s.access.studentScope(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	t, calendar, err := s.termCalendar(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	through := models.DateOnly(time.Now())
+	all, err := s.attendanceRepo.GetStudentAttendanceRates(studentID, t, calendar, through)
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]models.AttendanceRate, 0, len(all))
+	for _, rate := range all {
+		if rate.CourseID == nil || scope.AllowsCourse(*rate.CourseID) {
+			rates = append(rates, rate)
+		}
+	}
+
+	report := &models.StudentAttendanceRates{StudentID: studentID, Term: t.Name, Through: through.Format("2006-01-02"), Courses: rates}
+	for i := range rates {
+		report.Total.Merge(&rates[i])
+	}
+	sort.Slice(report.Courses, func(i, j int) bool {
+		return report.Courses[i].CourseCode < report.Courses[j].CourseCode
+	})
+	return report, nil
+}
+
+// GetCourseAttendanceRates gets the attendance rate of each student in a course in a term, the current
+// term when none is given, counting the sessions the school calendar held up to today
+func (s *AttendanceServiceImpl) GetCourseAttendanceRates(ctx context.Context, courseID uuid.UUID, term string) (*models.CourseAttendanceRates, error) {
+	if err := s.access.canReadCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	t, calendar, err := s.termCalendar(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	through := models.DateOnly(time.Now())
+	rates, err := s.attendanceRepo.GetCourseAttendanceRates(courseID, t, calendar, through)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CourseAttendanceRates{CourseID: courseID, Term: t.Name, Through: through.Format("2006-01-02"), Students: rates}
+	if report.Students == nil {
+		report.Students = []models.AttendanceRate{}
+	}
+	for i := range rates {
+		report.Total.Merge(&rates[i])
+	}
+	sort.Slice(report.Students, func(i, j int) bool {
+		a, b := report.Students[i], report.Students[j]
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	})
+	return report, nil
+}
+
+// GetMissingAttendance lists the sessions of a teacher's sections in a term, the current term when none
+// is given, that had no attendance taken. The dates default to the start of the term and today.
+// Teachers can only list their own sessions.
+func (s *AttendanceServiceImpl) GetMissingAttendance(ctx context.Context, teacherID uuid.UUID, term string, from, to *time.Time) ([]models.ExpectedSession, error) {
+	if err := s.access.canReadTeacher(ctx, teacherID); err != nil {
+		return nil, err
+	}
+
+	t, calendar, err := s.termCalendar(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := t.StartDate, models.DateOnly(time.Now())
+	if from != nil {
+		start = models.DateOnly(*from)
+	}
+	if to != nil {
+		end = models.DateOnly(*to)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end date must not be before start date", ErrInvalidAttendance)
+	}
+	return s.attendanceRepo.FindSessionsWithoutAttendance(teacherID, t, calendar, start, end)
+}
+
+// termCalendar resolves a term name, the current term when empty, together with its school calendar
+func (s *AttendanceServiceImpl) termCalendar(ctx context.Context, term string) (*models.Term, *models.SchoolCalendar, error) {
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, nil, err
+	}
+	calendar, err := s.termService.GetCalendar(ctx, t.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, calendar, nil
+}
+
+// GetStudentMinutesReport totals the instructional minutes a student missed in each course the caller
+// can see, optionally limited to a term
+func (s *AttendanceServiceImpl) GetStudentMinutesReport(ctx context.Context, studentID uuid.UUID, term string) (*models.StudentMinutesReport, error) {
+	scope, err := s.access.studentScope(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := s.termFilter(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := s.attendanceRepo.FindByStudent(studentID, filter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.StudentMinutesReport{StudentID: studentID, Term: term, Courses: []models.AttendanceMinutes{}}
+	courses := make(map[uuid.UUID]int)
+	for i := range attendances {
+		attendance := &attendances[i]
+		index, ok := courses[attendance.CourseID]
+		if !ok {
+			courseID := attendance.CourseID
+			index = len(report.Courses)
+			courses[courseID] = index
+			report.Courses = append(report.Courses, models.AttendanceMinutes{CourseID: &courseID, CourseCode: attendance.Course.Code})
+		}
+		report.Courses[index].Add(attendance)
+		report.Total.Add(attendance)
+	}
+
+	sort.Slice(report.Courses, func(i, j int) bool {
+		return report.Courses[i].CourseCode < report.Courses[j].CourseCode
+	})
+	return report, nil
+}
+
+// GetCourseMinutesReport totals the instructional minutes each student on a course's roster missed,
+// optionally limited to a term
+func (s *AttendanceServiceImpl) GetCourseMinutesReport(ctx context.Context, courseID uuid.UUID, term string) (*models.CourseMinutesReport, error) {
+	if err := s.access.canReadCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	filter, err := s.termFilter(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	roster, err := s.courseRepo.GetStudents(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := s.attendanceRepo.FindByCourse(courseID, filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CourseMinutesReport{CourseID: courseID, Term: term, Students: make([]models.AttendanceMinutes, 0, len(roster))}
+	students := make(map[uuid.UUID]int, len(roster))
+	for _, student := range roster {
+		studentID := student.ID
+		students[studentID] = len(report.Students)
+		report.Students = append(report.Students, models.AttendanceMinutes{StudentID: &studentID, FirstName: student.FirstName, LastName: student.LastName})
+	}
+
+	// Students who have since dropped the course still count towards it
+	for i := range attendances {
+		attendance := &attendances[i]
+		index, ok := students[attendance.StudentID]
+		if !ok {
+			studentID := attendance.StudentID
+			index = len(report.Students)
+			students[studentID] = index
+			report.Students = append(report.Students, models.AttendanceMinutes{StudentID: &studentID, FirstName: attendance.Student.FirstName, LastName: attendance.Student.LastName})
+		}
+		report.Students[index].Add(attendance)
+		report.Total.Add(attendance)
+	}
+
+	sort.Slice(report.Students, func(i, j int) bool {
+		a, b := report.Students[i], report.Students[j]
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	})
+	return report, nil
+}
+
+// GetRollCall gets a course's roster for a date and period merged with the attendance already recorded
+func (s *AttendanceServiceImpl) GetRollCall(ctx context.Context, courseID uuid.UUID, date time.Time, period int) (*models.RollCall, error) {
+	if err := s.access.canReadCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	roster, err := s.courseRepo.GetStudents(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := s.attendanceRepo.FindByCourseAndDate(courseID, date, nil)
+	if err != nil {
+		return nil, err
+	}
+	marks := make(map[uuid.UUID]models.Attendance, len(recorded))
+	for _, attendance := range recorded {
+		if attendance.Period == period {
+			marks[attendance.StudentID] = attendance
+		}
+	}
+
+	rollCall := &models.RollCall{
+		CourseID: courseID,
+		Date:     date.Format("2006-01-02"),
+		Period:   period,
+		Entries:  make([]models.RollCallEntry, 0, len(roster)),
+	}
+	for _, student := range roster {
+		entry := models.RollCallEntry{
+			StudentID: student.ID,
+			FirstName: student.FirstName,
+			LastName:  student.LastName,
+		}
+		if attendance, ok := marks[student.ID]; ok {
+			id := attendance.ID
+			entry.AttendanceID = &id
+			entry.Status = attendance.Status
+			entry.ArrivalTime = attendance.ArrivalTime
+			entry.DepartureTime = attendance.DepartureTime
+			entry.MinutesLate = attendance.MinutesLate
+			entry.MinutesLeftEarly = attendance.MinutesLeftEarly
+			entry.Notes = attendance.Notes
+			entry.Marked = true
+		}
+		rollCall.Entries = append(rollCall.Entries, entry)
+	}
+	return rollCall, nil
+}
+
+// TakeRollCall records the attendance of a course the caller teaches for a date and period in one go.
+// Enrolled students without a mark keep any status already recorded for the session and are otherwise
+// marked present.
+func (s *AttendanceServiceImpl) TakeRollCall(ctx context.Context, courseID uuid.UUID, date time.Time, period int, marks []models.RollCallMark, takenBy uuid.UUID) (*models.RollCall, error) {
+	if err := s.access.canChange(ctx, courseID); err != nil {
+		return nil, err
+	}
+	if period < 0 {
+		return nil, fmt.Errorf("%w: period cannot be negative", ErrInvalidAttendance)
+	}
+	date = models.DateOnly(date)
+
+	current, err := s.GetRollCall(ctx, courseID, date, period)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled := make(map[uuid.UUID]models.RollCallEntry, len(current.Entries))
+	for _, entry := range current.Entries {
+		enrolled[entry.StudentID] = entry
+	}
+
+	marked := make(map[uuid.UUID]models.RollCallMark, len(marks))
+	for _, mark := range marks {
+		if _, ok := enrolled[mark.StudentID]; !ok {
+			return nil, fmt.Errorf("%w: student %s is not enrolled in this course", ErrInvalidAttendance, mark.StudentID)
+		}
+		if _, ok := marked[mark.StudentID]; ok {
+			return nil, fmt.Errorf("%w: student %s is marked more than once", ErrInvalidAttendance, mark.StudentID)
+		}
+		if !mark.Status.IsValid() {
+			return nil, fmt.Errorf("%w: invalid status %q for student %s", ErrInvalidAttendance, mark.Status, mark.StudentID)
+		}
+		marked[mark.StudentID] = mark
+	}
+
+	records := make([]models.Attendance, 0, len(current.Entries))
+	for _, entry := range current.Entries {
+		record := models.Attendance{
+			StudentID: entry.StudentID,
+			CourseID:  courseID,
+			Date:      date,
+			Period:    period,
+			Status:    models.Present,
+		}
+		if mark, ok := marked[entry.StudentID]; ok {
+			record.Status = mark.Status
+			record.ArrivalTime = mark.ArrivalTime
+			record.DepartureTime = mark.DepartureTime
+			record.Notes = mark.Notes
+		} else if entry.Marked {
+			continue
+		}
+
+		if err := record.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: student %s: %v", ErrInvalidAttendance, entry.StudentID, err)
+		}
+		if err := s.applyMeeting(ctx, &record); err != nil {
+			return nil, err
+		}
+		if err := s.applyExcuse(ctx, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := s.attendanceRepo.SaveRoll(records, takenBy); err != nil {
+		return nil, err
+	}
+
+	studentIDs := make([]uuid.UUID, 0, len(records))
+	for _, record := range records {
+		studentIDs = append(studentIDs, record.StudentID)
+	}
+	s.checkAlerts(studentIDs...)
+	return s.GetRollCall(ctx, courseID, date, period)
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultCheckInTTL is how long a check-in code stays valid when no lifetime is asked for
+	DefaultCheckInTTL = 2 * time.Minute
+	// MaxCheckInTTL bounds how long a check-in code can stay valid, so a shared photo of it soon goes stale
+	MaxCheckInTTL = 15 * time.Minute
+	// checkInOpensEarly is how long before a meeting starts students can check in to it
+	checkInOpensEarly = 15
+	// checkInAudience marks check-in codes so they are never mistaken for login tokens
+	checkInAudience = "attendance-check-in"
+)
+
+var (
+	// ErrInvalidCheckIn is returned when a check-in code or the check-in it is used for is not valid
+	ErrInvalidCheckIn = errors.New("invalid check-in")
+	// ErrAlreadyCheckedIn is returned when a student checks in to a session a second time
+	ErrAlreadyCheckedIn = errors.New("already checked in to this session")
+)
+
+// checkInClaims are the claims of a signed check-in code for one section meeting on one date
+type checkInClaims struct {
+	MeetingID uuid.UUID `json:"mid"`
+	SectionID uuid.UUID `json:"sid"`
+	CourseID  uuid.UUID `json:"cid"`
+	Date      string    `json:"date"`
+	jwt.RegisteredClaims
+}
+
+// CheckInService defines the interface for self-service attendance check-in
+type CheckInService interface {
+	IssueSession(ctx context.Context, meetingID uuid.UUID, ttl time.Duration) (*models.CheckInSession, error)
+	CheckIn(ctx context.Context, token string, studentID, userID uuid.UUID, ipAddress string) (*models.CheckInResult, error)
+}
+
+// CheckInServiceImpl implements the CheckInService interface
+type CheckInServiceImpl struct {
+	checkInRepo       repositories.CheckInRepository
+	sectionRepo       repositories.SectionRepository
+	studentRepo       repositories.StudentRepository
+	attendanceService AttendanceService
+	access            recordAccess
+	signingKey        []byte
+}
+
+// NewCheckInService creates a new instance of CheckInServiceImpl. Check-in codes are signed with a key
+// derived from the JWT secret, so they cannot be used as login tokens or the other way round.
+func NewCheckInService(
+	checkInRepo repositories.CheckInRepository,
+	sectionRepo repositories.SectionRepository,
+	studentRepo repositories.StudentRepository,
+	teacherRepo repositories.TeacherRepository,
+	attendanceService AttendanceService,
+	jwtSecret string,
+) CheckInService {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(checkInAudience))
+	return &CheckInServiceImpl{
+		checkInRepo:       checkInRepo,
+		sectionRepo:       sectionRepo,
+		studentRepo:       studentRepo,
+		attendanceService: attendanceService,
+		access:            recordAccess{teacherRepo: teacherRepo},
+		signingKey:        mac.Sum(nil),
+	}
+}
+
+// IssueSession signs a short-lived check-in code for today's occurrence of a section meeting, for admins
+// and the teachers of the section's course. A zero ttl issues a code valid for DefaultCheckInTTL.
+func (s *CheckInServiceImpl) IssueSession(ctx context.Context, meetingID uuid.UUID, ttl time.Duration) (*models.CheckInSession, error) {
+	if ttl == 0 {
+		ttl = DefaultCheckInTTL
+	}
+	if ttl < 0 || ttl > MaxCheckInTTL {
+		return nil, fmt.Errorf("%w: code lifetime must be at most %s", ErrInvalidCheckIn, MaxCheckInTTL)
+	}
+
+	section, meeting, err := s.findMeeting(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	// A code records attendance, so only those who may take it can issue one
+	if err := s.access.canChange(ctx, section.CourseID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	today := models.DateOnly(now)
+	if meeting.Day != today.Weekday() {
+		return nil, fmt.Errorf("%w: meeting is not held on a %s", ErrInvalidCheckIn, today.Weekday())
+	}
+	if section.Term != nil && !section.Term.Contains(today) {
+		return nil, fmt.Errorf("%w: section's term does not include today", ErrInvalidCheckIn)
+	}
+
+	session := &models.CheckInSession{
+		TokenID:   uuid.NewString(),
+		SectionID: section.ID,
+		CourseID:  section.CourseID,
+		MeetingID: meeting.ID,
+		Date:      today,
+		StartTime: meeting.StartTime,
+		EndTime:   meeting.EndTime,
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+	}
+	claims := checkInClaims{
+		MeetingID: session.MeetingID,
+		SectionID: session.SectionID,
+		CourseID:  session.CourseID,
+		Date:      today.Format("2006-01-02"),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        session.TokenID,
+			Audience:  jwt.ClaimStrings{checkInAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(session.ExpiresAt),
+		},
+	}
+	session.Token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CheckIn validates a scanned check-in code and records a student, checking in with their own login, as
+// present, or late when they arrive after the meeting starts, unless their attendance for the session is
+// already recorded. Each student can check in to a session once.
+func (s *CheckInServiceImpl) CheckIn(ctx context.Context, token string, studentID, userID uuid.UUID, ipAddress string) (*models.CheckInResult, error) {
+	var claims checkInClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		return s.signingKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(checkInAudience), jwt.WithExpirationRequired())
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, fmt.Errorf("%w: check-in code has expired", ErrInvalidCheckIn)
+	}
+	if err != nil || claims.ID == "" {
+		return nil, fmt.Errorf("%w: check-in code is not valid", ErrInvalidCheckIn)
+	}
+
+	now := time.Now()
+	today := models.DateOnly(now)
+	if claims.Date != today.Format("2006-01-02") {
+		return nil, fmt.Errorf("%w: check-in code has expired", ErrInvalidCheckIn)
+	}
+
+	section, meeting, err := s.findMeeting(ctx, claims.MeetingID)
+	if err != nil {
+		return nil, err
+	}
+	start, errStart := models.ParseClock(meeting.StartTime)
+	end, errEnd := models.ParseClock(meeting.EndTime)
+	if errStart != nil || errEnd != nil {
+		return nil, fmt.Errorf("%w: meeting has no valid times", ErrInvalidCheckIn)
+	}
+	clock := now.Hour()*60 + now.Minute()
+	if clock < start-checkInOpensEarly {
+		return nil, fmt.Errorf("%w: check-in opens at %s", ErrInvalidCheckIn, models.FormatClock(start-checkInOpensEarly))
+	}
+	if clock >= end {
+		return nil, fmt.Errorf("%w: the session has ended", ErrInvalidCheckIn)
+	}
+
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: student not found", ErrInvalidCheckIn)
+	}
+	enrolled, err := s.sectionRepo.FindStudentSection(ctx, student.ID, section.CourseID, section.TermID)
+	if err != nil || enrolled.ID != section.ID {
+		return nil, fmt.Errorf("%w: student is not enrolled in this section", ErrInvalidCheckIn)
+	}
+
+	checkIn := models.CheckIn{
+		TokenID:     claims.ID,
+		StudentID:   student.ID,
+		MeetingID:   meeting.ID,
+		Date:        today,
+		CourseID:    section.CourseID,
+		CheckedInAt: now,
+		IPAddress:   ipAddress,
+	}
+	recorded, err := s.checkInRepo.Create(ctx, &checkIn)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	attendance := &models.Attendance{
+		StudentID:   student.ID,
+		CourseID:    section.CourseID,
+		Date:        today,
+		Period:      section.PeriodOf(meeting),
+		MeetingID:   &meeting.ID,
+		Status:      models.Present,
+		ArrivalTime: models.FormatClock(clock),
+	}
+	// The student may not record attendance themselves, so it is recorded on their behalf. Attendance the
+	// teacher has already taken, such as marking the student absent or excused, is left as it is.
+	attendance, err = s.attendanceService.RecordIfAbsent(authz.System(ctx), attendance, userID)
+	if err != nil {
+		// Let the student try again once whatever stopped the attendance being recorded is fixed
+		if deleteErr := s.checkInRepo.Delete(ctx, checkIn.ID); deleteErr != nil {
+			return nil, deleteErr
+		}
+		return nil, err
+	}
+	if err := s.checkInRepo.SetAttendance(ctx, checkIn.ID, attendance.ID); err != nil {
+		return nil, err
+	}
+	checkIn.AttendanceID = &attendance.ID
+
+	return &models.CheckInResult{CheckIn: checkIn, Attendance: attendance}, nil
+}
+
+// findMeeting loads a section meeting together with its section
+func (s *CheckInServiceImpl) findMeeting(ctx context.Context, meetingID uuid.UUID) (*models.Section, *models.SectionMeeting, error) {
+	section, err := s.sectionRepo.FindByMeeting(ctx, meetingID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: meeting not found", ErrInvalidCheckIn)
+	}
+	meeting := section.Meeting(meetingID)
+	if meeting == nil {
+		return nil, nil, fmt.Errorf("%w: meeting not found", ErrInvalidCheckIn)
+	}
+	return section, meeting, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// MaxExcuseDocumentSize is the largest supporting document accepted with an excuse request
+const MaxExcuseDocumentSize = 5 << 20
+
+// excuseDocumentTypes maps the accepted document content types to the extension they are stored with
+var excuseDocumentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// ErrInvalidExcuse is returned when an excuse request or its document fails validation
+var ErrInvalidExcuse = errors.New("invalid excuse request")
+
+// ExcuseDocument is a supporting document uploaded with an excuse request
+type ExcuseDocument struct {
+	Name    string
+	Content io.Reader
+}
+
+// ExcuseService defines the interface for absence excuse requests
+type ExcuseService interface {
+	SubmitExcuse(ctx context.Context, request *models.ExcuseRequest, document *ExcuseDocument) error
+	GetExcuses(ctx context.Context, filter repositories.ExcuseFilter) ([]models.ExcuseRequest, error)
+	GetExcuseByID(ctx context.Context, id uuid.UUID) (*models.ExcuseRequest, error)
+	ApproveExcuse(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*models.ExcuseRequest, error)
+	RejectExcuse(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*models.ExcuseRequest, error)
+	DocumentPath(request *models.ExcuseRequest) (string, error)
+}
+
+// ExcuseServiceImpl implements the ExcuseService interface
+type ExcuseServiceImpl struct {
+	excuseRepo   repositories.ExcuseRepository
+	studentRepo  repositories.StudentRepository
+	guardianRepo repositories.GuardianRepository
+	uploadDir    string
+}
+
+// NewExcuseService creates a new instance of ExcuseServiceImpl that stores documents under uploadDir
+func NewExcuseService(excuseRepo repositories.ExcuseRepository, studentRepo repositories.StudentRepository, guardianRepo repositories.GuardianRepository, uploadDir string) ExcuseService {
+	return &ExcuseServiceImpl{
+		excuseRepo:   excuseRepo,
+		studentRepo:  studentRepo,
+		guardianRepo: guardianRepo,
+		uploadDir:    uploadDir,
+	}
+}
+
+// SubmitExcuse records a pending excuse request for a student, storing its supporting document if one is given.
+// Guardians may only excuse the absences of their own children.
+func (s *ExcuseServiceImpl) SubmitExcuse(ctx context.Context, request *models.ExcuseRequest, document *ExcuseDocument) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExcuse, err)
+	}
+
+	// Check if student exists
+	if _, err := s.studentRepo.GetByID(ctx, request.StudentID); err != nil {
+		return fmt.Errorf("%w: student not found", ErrInvalidExcuse)
+	}
+	if err := s.checkGuardian(ctx, request.StudentID); err != nil {
+		return err
+	}
+
+	request.ID = uuid.New()
+	request.Status = models.ExcusePending
+	request.ReviewedBy = nil
+	request.ReviewedAt = nil
+	request.ReviewNotes = ""
+	request.ExcusedCount = 0
+	request.Student = nil
+
+	if document != nil {
+		if err := s.saveDocument(request, document); err != nil {
+			return err
+		}
+	}
+	if err := s.excuseRepo.Create(ctx, request); err != nil {
+		if request.DocumentPath != "" {
+			os.Remove(filepath.Join(s.uploadDir, request.DocumentPath))
+		}
+		return err
+	}
+	return nil
+}
+
+// checkGuardian checks that a guardian submitting an excuse request is a guardian of the student
+func (s *ExcuseServiceImpl) checkGuardian(ctx context.Context, studentID uuid.UUID) error {
+	principal, err := authz.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.Role != authz.RoleGuardian {
+		return nil
+	}
+	if principal.GuardianID == nil {
+		return fmt.Errorf("%w: your login is not linked to a guardian", authz.ErrForbidden)
+	}
+	link, err := s.guardianRepo.FindLink(ctx, *principal.GuardianID, studentID)
+	if err != nil || link.RecordsWithheld {
+		return fmt.Errorf("%w: you are not a guardian of this student", authz.ErrForbidden)
+	}
+	return nil
+}
+
+// saveDocument checks a document's size and type from its content and writes it under the upload directory
+func (s *ExcuseServiceImpl) saveDocument(request *models.ExcuseRequest, document *ExcuseDocument) error {
+	content := bufio.NewReader(io.LimitReader(document.Content, MaxExcuseDocumentSize+1))
+	head, err := content.Peek(512)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if len(head) == 0 {
+		return fmt.Errorf("%w: document is empty", ErrInvalidExcuse)
+	}
+	contentType := http.DetectContentType(head)
+	ext, ok := excuseDocumentTypes[contentType]
+	if !ok {
+		return fmt.Errorf("%w: document must be a PDF, JPEG or PNG", ErrInvalidExcuse)
+	}
+
+	dir := filepath.Join(s.uploadDir, "excuses")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	relative := filepath.Join("excuses", request.ID.String()+ext)
+	path := filepath.Join(s.uploadDir, relative)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > MaxExcuseDocumentSize {
+		err = fmt.Errorf("%w: document must be at most %d MB", ErrInvalidExcuse, MaxExcuseDocumentSize>>20)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	request.DocumentName = filepath.Base(document.Name)
+	request.DocumentType = contentType
+	request.DocumentPath = relative
+	return nil
+}
+
+// GetExcuses retrieves the excuse requests matching a filter
+func (s *ExcuseServiceImpl) GetExcuses(ctx context.Context, filter repositories.ExcuseFilter) ([]models.ExcuseRequest, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errors.New("status must be pending, approved or rejected")
+	}
+	return s.excuseRepo.GetAll(ctx, filter)
+}
+
+// GetExcuseByID retrieves an excuse request by ID
+func (s *ExcuseServiceImpl) GetExcuseByID(ctx context.Context, id uuid.UUID) (*models.ExcuseRequest, error) {
+	return s.excuseRepo.GetByID(ctx, id)
+}
+
+// ApproveExcuse approves a pending request, converting the student's absences in its date range to excused
+func (s *ExcuseServiceImpl) ApproveExcuse(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*models.ExcuseRequest, error) {
+	request, err := s.review(ctx, id, reviewerID, notes, models.ExcuseApproved)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.excuseRepo.Approve(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// RejectExcuse rejects a pending request, leaving the student's attendance as recorded
+func (s *ExcuseServiceImpl) RejectExcuse(ctx context.Context, id, reviewerID uuid.UUID, notes string) (*models.ExcuseRequest, error) {
+	request, err := s.review(ctx, id, reviewerID, notes, models.ExcuseRejected)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.excuseRepo.Reject(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// review loads a pending request and records the reviewer's decision on it. The repository saves the
+// decision only while the request is still pending, as another reviewer may decide on it meanwhile.
+func (s *ExcuseServiceImpl) review(ctx context.Context, id, reviewerID uuid.UUID, notes string, status models.ExcuseStatus) (*models.ExcuseRequest, error) {
+	request, err := s.excuseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.ExcusePending {
+		return nil, fmt.Errorf("excuse request is already %s", request.Status)
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+	request.ReviewNotes = notes
+	return request, nil
+}
+
+// DocumentPath returns where a request's supporting document is stored
+func (s *ExcuseServiceImpl) DocumentPath(request *models.ExcuseRequest) (string, error) {
+	if request.DocumentPath == "" {
+		return "", errors.New("excuse request has no document")
+	}
+	return filepath.Join(s.uploadDir, request.DocumentPath), nil
+}
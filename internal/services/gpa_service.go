@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidGPAQuery is returned when a GPA query has an unknown scope or policy
+var ErrInvalidGPAQuery = errors.New("invalid GPA query")
+
+// GPAService defines methods for GPA calculation
+type GPAService interface {
+	GetStudentGPA(ctx context.Context, studentID uuid.UUID, query models.GPAQuery) (*models.GPAResult, error)
+}
+
+// GPAServiceImpl implements the GPAService interface
+type GPAServiceImpl struct {
+	gradeRepo     repositories.GradeRepository
+	scaleService  GradingScaleService
+	defaultPolicy models.RetakePolicy
+	access        recordAccess
+}
+
+// NewGPAService creates a new GPAService
+func NewGPAService(gradeRepo repositories.GradeRepository, teacherRepo repositories.TeacherRepository, guardianRepo repositories.GuardianRepository, scaleService GradingScaleService, defaultPolicy string) GPAService {
+	policy := models.RetakePolicy(defaultPolicy)
+	if !policy.IsValid() {
+		policy = models.RetakeLatest
+	}
+	return &GPAServiceImpl{gradeRepo: gradeRepo, scaleService: scaleService, defaultPolicy: policy, access: recordAccess{teacherRepo: teacherRepo, guardianRepo: guardianRepo}}
+}
+
+// GetStudentGPA calculates a credit-weighted GPA for a student. As it covers every course the student
+// took, teachers, who only see the grades of their own courses, cannot see it.
+func (s *GPAServiceImpl) GetStudentGPA(ctx context.Context, studentID uuid.UUID, query models.GPAQuery) (*models.GPAResult, error) {
+	if err := s.access.canReadTranscript(ctx, studentID); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeQuery(&query); err != nil {
+		return nil, err
+	}
+
+	grades, err := s.gradeRepo.FindByStudent(studentID, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group attempts by course, keeping only grades inside the requested scope
+	attempts := make(map[uuid.UUID][]models.Grade)
+	for _, grade := range grades {
+		if query.Term != "" && grade.Term != query.Term {
+			continue
+		}
+		if query.Scope == models.GPAScopeDepartment && grade.Course.Department != query.Department {
+			continue
+		}
+		attempts[grade.CourseID] = append(attempts[grade.CourseID], grade)
+	}
+
+	result := &models.GPAResult{
+		StudentID:    studentID,
+		Scope:        query.Scope,
+		Term:         query.Term,
+		Department:   query.Department,
+		RetakePolicy: query.RetakePolicy,
+		Courses:      []models.GPACourse{},
+	}
+
+	scales := make(map[scaleKey]*models.GradingScale)
+	for _, courseGrades := range attempts {
+		scored, err := s.scoreAttempts(ctx, courseGrades, scales)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := resolveRetakes(scored, query.RetakePolicy)
+		result.Courses = append(result.Courses, entry)
+		if !entry.CountsTowardGPA {
+			continue
+		}
+		result.TotalCredits += entry.Credits
+		result.QualityPoints += entry.GradePoints * float64(entry.Credits)
+	}
+
+	sort.Slice(result.Courses, func(i, j int) bool {
+		return result.Courses[i].Code < result.Courses[j].Code
+	})
+
+	if result.TotalCredits > 0 {
+		result.GPA = math.Round(result.QualityPoints/float64(result.TotalCredits)*100) / 100
+	}
+
+	return result, nil
+}
+
+// normalizeQuery fills in defaults and validates the query
+func (s *GPAServiceImpl) normalizeQuery(query *models.GPAQuery) error {
+	if query.RetakePolicy == "" {
+		query.RetakePolicy = s.defaultPolicy
+	}
+	if !query.RetakePolicy.IsValid() {
+		return fmt.Errorf("%w: unknown retake policy %q", ErrInvalidGPAQuery, query.RetakePolicy)
+	}
+
+	if query.Scope == "" {
+		query.Scope = models.GPAScopeCumulative
+		if query.Term != "" {
+			query.Scope = models.GPAScopeTerm
+		}
+	}
+
+	switch query.Scope {
+	case models.GPAScopeCumulative:
+	case models.GPAScopeTerm:
+		if query.Term == "" {
+			return fmt.Errorf("%w: term scope requires a term", ErrInvalidGPAQuery)
+		}
+	case models.GPAScopeDepartment:
+		if query.Department == "" {
+			return fmt.Errorf("%w: department scope requires a department", ErrInvalidGPAQuery)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope %q", ErrInvalidGPAQuery, query.Scope)
+	}
+
+	return nil
+}
+
+// scoredAttempt is a grade together with the grade points its grading scale awards
+type scoredAttempt struct {
+	grade    models.Grade
+	points   float64
+	passFail bool
+}
+
+// scaleKey identifies the grading scale that applies to a course in a term
+type scaleKey struct {
+	courseID uuid.UUID
+	term     string
+}
+
+// scoreAttempts looks up grade points for each attempt in the scale that applied to it, resolving each
+// course and term's scale once and keeping it in scales for the other attempts
+func (s *GPAServiceImpl) scoreAttempts(ctx context.Context, grades []models.Grade, scales map[scaleKey]*models.GradingScale) ([]scoredAttempt, error) {
+	scored := make([]scoredAttempt, 0, len(grades))
+	for _, grade := range grades {
+		key := scaleKey{courseID: grade.CourseID, term: grade.Term}
+		scale, ok := scales[key]
+		if !ok {
+			var err error
+			scale, err = s.scaleService.ResolveScale(ctx, &grade.Course, grade.Term)
+			if err != nil {
+				return nil, err
+			}
+			scales[key] = scale
+		}
+
+		band := scale.BandForLetter(grade.Grade)
+		if band == nil {
+			band = scale.BandForScore(grade.Score)
+		}
+
+		attempt := scoredAttempt{grade: grade, passFail: scale.PassFail}
+		if band != nil {
+			attempt.points = band.GradePoints
+		}
+		scored = append(scored, attempt)
+	}
+	return scored, nil
+}
+
+// resolveRetakes collapses all attempts at a course into the single entry that counts toward GPA
+func resolveRetakes(attempts []scoredAttempt, policy models.RetakePolicy) models.GPACourse {
+	// Oldest attempt first so the last element is the latest attempt
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].grade.CreatedAt.Before(attempts[j].grade.CreatedAt)
+	})
+
+	counted := attempts[len(attempts)-1]
+	points := counted.points
+
+	switch policy {
+	case models.RetakeHighest:
+		for _, attempt := range attempts {
+			if attempt.points > points {
+				counted = attempt
+				points = attempt.points
+			}
+		}
+	case models.RetakeAverage:
+		var total float64
+		for _, attempt := range attempts {
+			total += attempt.points
+		}
+		points = total / float64(len(attempts))
+	}
+
+	return models.GPACourse{
+		CourseID:        counted.grade.CourseID,
+		Code:            counted.grade.Course.Code,
+		Name:            counted.grade.Course.Name,
+		Department:      counted.grade.Course.Department,
+		Credits:         counted.grade.Course.Credits,
+		Term:            counted.grade.Term,
+		Grade:           counted.grade.Grade,
+		GradePoints:     points,
+		Attempts:        len(attempts),
+		CountsTowardGPA: !counted.passFail,
+	}
+}
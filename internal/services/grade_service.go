@@ -1,92 +1,262 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"math"
+
 	"school-management-api/internal/models"
 	"school-management-api/internal/repositories"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // GradeService defines methods for grade management
 type GradeService interface {
-	CreateGrade(grade *models.Grade) error
-	UpdateGrade(grade *models.Grade) error
-	DeleteGrade(id uuid.UUID) error
-	GetGradeByID(id uuid.UUID) (*models.Grade, error)
-	GetAllGrades() ([]models.Grade, error)
-	GetGradesByStudent(studentID uuid.UUID) ([]models.Grade, error)
-	GetGradesByCourse(courseID uuid.UUID) ([]models.Grade, error)
-	GetGradesByStudentAndCourse(studentID, courseID uuid.UUID) ([]models.Grade, error)
-	GetGradesByTerm(term string) ([]models.Grade, error)
-	GetStudentGPA(studentID uuid.UUID) (float64, error)
-	GetCourseGradeDistribution(courseID uuid.UUID) (map[string]int, error)
+	CreateGrade(ctx context.Context, grade *models.Grade) error
+	UpdateGrade(ctx context.Context, grade *models.Grade) error
+	DeleteGrade(ctx context.Context, id uuid.UUID) error
+	GetGradeByID(ctx context.Context, id uuid.UUID) (*models.Grade, error)
+	GetAllGrades(ctx context.Context, term string) ([]models.Grade, error)
+	GetGradesByStudent(ctx context.Context, studentID uuid.UUID, term string) ([]models.Grade, error)
+	GetGradesByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.Grade, error)
+	GetGradesByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) ([]models.Grade, error)
+	GetGradesByTerm(ctx context.Context, term string) ([]models.Grade, error)
+	GetCourseGradeDistribution(ctx context.Context, courseID uuid.UUID) (map[string]int, error)
+	RollUpGrade(ctx context.Context, studentID, courseID uuid.UUID, term string, score float64, updatedBy uuid.UUID) (*models.Grade, error)
 }
 
 // GradeServiceImpl implements the GradeService interface
 type GradeServiceImpl struct {
-	gradeRepo repositories.GradeRepository
+	gradeRepo    repositories.GradeRepository
+	scaleService GradingScaleService
+	termService  TermService
+	access       recordAccess
 }
 
 // NewGradeService creates a new GradeService
-func NewGradeService(gradeRepo repositories.GradeRepository) GradeService {
-	return &GradeServiceImpl{gradeRepo: gradeRepo}
+func NewGradeService(gradeRepo repositories.GradeRepository, teacherRepo repositories.TeacherRepository, guardianRepo repositories.GuardianRepository, scaleService GradingScaleService, termService TermService) GradeService {
+	return &GradeServiceImpl{gradeRepo: gradeRepo, scaleService: scaleService, termService: termService, access: recordAccess{teacherRepo: teacherRepo, guardianRepo: guardianRepo}}
 }
 
-// CreateGrade creates a new grade
-func (s *GradeServiceImpl) CreateGrade(grade *models.Grade) error {
+// CreateGrade creates a new grade in a course the caller teaches
+func (s *GradeServiceImpl) CreateGrade(ctx context.Context, grade *models.Grade) error {
+	if err := s.access.canChange(ctx, grade.CourseID); err != nil {
+		return err
+	}
+
+	// Grades are filed against a configured term, the current one by default
+	if err := s.resolveTerm(ctx, grade); err != nil {
+		return err
+	}
+
 	// Calculate letter grade based on score
-	grade.CalculateGrade()
+	if err := s.calculateGrade(ctx, grade); err != nil {
+		return err
+	}
 	return s.gradeRepo.Create(grade)
 }
 
-// UpdateGrade updates a grade
-func (s *GradeServiceImpl) UpdateGrade(grade *models.Grade) error {
+// UpdateGrade updates a grade, which the caller must teach the course of both before and after
+func (s *GradeServiceImpl) UpdateGrade(ctx context.Context, grade *models.Grade) error {
+	existing, err := s.gradeRepo.FindByID(grade.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, existing.CourseID); err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, grade.CourseID); err != nil {
+		return err
+	}
+
+	// Grades filed before terms were configured keep their free-text term unless it is changed
+	if grade.Term != existing.Term {
+		if err := s.resolveTerm(ctx, grade); err != nil {
+			return err
+		}
+	}
+
 	// Calculate letter grade based on updated score
-	grade.CalculateGrade()
+	if err := s.calculateGrade(ctx, grade); err != nil {
+		return err
+	}
 	return s.gradeRepo.Update(grade)
 }
 
-// DeleteGrade deletes a grade
-func (s *GradeServiceImpl) DeleteGrade(id uuid.UUID) error {
+// resolveTerm checks that the grade's term exists and fills in the current term when none is given. Until
+// any term is configured, grades keep the term they are given, if any.
+func (s *GradeServiceImpl) resolveTerm(ctx context.Context, grade *models.Grade) error {
+	term, err := s.termService.ResolveTerm(ctx, grade.Term)
+	if errors.Is(err, ErrUnknownTerm) {
+		terms, listErr := s.termService.GetAllTerms(ctx)
+		if listErr != nil {
+			return listErr
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	grade.Term = term.Name
+	return nil
+}
+
+// checkTermFilter rejects term filters that do not match a configured term
+func (s *GradeServiceImpl) checkTermFilter(ctx context.Context, term string) error {
+	if term == "" {
+		return nil
+	}
+	_, err := s.termService.ResolveTerm(ctx, term)
+	return err
+}
+
+// calculateGrade sets the letter grade using the scale that applies to the grade's course and term
+func (s *GradeServiceImpl) calculateGrade(ctx context.Context, grade *models.Grade) error {
+	scale, err := s.scaleService.ResolveCourseScale(ctx, grade.CourseID, grade.Term)
+	if err != nil {
+		return err
+	}
+
+	grade.CalculateGrade(scale)
+	return nil
+}
+
+// DeleteGrade deletes a grade in a course the caller teaches
+func (s *GradeServiceImpl) DeleteGrade(ctx context.Context, id uuid.UUID) error {
+	grade, err := s.gradeRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.access.canChange(ctx, grade.CourseID); err != nil {
+		return err
+	}
 	return s.gradeRepo.Delete(id)
 }
 
 // GetGradeByID gets a grade by ID
-func (s *GradeServiceImpl) GetGradeByID(id uuid.UUID) (*models.Grade, error) {
-	return s.gradeRepo.FindByID(id)
+func (s *GradeServiceImpl) GetGradeByID(ctx context.Context, id uuid.UUID) (*models.Grade, error) {
+	grade, err := s.gradeRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.canRead(ctx, grade.StudentID, grade.CourseID); err != nil {
+		return nil, err
+	}
+	return grade, nil
 }
 
-// GetAllGrades gets all grades
-func (s *GradeServiceImpl) GetAllGrades() ([]models.Grade, error) {
-	return s.gradeRepo.FindAll()
+// GetAllGrades gets all the grades the caller can see, optionally limited to a term
+func (s *GradeServiceImpl) GetAllGrades(ctx context.Context, term string) ([]models.Grade, error) {
+	if err := s.checkTermFilter(ctx, term); err != nil {
+		return nil, err
+	}
+	scope, err := s.access.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.gradeRepo.FindAll(term, scope)
 }
 
-// GetGradesByStudent gets grades by student ID
-func (s *GradeServiceImpl) GetGradesByStudent(studentID uuid.UUID) ([]models.Grade, error) {
-	return s.gradeRepo.FindByStudent(studentID)
+// GetGradesByStudent gets the grades of a student the caller can see, optionally limited to a term
+func (s *GradeServiceImpl) GetGradesByStudent(ctx context.Context, studentID uuid.UUID, term string) ([]models.Grade, error) {
+	if err := s.checkTermFilter(ctx, term); err != nil {
+		return nil, err
+	}
+	scope, err := s.access.studentScope(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.gradeRepo.FindByStudent(studentID, term, scope)
 }
 
-// GetGradesByCourse gets grades by course ID
-func (s *GradeServiceImpl) GetGradesByCourse(courseID uuid.UUID) ([]models.Grade, error) {
-	return s.gradeRepo.FindByCourse(courseID)
+// GetGradesByCourse gets the grades in a course the caller can see, optionally limited to a term
+func (s *GradeServiceImpl) GetGradesByCourse(ctx context.Context, courseID uuid.UUID, term string) ([]models.Grade, error) {
+	if err := s.checkTermFilter(ctx, term); err != nil {
+		return nil, err
+	}
+	scope, err := s.access.courseScope(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.gradeRepo.FindByCourse(courseID, term, scope)
 }
 
 // GetGradesByStudentAndCourse gets grades by student ID and course ID
-func (s *GradeServiceImpl) GetGradesByStudentAndCourse(studentID, courseID uuid.UUID) ([]models.Grade, error) {
+func (s *GradeServiceImpl) GetGradesByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) ([]models.Grade, error) {
+	if err := s.access.canRead(ctx, studentID, courseID); err != nil {
+		return nil, err
+	}
 	return s.gradeRepo.FindByStudentAndCourse(studentID, courseID)
 }
 
-// GetGradesByTerm gets grades by term
-func (s *GradeServiceImpl) GetGradesByTerm(term string) ([]models.Grade, error) {
-	return s.gradeRepo.FindByTerm(term)
+// GetGradesByTerm gets the grades in a term the caller can see
+func (s *GradeServiceImpl) GetGradesByTerm(ctx context.Context, term string) ([]models.Grade, error) {
+	scope, err := s.access.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.gradeRepo.FindByTerm(term, scope)
 }
 
-// GetStudentGPA gets a student's GPA
-func (s *GradeServiceImpl) GetStudentGPA(studentID uuid.UUID) (float64, error) {
-	return s.gradeRepo.GetStudentGPA(studentID)
+// GetCourseGradeDistribution gets the distribution of grades for a course
+func (s *GradeServiceImpl) GetCourseGradeDistribution(ctx context.Context, courseID uuid.UUID) (map[string]int, error) {
+	if err := s.access.canReadCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	scale, err := s.scaleService.ResolveCourseScale(ctx, courseID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.gradeRepo.GetCourseGradeDistribution(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize the distribution with every letter of the course's scale
+	distribution := make(map[string]int)
+	for _, band := range scale.Bands {
+		distribution[band.Letter] = 0
+	}
+	for letter, count := range counts {
+		distribution[letter] += count
+	}
+
+	return distribution, nil
 }
 
-// GetCourseGradeDistribution gets the distribution of grades for a course
-func (s *GradeServiceImpl) GetCourseGradeDistribution(courseID uuid.UUID) (map[string]int, error) {
-	return s.gradeRepo.GetCourseGradeDistribution(courseID)
+// RollUpGrade records a computed gradebook score as the student's final grade for the course and term
+func (s *GradeServiceImpl) RollUpGrade(ctx context.Context, studentID, courseID uuid.UUID, term string, score float64, updatedBy uuid.UUID) (*models.Grade, error) {
+	score = math.Round(score*100) / 100
+
+	grade, err := s.gradeRepo.FindByStudentCourseAndTerm(studentID, courseID, term)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		grade = &models.Grade{
+			StudentID: studentID,
+			CourseID:  courseID,
+			Term:      term,
+			Score:     score,
+			CreatedBy: updatedBy,
+		}
+		if err := s.CreateGrade(ctx, grade); err != nil {
+			return nil, err
+		}
+		return grade, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	grade.Score = score
+	grade.UpdatedBy = updatedBy
+	if err := s.UpdateGrade(ctx, grade); err != nil {
+		return nil, err
+	}
+	return grade, nil
 }
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GradingScaleService defines the interface for grading scale service
+type GradingScaleService interface {
+	CreateScale(ctx context.Context, scale *models.GradingScale) error
+	GetScaleByID(ctx context.Context, id uuid.UUID) (*models.GradingScale, error)
+	GetAllScales(ctx context.Context) ([]models.GradingScale, error)
+	UpdateScale(ctx context.Context, scale *models.GradingScale) error
+	DeleteScale(ctx context.Context, id uuid.UUID) error
+	AssignScale(ctx context.Context, assignment *models.GradingScaleAssignment) error
+	RemoveAssignment(ctx context.Context, id uuid.UUID) error
+	GetScaleAssignments(ctx context.Context, scaleID uuid.UUID) ([]models.GradingScaleAssignment, error)
+	ResolveScale(ctx context.Context, course *models.Course, term string) (*models.GradingScale, error)
+	ResolveCourseScale(ctx context.Context, courseID uuid.UUID, term string) (*models.GradingScale, error)
+}
+
+// GradingScaleServiceImpl implements the GradingScaleService interface
+type GradingScaleServiceImpl struct {
+	scaleRepo  repositories.GradingScaleRepository
+	courseRepo repositories.CourseRepository
+}
+
+// NewGradingScaleService creates a new instance of GradingScaleServiceImpl
+func NewGradingScaleService(scaleRepo repositories.GradingScaleRepository, courseRepo repositories.CourseRepository) GradingScaleService {
+	return &GradingScaleServiceImpl{
+		scaleRepo:  scaleRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+// CreateScale creates a new grading scale
+func (s *GradingScaleServiceImpl) CreateScale(ctx context.Context, scale *models.GradingScale) error {
+	if err := scale.Validate(); err != nil {
+		return err
+	}
+	return s.scaleRepo.Create(ctx, scale)
+}
+
+// GetScaleByID retrieves a grading scale by its ID
+func (s *GradingScaleServiceImpl) GetScaleByID(ctx context.Context, id uuid.UUID) (*models.GradingScale, error) {
+	return s.scaleRepo.GetByID(ctx, id)
+}
+
+// GetAllScales retrieves all grading scales
+func (s *GradingScaleServiceImpl) GetAllScales(ctx context.Context) ([]models.GradingScale, error) {
+	return s.scaleRepo.GetAll(ctx)
+}
+
+// UpdateScale updates a grading scale and its bands
+func (s *GradingScaleServiceImpl) UpdateScale(ctx context.Context, scale *models.GradingScale) error {
+	// Check if scale exists
+	existingScale, err := s.scaleRepo.GetByID(ctx, scale.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := scale.Validate(); err != nil {
+		return err
+	}
+
+	scale.CreatedAt = existingScale.CreatedAt
+	return s.scaleRepo.Update(ctx, scale)
+}
+
+// DeleteScale deletes a grading scale
+func (s *GradingScaleServiceImpl) DeleteScale(ctx context.Context, id uuid.UUID) error {
+	// Check if scale exists
+	scale, err := s.scaleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if scale.IsDefault {
+		return errors.New("the default grading scale cannot be deleted")
+	}
+
+	return s.scaleRepo.Delete(ctx, id)
+}
+
+// AssignScale assigns a grading scale to a course, department or term
+func (s *GradingScaleServiceImpl) AssignScale(ctx context.Context, assignment *models.GradingScaleAssignment) error {
+	// Check if scale exists
+	_, err := s.scaleRepo.GetByID(ctx, assignment.ScaleID)
+	if err != nil {
+		return err
+	}
+
+	if assignment.CourseID == nil && assignment.Department == "" && assignment.Term == "" {
+		return errors.New("assignment must target a course, department or term")
+	}
+
+	return s.scaleRepo.CreateAssignment(ctx, assignment)
+}
+
+// RemoveAssignment removes a grading scale assignment
+func (s *GradingScaleServiceImpl) RemoveAssignment(ctx context.Context, id uuid.UUID) error {
+	return s.scaleRepo.DeleteAssignment(ctx, id)
+}
+
+// GetScaleAssignments gets all assignments for a grading scale
+func (s *GradingScaleServiceImpl) GetScaleAssignments(ctx context.Context, scaleID uuid.UUID) ([]models.GradingScaleAssignment, error) {
+	return s.scaleRepo.GetAssignments(ctx, scaleID)
+}
+
+// ResolveScale finds the most specific grading scale for a course in a term,
+// falling back to the school default and then the built-in standard scale
+func (s *GradingScaleServiceImpl) ResolveScale(ctx context.Context, course *models.Course, term string) (*models.GradingScale, error) {
+	assignments, err := s.scaleRepo.GetAllAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.GradingScaleAssignment
+	for i := range assignments {
+		if !assignments[i].Matches(course, term) {
+			continue
+		}
+		if best == nil || assignments[i].Specificity() > best.Specificity() {
+			best = &assignments[i]
+		}
+	}
+
+	if best != nil {
+		return s.scaleRepo.GetByID(ctx, best.ScaleID)
+	}
+
+	scale, err := s.scaleRepo.FindDefault(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultGradingScale(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// ResolveCourseScale loads a course and resolves the grading scale that applies to it in a term
+func (s *GradingScaleServiceImpl) ResolveCourseScale(ctx context.Context, courseID uuid.UUID, term string) (*models.GradingScale, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ResolveScale(ctx, course, term)
+}
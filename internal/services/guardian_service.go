@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidGuardian is returned when a guardian or their link to a student fails validation
+var ErrInvalidGuardian = errors.New("invalid guardian")
+
+// GuardianService defines the interface for guardians and the students they are linked to
+type GuardianService interface {
+	CreateGuardian(ctx context.Context, guardian *models.Guardian) (*models.ProvisionedAccount, error)
+	ProvisionAccount(ctx context.Context, id uuid.UUID, req models.AccountRequest) (*models.ProvisionedAccount, error)
+	GetGuardianByID(ctx context.Context, id uuid.UUID) (*models.Guardian, error)
+	GetAllGuardians(ctx context.Context, page, pageSize int) ([]models.Guardian, int64, error)
+	UpdateGuardian(ctx context.Context, guardian *models.Guardian) error
+	DeleteGuardian(ctx context.Context, id uuid.UUID) error
+	LinkStudent(ctx context.Context, link *models.StudentGuardian) error
+	UnlinkStudent(ctx context.Context, guardianID, studentID uuid.UUID) error
+	GetStudentGuardians(ctx context.Context, studentID uuid.UUID) ([]models.StudentGuardian, error)
+	GetChildren(ctx context.Context, guardianID uuid.UUID) ([]models.GuardianChild, error)
+	GetChild(ctx context.Context, guardianID, studentID uuid.UUID) (*models.GuardianChild, error)
+}
+
+// GuardianServiceImpl implements the GuardianService interface
+type GuardianServiceImpl struct {
+	guardianRepo repositories.GuardianRepository
+	studentRepo  repositories.StudentRepository
+	userService  UserService
+}
+
+// NewGuardianService creates a new instance of GuardianServiceImpl
+func NewGuardianService(
+	guardianRepo repositories.GuardianRepository,
+	studentRepo repositories.StudentRepository,
+	userService UserService,
+) GuardianService {
+	return &GuardianServiceImpl{
+		guardianRepo: guardianRepo,
+		studentRepo:  studentRepo,
+		userService:  userService,
+	}
+}
+
+// CreateGuardian creates a new guardian together with their login
+func (s *GuardianServiceImpl) CreateGuardian(ctx context.Context, guardian *models.Guardian) (*models.ProvisionedAccount, error) {
+	if err := guardian.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGuardian, err)
+	}
+
+	// Check if guardian with same email already exists
+	if guardian.Email != "" {
+		existingGuardian, err := s.guardianRepo.FindByEmail(ctx, guardian.Email)
+		if err == nil && existingGuardian.ID != uuid.Nil {
+			return nil, errors.New("guardian with this email already exists")
+		}
+	}
+
+	// A login is provisioned with the guardian unless asked not to
+	var req models.AccountRequest
+	if guardian.Account != nil {
+		req = *guardian.Account
+	}
+	if !req.Skip {
+		if err := s.userService.CheckAccount(ctx, guardianAccount(guardian), req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create guardian
+	guardian.Students = nil
+	if err := s.guardianRepo.Create(ctx, guardian); err != nil {
+		return nil, err
+	}
+	if req.Skip {
+		return nil, nil
+	}
+	account, err := s.userService.ProvisionAccount(ctx, guardianAccount(guardian), req)
+	if err != nil {
+		return nil, fmt.Errorf("guardian created, but their login could not be provisioned: %w", err)
+	}
+	return account, nil
+}
+
+// ProvisionAccount provisions the login of an existing guardian
+func (s *GuardianServiceImpl) ProvisionAccount(ctx context.Context, id uuid.UUID, req models.AccountRequest) (*models.ProvisionedAccount, error) {
+	guardian, err := s.guardianRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.userService.ProvisionAccount(ctx, guardianAccount(guardian), req)
+}
+
+// guardianAccount returns the login of a guardian before it is provisioned
+func guardianAccount(guardian *models.Guardian) *models.User {
+	account := &models.User{
+		Email:     guardian.Email,
+		FirstName: guardian.FirstName,
+		LastName:  guardian.LastName,
+		Role:      "Guardian",
+	}
+	if guardian.ID != uuid.Nil {
+		id := guardian.ID
+		account.GuardianID = &id
+	}
+	return account
+}
+
+// GetGuardianByID retrieves a guardian by their ID, with the students they are linked to
+func (s *GuardianServiceImpl) GetGuardianByID(ctx context.Context, id uuid.UUID) (*models.Guardian, error) {
+	return s.guardianRepo.GetByID(ctx, id)
+}
+
+// GetAllGuardians retrieves all guardians with pagination
+func (s *GuardianServiceImpl) GetAllGuardians(ctx context.Context, page, pageSize int) ([]models.Guardian, int64, error) {
+	return s.guardianRepo.GetAll(ctx, page, pageSize)
+}
+
+// UpdateGuardian updates a guardian's details, leaving their links to students as they are
+func (s *GuardianServiceImpl) UpdateGuardian(ctx context.Context, guardian *models.Guardian) error {
+	if err := guardian.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGuardian, err)
+	}
+
+	// Check if guardian exists
+	existingGuardian, err := s.guardianRepo.GetByID(ctx, guardian.ID)
+	if err != nil {
+		return err
+	}
+
+	// Check if email is already used by another guardian
+	if guardian.Email != "" && guardian.Email != existingGuardian.Email {
+		anotherGuardian, err := s.guardianRepo.FindByEmail(ctx, guardian.Email)
+		if err == nil && anotherGuardian.ID != uuid.Nil && anotherGuardian.ID != guardian.ID {
+			return errors.New("email is already used by another guardian")
+		}
+	}
+
+	guardian.CreatedAt = existingGuardian.CreatedAt
+	guardian.Students = nil
+	return s.guardianRepo.Update(ctx, guardian)
+}
+
+// DeleteGuardian deletes a guardian and their links to students
+func (s *GuardianServiceImpl) DeleteGuardian(ctx context.Context, id uuid.UUID) error {
+	// Check if guardian exists
+	_, err := s.guardianRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.guardianRepo.Delete(ctx, id)
+}
+
+// LinkStudent links a guardian to a student, or updates how they are linked when they already are
+func (s *GuardianServiceImpl) LinkStudent(ctx context.Context, link *models.StudentGuardian) error {
+	if err := link.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGuardian, err)
+	}
+
+	// Check if guardian and student exist
+	if _, err := s.guardianRepo.GetByID(ctx, link.GuardianID); err != nil {
+		return err
+	}
+	if _, err := s.studentRepo.GetByID(ctx, link.StudentID); err != nil {
+		return fmt.Errorf("%w: student not found", ErrInvalidGuardian)
+	}
+
+	link.Student = nil
+	link.Guardian = nil
+	return s.guardianRepo.SaveLink(ctx, link)
+}
+
+// UnlinkStudent removes the link between a guardian and a student
+func (s *GuardianServiceImpl) UnlinkStudent(ctx context.Context, guardianID, studentID uuid.UUID) error {
+	return s.guardianRepo.DeleteLink(ctx, guardianID, studentID)
+}
+
+// GetStudentGuardians retrieves the guardians of a student, primary contacts first
+func (s *GuardianServiceImpl) GetStudentGuardians(ctx context.Context, studentID uuid.UUID) ([]models.StudentGuardian, error) {
+	// Check if student exists
+	if _, err := s.studentRepo.GetByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return s.guardianRepo.GetGuardianLinks(ctx, studentID)
+}
+
+// GetChildren retrieves the students a guardian may see the records of
+func (s *GuardianServiceImpl) GetChildren(ctx context.Context, guardianID uuid.UUID) ([]models.GuardianChild, error) {
+	links, err := s.guardianRepo.GetStudentLinks(ctx, guardianID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]models.GuardianChild, 0, len(links))
+	for _, link := range links {
+		if link.RecordsWithheld || link.Student == nil {
+			continue
+		}
+		children = append(children, guardianChild(link))
+	}
+	return children, nil
+}
+
+// GetChild retrieves one of a guardian's children, refusing students they are not linked to or whose
+// records are withheld from them
+func (s *GuardianServiceImpl) GetChild(ctx context.Context, guardianID, studentID uuid.UUID) (*models.GuardianChild, error) {
+	link, err := s.guardianRepo.FindLink(ctx, guardianID, studentID)
+	if err != nil || link.RecordsWithheld || link.Student == nil {
+		return nil, fmt.Errorf("%w: you are not a guardian of this student", authz.ErrForbidden)
+	}
+	child := guardianChild(*link)
+	return &child, nil
+}
+
+// guardianChild maps a guardian's link to a student to the child shown in the portal
+func guardianChild(link models.StudentGuardian) models.GuardianChild {
+	return models.GuardianChild{
+		StudentID:    link.StudentID,
+		FirstName:    link.Student.FirstName,
+		LastName:     link.Student.LastName,
+		GradeLevel:   link.Student.GradeLevel,
+		Relationship: link.Relationship,
+		HasCustody:   link.HasCustody,
+		CanPickUp:    link.CanPickUp,
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"school-management-api/internal/authz"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// recordAccess decides which grade and attendance records the principal of a request may read and
+// change. Admins and counselors read everything, teachers the records of the courses they teach,
+// students their own records and guardians those of their children; only admins and the course's
+// teachers change them.
+type recordAccess struct {
+	teacherRepo  repositories.TeacherRepository
+	guardianRepo repositories.GuardianRepository
+}
+
+// scope returns the records the principal may read; a nil scope reads every record
+func (a recordAccess) scope(ctx context.Context) (*repositories.RecordScope, error) {
+	principal, err := authz.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case principal.ReadsAll():
+		return nil, nil
+	case principal.Role == authz.RoleTeacher && principal.TeacherID != nil:
+		courses, err := a.teacherRepo.GetCourses(ctx, *principal.TeacherID)
+		if err != nil {
+			return nil, err
+		}
+		courseIDs := make([]uuid.UUID, 0, len(courses))
+		for _, course := range courses {
+			courseIDs = append(courseIDs, course.ID)
+		}
+		return &repositories.RecordScope{CourseIDs: courseIDs}, nil
+	case principal.Role == authz.RoleStudent && principal.StudentID != nil:
+		return &repositories.RecordScope{StudentIDs: []uuid.UUID{*principal.StudentID}}, nil
+	case principal.Role == authz.RoleGuardian && principal.GuardianID != nil:
+		links, err := a.guardianRepo.GetStudentLinks(ctx, *principal.GuardianID)
+		if err != nil {
+			return nil, err
+		}
+		// Children whose records are withheld from the guardian are left out
+		studentIDs := make([]uuid.UUID, 0, len(links))
+		for _, link := range links {
+			if !link.RecordsWithheld {
+				studentIDs = append(studentIDs, link.StudentID)
+			}
+		}
+		return &repositories.RecordScope{StudentIDs: studentIDs}, nil
+	}
+	return nil, fmt.Errorf("%w: your login is not linked to a teacher, student or guardian", authz.ErrForbidden)
+}
+
+// studentScope returns the records of a student the principal may read, refusing principals who may
+// read none of them
+func (a recordAccess) studentScope(ctx context.Context, studentID uuid.UUID) (*repositories.RecordScope, error) {
+	scope, err := a.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.AllowsStudent(studentID) {
+		return nil, fmt.Errorf("%w: you cannot see this student's records", authz.ErrForbidden)
+	}
+	return scope, nil
+}
+
+// courseScope returns the records of a course the principal may read, refusing teachers who do not
+// teach it
+func (a recordAccess) courseScope(ctx context.Context, courseID uuid.UUID) (*repositories.RecordScope, error) {
+	scope, err := a.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.AllowsCourse(courseID) {
+		return nil, fmt.Errorf("%w: you do not teach this course", authz.ErrForbidden)
+	}
+	return scope, nil
+}
+
+// canRead checks that the principal may read the record of a student in a course
+func (a recordAccess) canRead(ctx context.Context, studentID, courseID uuid.UUID) error {
+	scope, err := a.scope(ctx)
+	if err != nil {
+		return err
+	}
+	if !scope.Allows(studentID, courseID) {
+		return fmt.Errorf("%w: you cannot see this record", authz.ErrForbidden)
+	}
+	return nil
+}
+
+// canReadTranscript checks that the principal may read every record of a student, as summaries over
+// all of their courses need
+func (a recordAccess) canReadTranscript(ctx context.Context, studentID uuid.UUID) error {
+	scope, err := a.studentScope(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if scope != nil && scope.CourseIDs != nil {
+		return fmt.Errorf("%w: you can only see the records of the courses you teach", authz.ErrForbidden)
+	}
+	return nil
+}
+
+// canReadCourse checks that the principal may read reports covering a whole course's roster, which
+// only staff who read everything and the course's teachers may
+func (a recordAccess) canReadCourse(ctx context.Context, courseID uuid.UUID) error {
+	scope, err := a.courseScope(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if scope != nil && scope.StudentIDs != nil {
+		return fmt.Errorf("%w: only the course's teachers can see its reports", authz.ErrForbidden)
+	}
+	return nil
+}
+
+// canReadTeacher checks that the principal may read the sessions a teacher teaches, which teachers
+// may only for themselves
+func (a recordAccess) canReadTeacher(ctx context.Context, teacherID uuid.UUID) error {
+	principal, err := authz.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.ReadsAll() || (principal.TeacherID != nil && *principal.TeacherID == teacherID) {
+		return nil
+	}
+	return fmt.Errorf("%w: you can only see your own sessions", authz.ErrForbidden)
+}
+
+// canEnroll checks that the principal may enroll a student in sections and drop them, which admins, the
+// student themselves and their guardians may
+func (a recordAccess) canEnroll(ctx context.Context, studentID uuid.UUID) error {
+	principal, err := authz.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.IsAdmin() {
+		return nil
+	}
+	if principal.Role == authz.RoleStudent || principal.Role == authz.RoleGuardian {
+		scope, err := a.scope(ctx)
+		if err != nil {
+			return err
+		}
+		if scope.AllowsStudent(studentID) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: you cannot change this student's enrollment", authz.ErrForbidden)
+}
+
+// canChange checks that the principal may record, change or delete grades and attendance in a course
+func (a recordAccess) canChange(ctx context.Context, courseID uuid.UUID) error {
+	principal, err := authz.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.IsAdmin() {
+		return nil
+	}
+	if principal.Role == authz.RoleTeacher && principal.TeacherID != nil {
+		scope, err := a.scope(ctx)
+		if err != nil {
+			return err
+		}
+		if scope.AllowsCourse(courseID) {
+			return nil
+		}
+		return fmt.Errorf("%w: you do not teach this course", authz.ErrForbidden)
+	}
+	return fmt.Errorf("%w: only the course's teachers can change its records", authz.ErrForbidden)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrRequisitesNotMet is returned when a student does not satisfy a course's prerequisites or corequisites
+var ErrRequisitesNotMet = errors.New("course requisites not met")
+
+// RequisiteService defines methods for course prerequisites and corequisites
+type RequisiteService interface {
+	GetRules(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteGroup, error)
+	SetRules(ctx context.Context, courseID uuid.UUID, groups []models.RequisiteGroup) error
+	CheckEnrollment(ctx context.Context, studentID, courseID, termID uuid.UUID) ([]models.UnmetRequisite, error)
+	GetOverrides(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteOverride, error)
+}
+
+// RequisiteServiceImpl implements the RequisiteService interface
+type RequisiteServiceImpl struct {
+	requisiteRepo repositories.RequisiteRepository
+	courseRepo    repositories.CourseRepository
+	studentRepo   repositories.StudentRepository
+	gradeRepo     repositories.GradeRepository
+	scaleService  GradingScaleService
+}
+
+// NewRequisiteService creates a new instance of RequisiteServiceImpl
+func NewRequisiteService(
+	requisiteRepo repositories.RequisiteRepository,
+	courseRepo repositories.CourseRepository,
+	studentRepo repositories.StudentRepository,
+	gradeRepo repositories.GradeRepository,
+	scaleService GradingScaleService,
+) RequisiteService {
+	return &RequisiteServiceImpl{
+		requisiteRepo: requisiteRepo,
+		courseRepo:    courseRepo,
+		studentRepo:   studentRepo,
+		gradeRepo:     gradeRepo,
+		scaleService:  scaleService,
+	}
+}
+
+// GetRules gets the requisite rule tree of a course
+func (s *RequisiteServiceImpl) GetRules(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteGroup, error) {
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	return s.requisiteRepo.GetGroups(ctx, courseID)
+}
+
+// SetRules replaces the requisite rule tree of a course
+func (s *RequisiteServiceImpl) SetRules(ctx context.Context, courseID uuid.UUID, groups []models.RequisiteGroup) error {
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return err
+	}
+
+	for i := range groups {
+		groups[i].ID = uuid.Nil
+		groups[i].CourseID = courseID
+		if err := groups[i].Validate(); err != nil {
+			return err
+		}
+
+		for j := range groups[i].Options {
+			option := &groups[i].Options[j]
+			option.ID = uuid.Nil
+			option.RequiredCourse = nil
+
+			// Check if the required course exists and the minimum grade is on its scale
+			if _, err := s.courseRepo.GetByID(ctx, option.RequiredCourseID); err != nil {
+				return fmt.Errorf("required course %s not found", option.RequiredCourseID)
+			}
+			if option.MinGrade != "" {
+				scale, err := s.scaleService.ResolveCourseScale(ctx, option.RequiredCourseID, "")
+				if err != nil {
+					return err
+				}
+				if scale.BandForLetter(option.MinGrade) == nil {
+					return fmt.Errorf("grade %q is not on the grading scale %q", option.MinGrade, scale.Name)
+				}
+			}
+		}
+	}
+
+	if err := s.checkCycles(ctx, courseID, groups); err != nil {
+		return err
+	}
+
+	return s.requisiteRepo.ReplaceGroups(ctx, courseID, groups)
+}
+
+// checkCycles rejects prerequisite rules that would make a course transitively require itself
+func (s *RequisiteServiceImpl) checkCycles(ctx context.Context, courseID uuid.UUID, groups []models.RequisiteGroup) error {
+	existing, err := s.requisiteRepo.GetAllGroups(ctx, models.RequisitePrerequisite)
+	if err != nil {
+		return err
+	}
+
+	// Build the prerequisite graph with this course's new rules in place of its old ones
+	requires := make(map[uuid.UUID][]uuid.UUID)
+	for _, group := range existing {
+		if group.CourseID == courseID {
+			continue
+		}
+		for _, option := range group.Options {
+			requires[group.CourseID] = append(requires[group.CourseID], option.RequiredCourseID)
+		}
+	}
+	for _, group := range groups {
+		if group.Kind != models.RequisitePrerequisite {
+			continue
+		}
+		for _, option := range group.Options {
+			requires[courseID] = append(requires[courseID], option.RequiredCourseID)
+		}
+	}
+
+	visited := make(map[uuid.UUID]bool)
+	var reaches func(from uuid.UUID) bool
+	reaches = func(from uuid.UUID) bool {
+		for _, next := range requires[from] {
+			if next == courseID {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				if reaches(next) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if reaches(courseID) {
+		return errors.New("prerequisite rules would create a cycle")
+	}
+	return nil
+}
+
+// CheckEnrollment returns the requisite groups a student does not satisfy for enrolling in a course in a term.
+// A prerequisite option is met by a grade in the required course at or above its minimum; a corequisite
+// option is also met by being enrolled in the required course in the same term.
+func (s *RequisiteServiceImpl) CheckEnrollment(ctx context.Context, studentID, courseID, termID uuid.UUID) ([]models.UnmetRequisite, error) {
+	groups, err := s.requisiteRepo.GetGroups(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	grades, err := s.gradeRepo.FindByStudent(studentID, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	gradesByCourse := make(map[uuid.UUID][]models.Grade)
+	for _, grade := range grades {
+		gradesByCourse[grade.CourseID] = append(gradesByCourse[grade.CourseID], grade)
+	}
+
+	concurrent, err := s.studentRepo.GetCourses(ctx, studentID, &termID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[uuid.UUID]bool)
+	for _, course := range concurrent {
+		enrolled[course.ID] = true
+	}
+
+	var unmet []models.UnmetRequisite
+	for _, group := range groups {
+		satisfied := false
+		descriptions := make([]string, 0, len(group.Options))
+		for _, option := range group.Options {
+			if group.Kind == models.RequisiteCorequisite && enrolled[option.RequiredCourseID] {
+				satisfied = true
+				break
+			}
+			met, err := s.meetsMinimum(ctx, option, gradesByCourse[option.RequiredCourseID])
+			if err != nil {
+				return nil, err
+			}
+			if met {
+				satisfied = true
+				break
+			}
+			descriptions = append(descriptions, describeOption(option))
+		}
+
+		if !satisfied {
+			unmet = append(unmet, models.UnmetRequisite{
+				GroupID: group.ID,
+				Kind:    group.Kind,
+				Options: descriptions,
+			})
+		}
+	}
+
+	return unmet, nil
+}
+
+// meetsMinimum reports whether any of the grades reaches the option's minimum on the scale that applied to it
+func (s *RequisiteServiceImpl) meetsMinimum(ctx context.Context, option models.RequisiteOption, grades []models.Grade) (bool, error) {
+	for _, grade := range grades {
+		scale, err := s.scaleService.ResolveCourseScale(ctx, grade.CourseID, grade.Term)
+		if err != nil {
+			return false, err
+		}
+
+		if option.MinGrade == "" {
+			if band := scale.BandForScore(grade.Score); band != nil && band.Passing {
+				return true, nil
+			}
+			continue
+		}
+		if band := scale.BandForLetter(option.MinGrade); band != nil && grade.Score >= band.MinScore {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// describeOption formats an option for error messages, e.g. "MATH101 (min C)"
+func describeOption(option models.RequisiteOption) string {
+	name := option.RequiredCourseID.String()
+	if option.RequiredCourse != nil {
+		name = option.RequiredCourse.Code
+	}
+	if option.MinGrade != "" {
+		return fmt.Sprintf("%s (min %s)", name, option.MinGrade)
+	}
+	return name
+}
+
+// GetOverrides gets the requisite overrides granted for a course
+func (s *RequisiteServiceImpl) GetOverrides(ctx context.Context, courseID uuid.UUID) ([]models.RequisiteOverride, error) {
+	return s.requisiteRepo.GetOverrides(ctx, courseID)
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrRoomDoubleBooked is returned when a booking overlaps another booking or a class in the same room
+var ErrRoomDoubleBooked = errors.New("room is already booked")
+
+// RoomService defines the interface for room, booking and utilisation service
+type RoomService interface {
+	CreateRoom(ctx context.Context, room *models.Room) error
+	GetRoomByID(ctx context.Context, id uuid.UUID) (*models.Room, error)
+	GetRooms(ctx context.Context, filter repositories.RoomFilter) ([]models.Room, error)
+	UpdateRoom(ctx context.Context, room *models.Room) error
+	DeleteRoom(ctx context.Context, id uuid.UUID) error
+	CreateBooking(ctx context.Context, booking *models.RoomBooking) error
+	GetBookingByID(ctx context.Context, id uuid.UUID) (*models.RoomBooking, error)
+	GetBookings(ctx context.Context, roomID uuid.UUID, from, to *time.Time) ([]models.RoomBooking, error)
+	CancelBooking(ctx context.Context, id uuid.UUID) error
+	GetUtilisation(ctx context.Context, term string) ([]models.RoomUtilisation, error)
+}
+
+// RoomServiceImpl implements the RoomService interface
+type RoomServiceImpl struct {
+	roomRepo    repositories.RoomRepository
+	sectionRepo repositories.SectionRepository
+	termService TermService
+}
+
+// NewRoomService creates a new instance of RoomServiceImpl
+func NewRoomService(
+	roomRepo repositories.RoomRepository,
+	sectionRepo repositories.SectionRepository,
+	termService TermService,
+) RoomService {
+	return &RoomServiceImpl{
+		roomRepo:    roomRepo,
+		sectionRepo: sectionRepo,
+		termService: termService,
+	}
+}
+
+// CreateRoom creates a new room, a classroom unless another type is given
+func (s *RoomServiceImpl) CreateRoom(ctx context.Context, room *models.Room) error {
+	if room.Type == "" {
+		room.Type = models.RoomClassroom
+	}
+	if err := room.Validate(); err != nil {
+		return err
+	}
+	return s.roomRepo.Create(ctx, room)
+}
+
+// GetRoomByID retrieves a room by ID
+func (s *RoomServiceImpl) GetRoomByID(ctx context.Context, id uuid.UUID) (*models.Room, error) {
+	return s.roomRepo.GetByID(ctx, id)
+}
+
+// GetRooms retrieves the rooms matching a filter
+func (s *RoomServiceImpl) GetRooms(ctx context.Context, filter repositories.RoomFilter) ([]models.Room, error) {
+	return s.roomRepo.GetAll(ctx, filter)
+}
+
+// UpdateRoom updates a room
+func (s *RoomServiceImpl) UpdateRoom(ctx context.Context, room *models.Room) error {
+	// Check if room exists
+	existingRoom, err := s.roomRepo.GetByID(ctx, room.ID)
+	if err != nil {
+		return err
+	}
+
+	if room.Type == "" {
+		room.Type = existingRoom.Type
+	}
+	if err := room.Validate(); err != nil {
+		return err
+	}
+
+	room.CreatedAt = existingRoom.CreatedAt
+	return s.roomRepo.Update(ctx, room)
+}
+
+// DeleteRoom deletes a room that no section is held in, together with its bookings
+func (s *RoomServiceImpl) DeleteRoom(ctx context.Context, id uuid.UUID) error {
+	// Check if room exists
+	if _, err := s.roomRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	inUse, err := s.roomRepo.IsInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("cannot delete a room that sections are still held in")
+	}
+	return s.roomRepo.Delete(ctx, id)
+}
+
+// CreateBooking books a room for an event unless it clashes with another booking or a class held there
+func (s *RoomServiceImpl) CreateBooking(ctx context.Context, booking *models.RoomBooking) error {
+	booking.Room = nil
+	if err := booking.Validate(); err != nil {
+		return err
+	}
+
+	// Check if room exists
+	room, err := s.roomRepo.GetByID(ctx, booking.RoomID)
+	if err != nil {
+		return errors.New("room not found")
+	}
+
+	// Classes only meet on dates inside their term
+	terms, err := s.termService.GetAllTerms(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range terms {
+		if !terms[i].Contains(booking.Date) {
+			continue
+		}
+		sections, err := s.sectionRepo.FindByRoom(ctx, room.ID, terms[i].ID)
+		if err != nil {
+			return err
+		}
+		for j := range sections {
+			section := &sections[j]
+			for k := range section.Meetings {
+				meeting := &section.Meetings[k]
+				roomID := section.RoomFor(meeting)
+				if roomID == nil || *roomID != room.ID || !booking.Overlaps(meeting) {
+					continue
+				}
+				name := section.Code
+				if section.Course != nil {
+					name = section.Course.Code + "-" + section.Code
+				}
+				return fmt.Errorf("%w: %s meets in %s from %s to %s", ErrRoomDoubleBooked, name, room.Name, meeting.StartTime, meeting.EndTime)
+			}
+		}
+	}
+
+	conflict, err := s.roomRepo.CreateBooking(ctx, booking)
+	if err != nil {
+		return err
+	}
+	if conflict != nil {
+		return fmt.Errorf("%w: %q holds %s from %s to %s", ErrRoomDoubleBooked, conflict.Title, room.Name, conflict.StartTime, conflict.EndTime)
+	}
+	return nil
+}
+
+// GetBookingByID retrieves a room booking by ID
+func (s *RoomServiceImpl) GetBookingByID(ctx context.Context, id uuid.UUID) (*models.RoomBooking, error) {
+	return s.roomRepo.GetBookingByID(ctx, id)
+}
+
+// GetBookings retrieves a room's bookings, optionally within a date range
+func (s *RoomServiceImpl) GetBookings(ctx context.Context, roomID uuid.UUID, from, to *time.Time) ([]models.RoomBooking, error) {
+	// Check if room exists
+	if _, err := s.roomRepo.GetByID(ctx, roomID); err != nil {
+		return nil, err
+	}
+
+	return s.roomRepo.GetBookings(ctx, roomID, from, to)
+}
+
+// CancelBooking cancels a room booking
+func (s *RoomServiceImpl) CancelBooking(ctx context.Context, id uuid.UUID) error {
+	// Check if booking exists
+	if _, err := s.roomRepo.GetBookingByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.roomRepo.DeleteBooking(ctx, id)
+}
+
+// GetUtilisation reports how much each room is used in a term, the current term when none is given.
+// Time utilisation is measured against the default school week used by the timetable generator.
+func (s *RoomServiceImpl) GetUtilisation(ctx context.Context, term string) ([]models.RoomUtilisation, error) {
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := s.roomRepo.GetAll(ctx, repositories.RoomFilter{})
+	if err != nil {
+		return nil, err
+	}
+	sections, err := s.sectionRepo.GetAll(ctx, nil, &t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	week := models.ScheduleOptions{}
+	week.ApplyDefaults()
+	dayStart, _ := models.ParseClock(week.DayStart)
+	dayEnd, _ := models.ParseClock(week.DayEnd)
+	available := len(week.Days) * (dayEnd - dayStart)
+
+	report := make([]models.RoomUtilisation, len(rooms))
+	index := make(map[uuid.UUID]int, len(rooms))
+	for i, room := range rooms {
+		index[room.ID] = i
+		report[i] = models.RoomUtilisation{
+			RoomID:           room.ID,
+			Name:             room.Name,
+			Building:         room.Building,
+			Type:             room.Type,
+			Capacity:         room.Capacity,
+			AvailableMinutes: available,
+		}
+	}
+
+	// Seat utilisation weighs each class by its length: enrolled seat-minutes over offered seat-minutes
+	seatMinutes := make([]int, len(rooms))
+	for i := range sections {
+		section := &sections[i]
+		seats, err := s.sectionRepo.GetSeatCount(ctx, section.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		counted := make(map[int]bool)
+		for j := range section.Meetings {
+			meeting := &section.Meetings[j]
+			roomID := section.RoomFor(meeting)
+			if roomID == nil {
+				continue
+			}
+			r, ok := index[*roomID]
+			if !ok {
+				continue
+			}
+			start, errStart := models.ParseClock(meeting.StartTime)
+			end, errEnd := models.ParseClock(meeting.EndTime)
+			if errStart != nil || errEnd != nil {
+				continue
+			}
+
+			if !counted[r] {
+				counted[r] = true
+				report[r].Sections++
+			}
+			report[r].WeeklyMinutes += end - start
+			seatMinutes[r] += seats.Enrolled * (end - start)
+		}
+	}
+
+	for i := range report {
+		if available > 0 {
+			report[i].TimeUtilisation = percentage(report[i].WeeklyMinutes, available)
+		}
+		if offered := report[i].Capacity * report[i].WeeklyMinutes; offered > 0 {
+			report[i].SeatUtilisation = percentage(seatMinutes[i], offered)
+		}
+
+		bookings, err := s.roomRepo.GetBookings(ctx, report[i].RoomID, &t.StartDate, &t.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		report[i].Bookings = len(bookings)
+		for j := range bookings {
+			report[i].BookedMinutes += bookings[j].Minutes()
+		}
+	}
+
+	return report, nil
+}
+
+// percentage returns part as a percentage of whole, rounded to one decimal place
+func percentage(part, whole int) float64 {
+	return math.Round(float64(part)/float64(whole)*1000) / 10
+}
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+	"school-management-api/internal/scheduler"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleService defines methods for automatic timetable generation and its inputs
+type ScheduleService interface {
+	StartRun(ctx context.Context, term string, options models.ScheduleOptions, requestedBy uuid.UUID) (*models.ScheduleRun, error)
+	GetRun(ctx context.Context, id uuid.UUID) (*models.ScheduleRun, error)
+	GetRuns(ctx context.Context, term string) ([]models.ScheduleRun, error)
+	PublishRun(ctx context.Context, id uuid.UUID) (*models.ScheduleRun, error)
+	RecoverInterruptedRuns(ctx context.Context) error
+	GetAvailability(ctx context.Context, teacherID uuid.UUID) ([]models.TeacherAvailability, error)
+	SetAvailability(ctx context.Context, teacherID uuid.UUID, windows []models.TeacherAvailability) error
+	CreateCourseRequest(ctx context.Context, studentID, courseID uuid.UUID, term string) (*models.CourseRequest, error)
+	GetCourseRequests(ctx context.Context, studentID uuid.UUID, term string) ([]models.CourseRequest, error)
+	DeleteCourseRequest(ctx context.Context, studentID, requestID uuid.UUID) error
+}
+
+// ScheduleServiceImpl implements the ScheduleService interface
+type ScheduleServiceImpl struct {
+	scheduleRepo repositories.ScheduleRepository
+	sectionRepo  repositories.SectionRepository
+	roomRepo     repositories.RoomRepository
+	courseRepo   repositories.CourseRepository
+	studentRepo  repositories.StudentRepository
+	teacherRepo  repositories.TeacherRepository
+	termService  TermService
+}
+
+// NewScheduleService creates a new instance of ScheduleServiceImpl
+func NewScheduleService(
+	scheduleRepo repositories.ScheduleRepository,
+	sectionRepo repositories.SectionRepository,
+	roomRepo repositories.RoomRepository,
+	courseRepo repositories.CourseRepository,
+	studentRepo repositories.StudentRepository,
+	teacherRepo repositories.TeacherRepository,
+	termService TermService,
+) ScheduleService {
+	return &ScheduleServiceImpl{
+		scheduleRepo: scheduleRepo,
+		sectionRepo:  sectionRepo,
+		roomRepo:     roomRepo,
+		courseRepo:   courseRepo,
+		studentRepo:  studentRepo,
+		teacherRepo:  teacherRepo,
+		termService:  termService,
+	}
+}
+
+// StartRun queues a timetable run for a term, the current term when none is given, and solves it in the background
+func (s *ScheduleServiceImpl) StartRun(ctx context.Context, term string, options models.ScheduleOptions, requestedBy uuid.UUID) (*models.ScheduleRun, error) {
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	options.ApplyDefaults()
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Only one run per term at a time
+	run := &models.ScheduleRun{
+		TermID:      t.ID,
+		Status:      models.ScheduleRunPending,
+		Options:     options,
+		RequestedBy: requestedBy,
+	}
+	created, err := s.scheduleRepo.CreateRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, fmt.Errorf("a timetable run for term %s is already in progress", t.Name)
+	}
+
+	// The run outlives the request that started it and works on its own copy
+	queued := *run
+	go s.execute(&queued)
+
+	return run, nil
+}
+
+// execute solves a run and stores its draft assignments, or marks the run failed
+func (s *ScheduleServiceImpl) execute(run *models.ScheduleRun) {
+	ctx := context.Background()
+
+	// A panic would otherwise take the server down and leave the run blocking its term
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Timetable run %s panicked: %v\n%s", run.ID, r, debug.Stack())
+			s.failRun(ctx, run, fmt.Errorf("internal error: %v", r))
+		}
+	}()
+
+	startedAt := time.Now()
+	run.Status = models.ScheduleRunRunning
+	run.StartedAt = &startedAt
+	if err := s.scheduleRepo.UpdateRun(ctx, run); err != nil {
+		log.Printf("Failed to start timetable run %s: %v", run.ID, err)
+		return
+	}
+
+	assignments, err := s.solve(ctx, run)
+	if err != nil {
+		s.failRun(ctx, run, err)
+		return
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = models.ScheduleRunCompleted
+	if err := s.scheduleRepo.SaveResult(ctx, run, assignments); err != nil {
+		log.Printf("Failed to save timetable run %s: %v", run.ID, err)
+		s.failRun(ctx, run, err)
+	}
+}
+
+// failRun marks a run failed with the error that stopped it
+func (s *ScheduleServiceImpl) failRun(ctx context.Context, run *models.ScheduleRun, err error) {
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = models.ScheduleRunFailed
+	run.Error = err.Error()
+	if err := s.scheduleRepo.UpdateRun(ctx, run); err != nil {
+		log.Printf("Failed to record timetable run %s failure: %v", run.ID, err)
+	}
+}
+
+// solve loads the term's sections, teacher availability and course requests and runs the scheduler
+func (s *ScheduleServiceImpl) solve(ctx context.Context, run *models.ScheduleRun) ([]models.ScheduleAssignment, error) {
+	sections, err := s.sectionRepo.GetAll(ctx, nil, &run.TermID)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.scheduleRepo.GetAllAvailability(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.scheduleRepo.GetTermRequests(ctx, run.TermID)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := s.runRooms(ctx, run.Options.RoomIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Options were validated when the run was queued
+	options := run.Options
+	dayStart, _ := models.ParseClock(options.DayStart)
+	dayEnd, _ := models.ParseClock(options.DayEnd)
+	problem := scheduler.Problem{
+		Slots:        scheduler.WeeklySlots(options.Days, dayStart, dayEnd, options.PeriodMinutes, options.BreakMinutes),
+		Availability: make(map[uuid.UUID][]scheduler.Slot),
+	}
+	for _, room := range rooms {
+		problem.Rooms = append(problem.Rooms, scheduler.Room{ID: room.ID, Capacity: room.Capacity, Type: string(room.Type)})
+	}
+	for _, window := range windows {
+		slot, err := toSlot(window.Day, window.StartTime, window.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		problem.Availability[window.TeacherID] = append(problem.Availability[window.TeacherID], slot)
+	}
+	for _, request := range requests {
+		problem.Requests = append(problem.Requests, scheduler.Request{StudentID: request.StudentID, CourseID: request.CourseID})
+	}
+
+	byID := make(map[uuid.UUID]*models.Section, len(sections))
+	for i := range sections {
+		section := &sections[i]
+		byID[section.ID] = section
+
+		input := scheduler.Section{
+			ID:       section.ID,
+			CourseID: section.CourseID,
+			Capacity: section.Capacity,
+			RoomType: string(section.RoomType),
+			Meetings: options.MeetingsPerWeek,
+		}
+		if section.RoomID != nil {
+			input.Room = *section.RoomID
+		}
+		for _, teacher := range section.Teachers {
+			input.TeacherIDs = append(input.TeacherIDs, teacher.ID)
+		}
+		if !options.Reschedule {
+			for _, meeting := range section.Meetings {
+				slot, err := toSlot(meeting.Day, meeting.StartTime, meeting.EndTime)
+				if err != nil {
+					return nil, err
+				}
+				placement := scheduler.Placement{SectionID: section.ID, Slot: slot}
+				if meeting.RoomID != nil {
+					placement.Room = *meeting.RoomID
+				}
+				input.Fixed = append(input.Fixed, placement)
+			}
+		}
+		problem.Sections = append(problem.Sections, input)
+	}
+
+	result := scheduler.Solve(problem)
+
+	assignments := make([]models.ScheduleAssignment, 0, len(result.Placements))
+	for _, placement := range result.Placements {
+		assignment := models.ScheduleAssignment{
+			RunID:     run.ID,
+			SectionID: placement.SectionID,
+			Day:       placement.Slot.Day,
+			StartTime: models.FormatClock(placement.Slot.Start),
+			EndTime:   models.FormatClock(placement.Slot.End),
+		}
+		if placement.Room != uuid.Nil {
+			roomID := placement.Room
+			assignment.RoomID = &roomID
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	run.Unresolved = make([]string, 0, len(result.Unresolved))
+	for _, unresolved := range result.Unresolved {
+		run.Unresolved = append(run.Unresolved, s.describeUnresolved(ctx, unresolved, byID))
+	}
+	run.StudentConflicts = result.StudentConflicts
+	return assignments, nil
+}
+
+// runRooms loads the rooms a run may use: the listed ones, or every registered room
+func (s *ScheduleServiceImpl) runRooms(ctx context.Context, roomIDs []uuid.UUID) ([]models.Room, error) {
+	if len(roomIDs) == 0 {
+		return s.roomRepo.GetAll(ctx, repositories.RoomFilter{})
+	}
+
+	rooms := make([]models.Room, 0, len(roomIDs))
+	for _, id := range roomIDs {
+		room, err := s.roomRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("room %s not found", id)
+		}
+		rooms = append(rooms, *room)
+	}
+	return rooms, nil
+}
+
+// describeUnresolved prefixes an unresolved item with its course and section codes, e.g. "ALG1-02: ..."
+func (s *ScheduleServiceImpl) describeUnresolved(ctx context.Context, unresolved scheduler.Unresolved, sections map[uuid.UUID]*models.Section) string {
+	name := unresolved.CourseID.String()
+	if section, ok := sections[unresolved.SectionID]; ok {
+		name = section.Code
+		if section.Course != nil {
+			name = section.Course.Code + "-" + section.Code
+		}
+	} else if course, err := s.courseRepo.GetByID(ctx, unresolved.CourseID); err == nil {
+		name = course.Code
+	}
+	return fmt.Sprintf("%s: %s", name, unresolved.Reason)
+}
+
+// toSlot converts a day and HH:MM range into a scheduler slot
+func toSlot(day time.Weekday, startTime, endTime string) (scheduler.Slot, error) {
+	start, err := models.ParseClock(startTime)
+	if err != nil {
+		return scheduler.Slot{}, err
+	}
+	end, err := models.ParseClock(endTime)
+	if err != nil {
+		return scheduler.Slot{}, err
+	}
+	return scheduler.Slot{Day: day, Start: start, End: end}, nil
+}
+
+// GetRun retrieves a timetable run with its draft assignments
+func (s *ScheduleServiceImpl) GetRun(ctx context.Context, id uuid.UUID) (*models.ScheduleRun, error) {
+	return s.scheduleRepo.GetRunByID(ctx, id)
+}
+
+// GetRuns retrieves timetable runs, optionally limited to a term
+func (s *ScheduleServiceImpl) GetRuns(ctx context.Context, term string) ([]models.ScheduleRun, error) {
+	termID, err := resolveTermID(ctx, s.termService, term)
+	if err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetRuns(ctx, termID)
+}
+
+// PublishRun copies a completed run's draft assignments onto its sections' meetings
+func (s *ScheduleServiceImpl) PublishRun(ctx context.Context, id uuid.UUID) (*models.ScheduleRun, error) {
+	run, err := s.scheduleRepo.GetRunByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if run.Status != models.ScheduleRunCompleted {
+		return nil, fmt.Errorf("only completed runs can be published, this run is %s", run.Status)
+	}
+
+	if err := s.scheduleRepo.Publish(ctx, run, s.checkPublish); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// checkPublish checks the meetings a run gives its sections against the timetable as it is now, which
+// may have changed since the run was solved: the rooms must suit the sections and be free, and the
+// sections' teachers and students must be free too
+func (s *ScheduleServiceImpl) checkPublish(ctx context.Context, sections []models.Section) error {
+	proposed := make(map[uuid.UUID]*models.Section, len(sections))
+	for i := range sections {
+		proposed[sections[i].ID] = &sections[i]
+	}
+
+	for i := range sections {
+		section := &sections[i]
+		existing, err := s.sectionRepo.GetByID(ctx, section.ID)
+		if err != nil {
+			return err
+		}
+		name := section.Code
+		if existing.Course != nil {
+			name = existing.Course.Code + "-" + section.Code
+		}
+
+		if err := checkRooms(ctx, s.roomRepo, s.sectionRepo, section, proposed); err != nil {
+			return fmt.Errorf("section %s: %w", name, err)
+		}
+		for _, teacher := range existing.Teachers {
+			others, err := s.sectionRepo.FindByTeacher(ctx, teacher.ID, &section.TermID)
+			if err != nil {
+				return err
+			}
+			if err := checkConflicts(section, withProposed(others, proposed), false); err != nil {
+				return fmt.Errorf("section %s, teacher %s %s: %w", name, teacher.FirstName, teacher.LastName, err)
+			}
+		}
+		for _, student := range existing.Students {
+			others, err := s.sectionRepo.FindByStudent(ctx, student.ID, &section.TermID)
+			if err != nil {
+				return err
+			}
+			if err := checkConflicts(section, withProposed(others, proposed), false); err != nil {
+				return fmt.Errorf("section %s, student %s %s: %w", name, student.FirstName, student.LastName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RecoverInterruptedRuns marks runs left pending or running by a restart as failed
+func (s *ScheduleServiceImpl) RecoverInterruptedRuns(ctx context.Context) error {
+	runs, err := s.scheduleRepo.GetRuns(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for i := range runs {
+		run := &runs[i]
+		if run.Status != models.ScheduleRunPending && run.Status != models.ScheduleRunRunning {
+			continue
+		}
+		run.Status = models.ScheduleRunFailed
+		run.Error = "interrupted by a server restart"
+		if err := s.scheduleRepo.UpdateRun(ctx, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAvailability gets a teacher's weekly availability windows
+func (s *ScheduleServiceImpl) GetAvailability(ctx context.Context, teacherID uuid.UUID) ([]models.TeacherAvailability, error) {
+	// Check if teacher exists
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.GetAvailability(ctx, teacherID)
+}
+
+// SetAvailability replaces a teacher's weekly availability windows
+func (s *ScheduleServiceImpl) SetAvailability(ctx context.Context, teacherID uuid.UUID, windows []models.TeacherAvailability) error {
+	// Check if teacher exists
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return err
+	}
+
+	for i := range windows {
+		windows[i].ID = uuid.Nil
+		windows[i].TeacherID = teacherID
+		if err := windows[i].Validate(); err != nil {
+			return fmt.Errorf("window %d: %w", i+1, err)
+		}
+	}
+	return s.scheduleRepo.ReplaceAvailability(ctx, teacherID, windows)
+}
+
+// CreateCourseRequest records a student's request to take a course in a term, the current term when none is given
+func (s *ScheduleServiceImpl) CreateCourseRequest(ctx context.Context, studentID, courseID uuid.UUID, term string) (*models.CourseRequest, error) {
+	// Check if student exists
+	if _, err := s.studentRepo.GetByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, errors.New("course not found")
+	}
+
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &models.CourseRequest{
+		StudentID: studentID,
+		CourseID:  courseID,
+		TermID:    t.ID,
+	}
+	if err := s.scheduleRepo.CreateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetCourseRequests gets a student's course requests, optionally limited to a term
+func (s *ScheduleServiceImpl) GetCourseRequests(ctx context.Context, studentID uuid.UUID, term string) ([]models.CourseRequest, error) {
+	termID, err := resolveTermID(ctx, s.termService, term)
+	if err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetRequests(ctx, studentID, termID)
+}
+
+// DeleteCourseRequest withdraws one of a student's course requests
+func (s *ScheduleServiceImpl) DeleteCourseRequest(ctx context.Context, studentID, requestID uuid.UUID) error {
+	// Check if request exists and belongs to the student
+	request, err := s.scheduleRepo.GetRequestByID(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.StudentID != studentID {
+		return errors.New("course request not found")
+	}
+
+	return s.scheduleRepo.DeleteRequest(ctx, requestID)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyInCourse is returned when a student already sits in another section of a course in the term
+var ErrAlreadyInCourse = errors.New("student is already enrolled in this course")
+
+// seatCheck decides whether a student may take a seat in a section. It backs the EnrollmentCheck of
+// the section repository, so it runs with the section and the student locked: both when a student asks
+// for a seat and when a waitlisted student is promoted into one.
+type seatCheck struct {
+	sectionRepo      repositories.SectionRepository
+	requisiteService RequisiteService
+}
+
+// check refuses a student who sits in another section of the course or whose timetable the section
+// clashes with, and returns the course requisites the student does not meet
+func (c seatCheck) check(ctx context.Context, section *models.Section, studentID uuid.UUID) ([]models.UnmetRequisite, error) {
+	// A student sits in one section of a course per term
+	existing, err := c.sectionRepo.FindStudentSection(ctx, studentID, section.CourseID, section.TermID)
+	if err == nil && existing.ID != section.ID {
+		return nil, fmt.Errorf("%w, in section %s", ErrAlreadyInCourse, existing.Code)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Check the section fits the student's timetable
+	others, err := c.sectionRepo.FindByStudent(ctx, studentID, &section.TermID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConflicts(section, others, false); err != nil {
+		return nil, err
+	}
+
+	// Check prerequisites and corequisites
+	return c.requisiteService.CheckEnrollment(ctx, studentID, section.CourseID, section.TermID)
+}
+
+// promotion checks a waitlisted student before they are promoted into a seat, which the student may
+// have become unable to take since joining the waitlist. Requisites are met by an override granted
+// for the section. Refusals wrap repositories.ErrSeatRefused so the student is passed over.
+func (c seatCheck) promotion(ctx context.Context, section *models.Section, studentID uuid.UUID) error {
+	unmet, err := c.check(ctx, section, studentID)
+	if errors.Is(err, ErrAlreadyInCourse) || errors.Is(err, ErrScheduleConflict) {
+		return fmt.Errorf("%w: %w", repositories.ErrSeatRefused, err)
+	}
+	if err != nil || len(unmet) == 0 {
+		return err
+	}
+
+	overrides, err := c.requisiteService.GetOverrides(ctx, section.CourseID)
+	if err != nil {
+		return err
+	}
+	for _, override := range overrides {
+		if override.StudentID == studentID && override.SectionID == section.ID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %w: %s", repositories.ErrSeatRefused, ErrRequisitesNotMet, describeUnmet(unmet))
+}
+
+// describeUnmet formats unmet requisites for error messages
+func describeUnmet(unmet []models.UnmetRequisite) string {
+	descriptions := make([]string, len(unmet))
+	for i, requisite := range unmet {
+		descriptions[i] = requisite.String()
+	}
+	return strings.Join(descriptions, "; ")
+}
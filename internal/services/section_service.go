@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrScheduleConflict is returned when a section's meetings overlap another commitment
+var ErrScheduleConflict = errors.New("schedule conflict")
+
+// SectionService defines the interface for section service
+type SectionService interface {
+	CreateSection(ctx context.Context, section *models.Section) error
+	GetSectionByID(ctx context.Context, id uuid.UUID) (*models.Section, error)
+	GetSections(ctx context.Context, courseID *uuid.UUID, term string) ([]models.Section, error)
+	UpdateSection(ctx context.Context, section *models.Section) error
+	DeleteSection(ctx context.Context, id uuid.UUID) error
+	GetSeatCount(ctx context.Context, id uuid.UUID) (*models.SeatCount, error)
+	GetWaitlist(ctx context.Context, id uuid.UUID) ([]models.WaitlistEntry, error)
+}
+
+// SectionServiceImpl implements the SectionService interface
+type SectionServiceImpl struct {
+	sectionRepo repositories.SectionRepository
+	courseRepo  repositories.CourseRepository
+	roomRepo    repositories.RoomRepository
+	termService TermService
+	seats       seatCheck
+}
+
+// NewSectionService creates a new instance of SectionServiceImpl
+func NewSectionService(
+	sectionRepo repositories.SectionRepository,
+	courseRepo repositories.CourseRepository,
+	roomRepo repositories.RoomRepository,
+	termService TermService,
+	requisiteService RequisiteService,
+) SectionService {
+	return &SectionServiceImpl{
+		sectionRepo: sectionRepo,
+		courseRepo:  courseRepo,
+		roomRepo:    roomRepo,
+		termService: termService,
+		seats:       seatCheck{sectionRepo: sectionRepo, requisiteService: requisiteService},
+	}
+}
+
+// CreateSection creates a new section of a course, in the current term when none is given
+func (s *SectionServiceImpl) CreateSection(ctx context.Context, section *models.Section) error {
+	if err := section.Validate(); err != nil {
+		return err
+	}
+
+	// Check if course exists
+	if _, err := s.courseRepo.GetByID(ctx, section.CourseID); err != nil {
+		return errors.New("course not found")
+	}
+
+	// Check if term exists
+	if section.TermID == uuid.Nil {
+		term, err := s.termService.GetCurrentTerm(ctx)
+		if err != nil {
+			return err
+		}
+		section.TermID = term.ID
+	} else if _, err := s.termService.GetTermByID(ctx, section.TermID); err != nil {
+		return errors.New("term not found")
+	}
+
+	if err := checkRooms(ctx, s.roomRepo, s.sectionRepo, section, nil); err != nil {
+		return err
+	}
+
+	return s.sectionRepo.Create(ctx, section)
+}
+
+// GetSectionByID retrieves a section with its teachers and roster
+func (s *SectionServiceImpl) GetSectionByID(ctx context.Context, id uuid.UUID) (*models.Section, error) {
+	return s.sectionRepo.GetByID(ctx, id)
+}
+
+// GetSections retrieves sections, optionally limited to a course and a term
+func (s *SectionServiceImpl) GetSections(ctx context.Context, courseID *uuid.UUID, term string) ([]models.Section, error) {
+	termID, err := resolveTermID(ctx, s.termService, term)
+	if err != nil {
+		return nil, err
+	}
+	return s.sectionRepo.GetAll(ctx, courseID, termID)
+}
+
+// UpdateSection updates a section's code, room, capacity and meetings
+func (s *SectionServiceImpl) UpdateSection(ctx context.Context, section *models.Section) error {
+	// Check if section exists
+	existingSection, err := s.sectionRepo.GetByID(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := section.Validate(); err != nil {
+		return err
+	}
+
+	// A section cannot move to another course or term once it has a roster
+	section.CourseID = existingSection.CourseID
+	section.TermID = existingSection.TermID
+	section.CreatedAt = existingSection.CreatedAt
+
+	if err := checkRooms(ctx, s.roomRepo, s.sectionRepo, section, nil); err != nil {
+		return err
+	}
+
+	// New meeting times must still fit the schedules of the assigned teachers and enrolled students
+	for _, teacher := range existingSection.Teachers {
+		others, err := s.sectionRepo.FindByTeacher(ctx, teacher.ID, &section.TermID)
+		if err != nil {
+			return err
+		}
+		if err := checkConflicts(section, others, false); err != nil {
+			return fmt.Errorf("teacher %s %s: %w", teacher.FirstName, teacher.LastName, err)
+		}
+	}
+	for _, student := range existingSection.Students {
+		others, err := s.sectionRepo.FindByStudent(ctx, student.ID, &section.TermID)
+		if err != nil {
+			return err
+		}
+		if err := checkConflicts(section, others, false); err != nil {
+			return fmt.Errorf("student %s %s: %w", student.FirstName, student.LastName, err)
+		}
+	}
+
+	return s.sectionRepo.Update(ctx, section, s.seats.promotion)
+}
+
+// DeleteSection deletes a section that has no enrolled students
+func (s *SectionServiceImpl) DeleteSection(ctx context.Context, id uuid.UUID) error {
+	// Check if section exists
+	section, err := s.sectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(section.Students) > 0 {
+		return errors.New("cannot delete a section that still has enrolled students")
+	}
+	return s.sectionRepo.Delete(ctx, id)
+}
+
+// GetSeatCount reports the capacity, enrolled, available and waitlisted seats of a section
+func (s *SectionServiceImpl) GetSeatCount(ctx context.Context, id uuid.UUID) (*models.SeatCount, error) {
+	return s.sectionRepo.GetSeatCount(ctx, id)
+}
+
+// GetWaitlist gets a section's waitlist in the order seats will be offered
+func (s *SectionServiceImpl) GetWaitlist(ctx context.Context, id uuid.UUID) ([]models.WaitlistEntry, error) {
+	// Check if section exists
+	if _, err := s.sectionRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.sectionRepo.GetWaitlist(ctx, id)
+}
+
+// checkRooms checks that the section's rooms exist, suit the section and are not used by another
+// section of the term at the same time. Sections about to be given new meetings are passed as proposed,
+// and are checked against with those meetings.
+func checkRooms(
+	ctx context.Context,
+	roomRepo repositories.RoomRepository,
+	sectionRepo repositories.SectionRepository,
+	section *models.Section,
+	proposed map[uuid.UUID]*models.Section,
+) error {
+	// Rooms are referenced by ID only and never saved through a section
+	section.Room = nil
+	roomIDs := make(map[uuid.UUID]bool)
+	if section.RoomID != nil {
+		roomIDs[*section.RoomID] = true
+	}
+	for i := range section.Meetings {
+		section.Meetings[i].Room = nil
+		if section.Meetings[i].RoomID != nil {
+			roomIDs[*section.Meetings[i].RoomID] = true
+		}
+	}
+
+	for roomID := range roomIDs {
+		room, err := roomRepo.GetByID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("room %s not found", roomID)
+		}
+		if section.RoomType != "" && room.Type != section.RoomType {
+			return fmt.Errorf("room %s is a %s, the section needs a %s", room.Name, room.Type, section.RoomType)
+		}
+		// Rooms carried over from free-text names have no recorded capacity yet
+		if room.Capacity > 0 && section.Capacity > room.Capacity {
+			return fmt.Errorf("room %s holds %d students, fewer than the section's capacity of %d", room.Name, room.Capacity, section.Capacity)
+		}
+
+		others, err := sectionRepo.FindByRoom(ctx, roomID, section.TermID)
+		if err != nil {
+			return err
+		}
+		// Proposed sections may move into the room, so all of them are checked against
+		others = withProposed(others, proposed)
+		for id, other := range proposed {
+			if !containsSection(others, id) {
+				others = append(others, *other)
+			}
+		}
+		if err := checkConflicts(section, others, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConflicts returns ErrScheduleConflict when the section overlaps one of the other sections.
+// With sameRoom only meetings held in the same room clash.
+func checkConflicts(section *models.Section, others []models.Section, sameRoom bool) error {
+	for i := range others {
+		if others[i].ID == section.ID {
+			continue
+		}
+		if conflict := section.ConflictWith(&others[i], sameRoom); conflict != nil {
+			return fmt.Errorf("%w with %s", ErrScheduleConflict, conflict)
+		}
+	}
+	return nil
+}
+
+// withProposed returns the sections with those about to be given new meetings swapped for their
+// proposed versions
+func withProposed(sections []models.Section, proposed map[uuid.UUID]*models.Section) []models.Section {
+	if len(proposed) == 0 {
+		return sections
+	}
+	swapped := make([]models.Section, len(sections))
+	for i := range sections {
+		if section, ok := proposed[sections[i].ID]; ok {
+			swapped[i] = *section
+		} else {
+			swapped[i] = sections[i]
+		}
+	}
+	return swapped
+}
+
+// containsSection reports whether a section is among the sections
+func containsSection(sections []models.Section, id uuid.UUID) bool {
+	for i := range sections {
+		if sections[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveTermID turns an optional term name filter into a term ID
+func resolveTermID(ctx context.Context, termService TermService, term string) (*uuid.UUID, error) {
+	if term == "" {
+		return nil, nil
+	}
+	t, err := termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	return &t.ID, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"school-management-api/internal/models"
 	"school-management-api/internal/repositories"
@@ -12,40 +13,105 @@ import (
 
 // StudentService defines the interface for student service
 type StudentService interface {
-	CreateStudent(ctx context.Context, student *models.Student) error
+	CreateStudent(ctx context.Context, student *models.Student) (*models.ProvisionedAccount, error)
+	ProvisionAccount(ctx context.Context, id uuid.UUID, req models.AccountRequest) (*models.ProvisionedAccount, error)
 	GetStudentByID(ctx context.Context, id uuid.UUID) (*models.StudentResponse, error)
 	GetAllStudents(ctx context.Context, page, pageSize int) ([]models.StudentResponse, int64, error)
 	UpdateStudent(ctx context.Context, student *models.Student) error
 	DeleteStudent(ctx context.Context, id uuid.UUID) error
-	EnrollCourse(ctx context.Context, studentID, courseID uuid.UUID) error
-	DropCourse(ctx context.Context, studentID, courseID uuid.UUID) error
-	GetStudentCourses(ctx context.Context, studentID uuid.UUID) ([]models.Course, error)
+	EnrollSection(ctx context.Context, studentID, sectionID uuid.UUID, override *models.RequisiteOverride) (*models.EnrollmentResult, error)
+	DropSection(ctx context.Context, studentID, sectionID uuid.UUID) error
+	GetStudentSections(ctx context.Context, studentID uuid.UUID, term string) ([]models.Section, error)
+	GetStudentWaitlist(ctx context.Context, studentID uuid.UUID) ([]models.WaitlistEntry, error)
+	GetStudentCourses(ctx context.Context, studentID uuid.UUID, term string) ([]models.Course, error)
 }
 
 // StudentServiceImpl implements the StudentService interface
 type StudentServiceImpl struct {
-	studentRepo repositories.StudentRepository
-	courseRepo  repositories.CourseRepository
+	studentRepo      repositories.StudentRepository
+	sectionRepo      repositories.SectionRepository
+	termService      TermService
+	requisiteService RequisiteService
+	userService      UserService
+	seats            seatCheck
+	access           recordAccess
 }
 
 // NewStudentService creates a new instance of StudentServiceImpl
-func NewStudentService(studentRepo repositories.StudentRepository, courseRepo repositories.CourseRepository) StudentService {
+func NewStudentService(
+	studentRepo repositories.StudentRepository,
+	sectionRepo repositories.SectionRepository,
+	termService TermService,
+	requisiteService RequisiteService,
+	userService UserService,
+	guardianRepo repositories.GuardianRepository,
+) StudentService {
 	return &StudentServiceImpl{
-		studentRepo: studentRepo,
-		courseRepo:  courseRepo,
+		studentRepo:      studentRepo,
+		sectionRepo:      sectionRepo,
+		termService:      termService,
+		requisiteService: requisiteService,
+		userService:      userService,
+		seats:            seatCheck{sectionRepo: sectionRepo, requisiteService: requisiteService},
+		access:           recordAccess{guardianRepo: guardianRepo},
 	}
 }
 
-// CreateStudent creates a new student
-func (s *StudentServiceImpl) CreateStudent(ctx context.Context, student *models.Student) error {
+// CreateStudent creates a new student together with their login
+func (s *StudentServiceImpl) CreateStudent(ctx context.Context, student *models.Student) (*models.ProvisionedAccount, error) {
 	// Check if student with same email already exists
 	existingStudent, err := s.studentRepo.FindByEmail(ctx, student.Email)
 	if err == nil && existingStudent.ID != uuid.Nil {
-		return errors.New("student with this email already exists")
+		return nil, errors.New("student with this email already exists")
+	}
+
+	// A login is provisioned with the student unless asked not to
+	var req models.AccountRequest
+	if student.Account != nil {
+		req = *student.Account
+	}
+	if !req.Skip {
+		if err := s.userService.CheckAccount(ctx, studentAccount(student), req); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create student
-	return s.studentRepo.Create(ctx, student)
+	if err := s.studentRepo.Create(ctx, student); err != nil {
+		return nil, err
+	}
+	if req.Skip {
+		return nil, nil
+	}
+	account, err := s.userService.ProvisionAccount(ctx, studentAccount(student), req)
+	if err != nil {
+		return nil, fmt.Errorf("student created, but their login could not be provisioned: %w", err)
+	}
+	return account, nil
+}
+
+// ProvisionAccount provisions the login of an existing student
+func (s *StudentServiceImpl) ProvisionAccount(ctx context.Context, id uuid.UUID, req models.AccountRequest) (*models.ProvisionedAccount, error) {
+	student, err := s.studentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.userService.ProvisionAccount(ctx, studentAccount(student), req)
+}
+
+// studentAccount returns the login of a student before it is provisioned
+func studentAccount(student *models.Student) *models.User {
+	account := &models.User{
+		Email:     student.Email,
+		FirstName: student.FirstName,
+		LastName:  student.LastName,
+		Role:      "Student",
+	}
+	if student.ID != uuid.Nil {
+		id := student.ID
+		account.StudentID = &id
+	}
+	return account
 }
 
 // GetStudentByID retrieves a student by their ID
@@ -67,6 +133,7 @@ func (s *StudentServiceImpl) GetStudentByID(ctx context.Context, id uuid.UUID) (
 		Phone:          student.Phone,
 		EnrollmentDate: student.EnrollmentDate,
 		GradeLevel:     student.GradeLevel,
+		CounselorID:    student.CounselorID,
 		Courses:        student.Courses,
 	}
 
@@ -94,6 +161,7 @@ func (s *StudentServiceImpl) GetAllStudents(ctx context.Context, page, pageSize
 			Phone:          student.Phone,
 			EnrollmentDate: student.EnrollmentDate,
 			GradeLevel:     student.GradeLevel,
+			CounselorID:    student.CounselorID,
 		})
 	}
 
@@ -130,35 +198,109 @@ func (s *StudentServiceImpl) DeleteStudent(ctx context.Context, id uuid.UUID) er
 	return s.studentRepo.Delete(ctx, id)
 }
 
-// EnrollCourse enrolls a student in a course
-func (s *StudentServiceImpl) EnrollCourse(ctx context.Context, studentID, courseID uuid.UUID) error {
+// EnrollSection enrolls a student in a section of a course, or waitlists them when it is full.
+// Unmet prerequisites or corequisites reject the request unless an override is given, which is recorded.
+func (s *StudentServiceImpl) EnrollSection(ctx context.Context, studentID, sectionID uuid.UUID, override *models.RequisiteOverride) (*models.EnrollmentResult, error) {
+	if err := s.access.canEnroll(ctx, studentID); err != nil {
+		return nil, err
+	}
+
 	// Check if student exists
 	_, err := s.studentRepo.GetByID(ctx, studentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Check if course exists
-	_, err = s.courseRepo.GetByID(ctx, courseID)
+	// Check if section exists
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
 	if err != nil {
+		return nil, errors.New("section not found")
+	}
+
+	// The student is checked with the section locked, so its roster cannot change meanwhile. The
+	// override is recorded with the enrollment, and only when it was actually needed.
+	return s.sectionRepo.Enroll(ctx, section.ID, studentID, func(ctx context.Context, section *models.Section, studentID uuid.UUID) error {
+		unmet, err := s.seats.check(ctx, section, studentID)
+		if err != nil {
+			return err
+		}
+		if override != nil {
+			override.Unmet = nil
+		}
+		if len(unmet) == 0 {
+			return nil
+		}
+		if override == nil {
+			return fmt.Errorf("%w: %s", ErrRequisitesNotMet, describeUnmet(unmet))
+		}
+		if err := override.Validate(); err != nil {
+			return err
+		}
+
+		override.StudentID = studentID
+		override.CourseID = section.CourseID
+		override.SectionID = section.ID
+		for _, requisite := range unmet {
+			override.Unmet = append(override.Unmet, requisite.String())
+		}
+		return nil
+	}, override)
+}
+
+// DropSection removes a student from a section or its waitlist, promoting the next waitlisted students
+// who can still take the freed seat
+func (s *StudentServiceImpl) DropSection(ctx context.Context, studentID, sectionID uuid.UUID) error {
+	if err := s.access.canEnroll(ctx, studentID); err != nil {
 		return err
 	}
 
-	return s.studentRepo.AddCourse(ctx, studentID, courseID)
+	// Check if section exists
+	if _, err := s.sectionRepo.GetByID(ctx, sectionID); err != nil {
+		return errors.New("section not found")
+	}
+
+	return s.sectionRepo.Drop(ctx, sectionID, studentID, s.seats.promotion)
 }
 
-// DropCourse removes a student from a course
-func (s *StudentServiceImpl) DropCourse(ctx context.Context, studentID, courseID uuid.UUID) error {
-	return s.studentRepo.RemoveCourse(ctx, studentID, courseID)
+// GetStudentSections gets the sections a student is enrolled in, optionally limited to a term
+func (s *StudentServiceImpl) GetStudentSections(ctx context.Context, studentID uuid.UUID, term string) ([]models.Section, error) {
+	// Check if student exists
+	_, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	termID, err := resolveTermID(ctx, s.termService, term)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sectionRepo.FindByStudent(ctx, studentID, termID)
+}
+
+// GetStudentWaitlist gets the section waitlists a student is on and their position in each
+func (s *StudentServiceImpl) GetStudentWaitlist(ctx context.Context, studentID uuid.UUID) ([]models.WaitlistEntry, error) {
+	// Check if student exists
+	_, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sectionRepo.FindWaitlistByStudent(ctx, studentID)
 }
 
-// GetStudentCourses gets all courses for a student
-func (s *StudentServiceImpl) GetStudentCourses(ctx context.Context, studentID uuid.UUID) ([]models.Course, error) {
+// GetStudentCourses gets all courses for a student, optionally limited to a term
+func (s *StudentServiceImpl) GetStudentCourses(ctx context.Context, studentID uuid.UUID, term string) ([]models.Course, error) {
 	// Check if student exists
 	_, err := s.studentRepo.GetByID(ctx, studentID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.studentRepo.GetCourses(ctx, studentID)
+	termID, err := resolveTermID(ctx, s.termService, term)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.studentRepo.GetCourses(ctx, studentID, termID)
 }
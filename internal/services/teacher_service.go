@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"school-management-api/internal/models"
 	"school-management-api/internal/repositories"
@@ -12,43 +13,96 @@ import (
 
 // TeacherService defines the interface for teacher service
 type TeacherService interface {
-	CreateTeacher(ctx context.Context, teacher *models.Teacher) error
+	CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.ProvisionedAccount, error)
+	ProvisionAccount(ctx context.Context, id uuid.UUID, req models.AccountRequest) (*models.ProvisionedAccount, error)
 	GetTeacherByID(ctx context.Context, id uuid.UUID) (*models.TeacherResponse, error)
 	GetAllTeachers(ctx context.Context, page, pageSize int) ([]models.TeacherResponse, int64, error)
 	UpdateTeacher(ctx context.Context, teacher *models.Teacher) error
 	DeleteTeacher(ctx context.Context, id uuid.UUID) error
-	AssignCourse(ctx context.Context, teacherID, courseID uuid.UUID) error
-	RemoveCourse(ctx context.Context, teacherID, courseID uuid.UUID) error
+	AssignSection(ctx context.Context, teacherID, sectionID uuid.UUID) error
+	RemoveSection(ctx context.Context, teacherID, sectionID uuid.UUID) error
+	GetTeacherSections(ctx context.Context, teacherID uuid.UUID, term string) ([]models.Section, error)
 	GetTeacherCourses(ctx context.Context, teacherID uuid.UUID) ([]models.Course, error)
 }
 
 // TeacherServiceImpl implements the TeacherService interface
 type TeacherServiceImpl struct {
 	teacherRepo repositories.TeacherRepository
-	courseRepo  repositories.CourseRepository
+	sectionRepo repositories.SectionRepository
+	termService TermService
+	userService UserService
 }
 
 // NewTeacherService creates a new instance of TeacherServiceImpl
 func NewTeacherService(
 	teacherRepo repositories.TeacherRepository,
-	courseRepo repositories.CourseRepository,
+	sectionRepo repositories.SectionRepository,
+	termService TermService,
+	userService UserService,
 ) TeacherService {
 	return &TeacherServiceImpl{
 		teacherRepo: teacherRepo,
-		courseRepo:  courseRepo,
+		sectionRepo: sectionRepo,
+		termService: termService,
+		userService: userService,
 	}
 }
 
-// CreateTeacher creates a new teacher
-func (s *TeacherServiceImpl) CreateTeacher(ctx context.Context, teacher *models.Teacher) error {
+// CreateTeacher creates a new teacher together with their login
+func (s *TeacherServiceImpl) CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.ProvisionedAccount, error) {
 	// Check if teacher with same email already exists
 	existingTeacher, err := s.teacherRepo.FindByEmail(ctx, teacher.Email)
 	if err == nil && existingTeacher.ID != uuid.Nil {
-		return errors.New("teacher with this email already exists")
+		return nil, errors.New("teacher with this email already exists")
+	}
+
+	// A login is provisioned with the teacher unless asked not to
+	var req models.AccountRequest
+	if teacher.Account != nil {
+		req = *teacher.Account
+	}
+	if !req.Skip {
+		if err := s.userService.CheckAccount(ctx, teacherAccount(teacher), req); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create teacher
-	return s.teacherRepo.Create(ctx, teacher)
+	if err := s.teacherRepo.Create(ctx, teacher); err != nil {
+		return nil, err
+	}
+	if req.Skip {
+		return nil, nil
+	}
+	account, err := s.userService.ProvisionAccount(ctx, teacherAccount(teacher), req)
+	if err != nil {
+		return nil, fmt.Errorf("teacher created, but their login could not be provisioned: %w", err)
+	}
+	return account, nil
+}
+
+// ProvisionAccount provisions the login of an existing teacher
+func (s *TeacherServiceImpl) ProvisionAccount(ctx context.Context, id uuid.UUID, req models.AccountRequest) (*models.ProvisionedAccount, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.userService.ProvisionAccount(ctx, teacherAccount(teacher), req)
+}
+
+// teacherAccount returns the login of a teacher before it is provisioned
+func teacherAccount(teacher *models.Teacher) *models.User {
+	account := &models.User{
+		Email:     teacher.Email,
+		FirstName: teacher.FirstName,
+		LastName:  teacher.LastName,
+		Role:      "Teacher",
+	}
+	if teacher.ID != uuid.Nil {
+		id := teacher.ID
+		account.TeacherID = &id
+	}
+	return account
 }
 
 // GetTeacherByID retrieves a teacher by their ID
@@ -127,26 +181,63 @@ func (s *TeacherServiceImpl) DeleteTeacher(ctx context.Context, id uuid.UUID) er
 	return s.teacherRepo.Delete(ctx, id)
 }
 
-// AssignCourse assigns a course to a teacher
-func (s *TeacherServiceImpl) AssignCourse(ctx context.Context, teacherID, courseID uuid.UUID) error {
+// AssignSection assigns a teacher to a course section
+func (s *TeacherServiceImpl) AssignSection(ctx context.Context, teacherID, sectionID uuid.UUID) error {
 	// Check if teacher exists
 	_, err := s.teacherRepo.GetByID(ctx, teacherID)
 	if err != nil {
 		return err
 	}
 
-	// Check if course exists
-	_, err = s.courseRepo.GetByID(ctx, courseID)
+	// Check if section exists
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return errors.New("section not found")
+	}
+
+	for _, teacher := range section.Teachers {
+		if teacher.ID == teacherID {
+			return errors.New("teacher is already assigned to this section")
+		}
+	}
+
+	// Check the section fits the teacher's timetable
+	others, err := s.sectionRepo.FindByTeacher(ctx, teacherID, &section.TermID)
 	if err != nil {
 		return err
 	}
+	if err := checkConflicts(section, others, false); err != nil {
+		return err
+	}
+
+	return s.sectionRepo.AddTeacher(ctx, section, teacherID)
+}
+
+// RemoveSection removes a teacher from a course section
+func (s *TeacherServiceImpl) RemoveSection(ctx context.Context, teacherID, sectionID uuid.UUID) error {
+	// Check if section exists
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return errors.New("section not found")
+	}
 
-	return s.teacherRepo.AddCourse(ctx, teacherID, courseID)
+	return s.sectionRepo.RemoveTeacher(ctx, section, teacherID)
 }
 
-// RemoveCourse removes a course from a teacher
-func (s *TeacherServiceImpl) RemoveCourse(ctx context.Context, teacherID, courseID uuid.UUID) error {
-	return s.teacherRepo.RemoveCourse(ctx, teacherID, courseID)
+// GetTeacherSections gets the sections a teacher is assigned to, optionally limited to a term
+func (s *TeacherServiceImpl) GetTeacherSections(ctx context.Context, teacherID uuid.UUID, term string) ([]models.Section, error) {
+	// Check if teacher exists
+	_, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	termID, err := resolveTermID(ctx, s.termService, term)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sectionRepo.FindByTeacher(ctx, teacherID, termID)
 }
 
 // GetTeacherCourses gets all courses for a teacher
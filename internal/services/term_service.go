@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownTerm is returned when a term name does not match any configured term
+var ErrUnknownTerm = errors.New("unknown term")
+
+// ErrTermInUse is returned when deleting a term that grades or sections belong to, or an academic year
+// that still has terms
+var ErrTermInUse = errors.New("term is in use")
+
+// TermService defines the interface for academic year and term service
+type TermService interface {
+	CreateYear(ctx context.Context, year *models.AcademicYear) error
+	GetYearByID(ctx context.Context, id uuid.UUID) (*models.AcademicYear, error)
+	GetAllYears(ctx context.Context) ([]models.AcademicYear, error)
+	UpdateYear(ctx context.Context, year *models.AcademicYear) error
+	DeleteYear(ctx context.Context, id uuid.UUID) error
+	CreateTerm(ctx context.Context, term *models.Term) error
+	GetTermByID(ctx context.Context, id uuid.UUID) (*models.Term, error)
+	GetAllTerms(ctx context.Context) ([]models.Term, error)
+	UpdateTerm(ctx context.Context, term *models.Term) error
+	DeleteTerm(ctx context.Context, id uuid.UUID) error
+	GetCurrentTerm(ctx context.Context) (*models.Term, error)
+	SetCurrentTerm(ctx context.Context, id uuid.UUID) error
+	ResolveTerm(ctx context.Context, name string) (*models.Term, error)
+	GetCalendar(ctx context.Context, termID uuid.UUID) (*models.SchoolCalendar, error)
+	SetCalendar(ctx context.Context, calendar *models.SchoolCalendar) error
+}
+
+// TermServiceImpl implements the TermService interface
+type TermServiceImpl struct {
+	termRepo repositories.TermRepository
+}
+
+// NewTermService creates a new instance of TermServiceImpl
+func NewTermService(termRepo repositories.TermRepository) TermService {
+	return &TermServiceImpl{
+		termRepo: termRepo,
+	}
+}
+
+// CreateYear creates a new academic year
+func (s *TermServiceImpl) CreateYear(ctx context.Context, year *models.AcademicYear) error {
+	if err := year.Validate(); err != nil {
+		return err
+	}
+	return s.termRepo.CreateYear(ctx, year)
+}
+
+// GetYearByID retrieves an academic year and its terms
+func (s *TermServiceImpl) GetYearByID(ctx context.Context, id uuid.UUID) (*models.AcademicYear, error) {
+	return s.termRepo.GetYearByID(ctx, id)
+}
+
+// GetAllYears retrieves all academic years
+func (s *TermServiceImpl) GetAllYears(ctx context.Context) ([]models.AcademicYear, error) {
+	return s.termRepo.GetAllYears(ctx)
+}
+
+// UpdateYear updates an academic year
+func (s *TermServiceImpl) UpdateYear(ctx context.Context, year *models.AcademicYear) error {
+	// Check if academic year exists
+	existingYear, err := s.termRepo.GetYearByID(ctx, year.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := year.Validate(); err != nil {
+		return err
+	}
+
+	// Terms must still fit inside the new date range
+	for i := range existingYear.Terms {
+		if err := existingYear.Terms[i].Validate(year); err != nil {
+			return fmt.Errorf("term %s: %w", existingYear.Terms[i].Name, err)
+		}
+	}
+
+	year.CreatedAt = existingYear.CreatedAt
+	return s.termRepo.UpdateYear(ctx, year)
+}
+
+// DeleteYear deletes an academic year that has no terms
+func (s *TermServiceImpl) DeleteYear(ctx context.Context, id uuid.UUID) error {
+	// Check if academic year exists
+	year, err := s.termRepo.GetYearByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(year.Terms) > 0 {
+		return fmt.Errorf("%w: cannot delete an academic year that still has terms", ErrTermInUse)
+	}
+	return s.termRepo.DeleteYear(ctx, id)
+}
+
+// CreateTerm creates a new term within an academic year
+func (s *TermServiceImpl) CreateTerm(ctx context.Context, term *models.Term) error {
+	year, err := s.termRepo.GetYearByID(ctx, term.AcademicYearID)
+	if err != nil {
+		return errors.New("academic year not found")
+	}
+
+	if err := term.Validate(year); err != nil {
+		return err
+	}
+	if err := checkTermOverlap(term, year); err != nil {
+		return err
+	}
+
+	// The current flag is managed through SetCurrentTerm
+	isCurrent := term.IsCurrent
+	term.IsCurrent = false
+	if err := s.termRepo.Create(ctx, term); err != nil {
+		return err
+	}
+
+	if isCurrent {
+		if err := s.termRepo.SetCurrent(ctx, term.ID); err != nil {
+			return err
+		}
+		term.IsCurrent = true
+	}
+	return nil
+}
+
+// GetTermByID retrieves a term by its ID
+func (s *TermServiceImpl) GetTermByID(ctx context.Context, id uuid.UUID) (*models.Term, error) {
+	return s.termRepo.GetByID(ctx, id)
+}
+
+// GetAllTerms retrieves all terms
+func (s *TermServiceImpl) GetAllTerms(ctx context.Context) ([]models.Term, error) {
+	return s.termRepo.GetAll(ctx)
+}
+
+// UpdateTerm updates a term
+func (s *TermServiceImpl) UpdateTerm(ctx context.Context, term *models.Term) error {
+	// Check if term exists
+	existingTerm, err := s.termRepo.GetByID(ctx, term.ID)
+	if err != nil {
+		return err
+	}
+
+	year, err := s.termRepo.GetYearByID(ctx, term.AcademicYearID)
+	if err != nil {
+		return errors.New("academic year not found")
+	}
+
+	if err := term.Validate(year); err != nil {
+		return err
+	}
+	if err := checkTermOverlap(term, year); err != nil {
+		return err
+	}
+
+	// Grades reference terms by name, so a term cannot be renamed
+	if term.Name != existingTerm.Name {
+		return errors.New("term name cannot be changed")
+	}
+
+	term.CreatedAt = existingTerm.CreatedAt
+	term.IsCurrent = existingTerm.IsCurrent
+	return s.termRepo.Update(ctx, term)
+}
+
+// DeleteTerm deletes a term that no grade or section belongs to
+func (s *TermServiceImpl) DeleteTerm(ctx context.Context, id uuid.UUID) error {
+	// Check if term exists
+	term, err := s.termRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := s.termRepo.IsInUse(ctx, term)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return fmt.Errorf("%w: cannot delete a term that grades or sections still belong to", ErrTermInUse)
+	}
+	return s.termRepo.Delete(ctx, id)
+}
+
+// checkTermOverlap refuses a term that overlaps another term of its academic year
+func checkTermOverlap(term *models.Term, year *models.AcademicYear) error {
+	for i := range year.Terms {
+		other := &year.Terms[i]
+		if other.ID != term.ID && term.Overlaps(other) {
+			return fmt.Errorf("term overlaps %s, from %s to %s", other.Name,
+				other.StartDate.Format("2006-01-02"), other.EndDate.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// GetCurrentTerm returns the term flagged as current, or the term containing today's date
+func (s *TermServiceImpl) GetCurrentTerm(ctx context.Context) (*models.Term, error) {
+	term, err := s.termRepo.FindCurrent(ctx)
+	if err == nil {
+		return term, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	term, err = s.termRepo.FindByDate(ctx, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no current term is configured", ErrUnknownTerm)
+	}
+	return term, err
+}
+
+// SetCurrentTerm marks a term as the current term
+func (s *TermServiceImpl) SetCurrentTerm(ctx context.Context, id uuid.UUID) error {
+	// Check if term exists
+	_, err := s.termRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.termRepo.SetCurrent(ctx, id)
+}
+
+// ResolveTerm looks up a term by name, defaulting to the current term when no name is given
+func (s *TermServiceImpl) ResolveTerm(ctx context.Context, name string) (*models.Term, error) {
+	if name == "" {
+		return s.GetCurrentTerm(ctx)
+	}
+
+	term, err := s.termRepo.FindByName(ctx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTerm, name)
+	}
+	return term, err
+}
+
+// GetCalendar retrieves a term's school calendar, falling back to class every weekday when none is set
+func (s *TermServiceImpl) GetCalendar(ctx context.Context, termID uuid.UUID) (*models.SchoolCalendar, error) {
+	// Check if term exists
+	if _, err := s.termRepo.GetByID(ctx, termID); err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.termRepo.GetCalendar(ctx, termID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultSchoolCalendar(termID), nil
+	}
+	return calendar, err
+}
+
+// SetCalendar replaces a term's school calendar
+func (s *TermServiceImpl) SetCalendar(ctx context.Context, calendar *models.SchoolCalendar) error {
+	term, err := s.termRepo.GetByID(ctx, calendar.TermID)
+	if err != nil {
+		return errors.New("term not found")
+	}
+
+	for i := range calendar.Days {
+		calendar.Days[i].Date = models.DateOnly(calendar.Days[i].Date)
+	}
+	if err := calendar.Validate(term); err != nil {
+		return err
+	}
+	return s.termRepo.SaveCalendar(ctx, calendar)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// fakeTermRepository keeps terms in memory, with the terms grades or sections belong to
+type fakeTermRepository struct {
+	repositories.TermRepository
+	terms   map[uuid.UUID]*models.Term
+	inUse   map[uuid.UUID]bool
+	deleted []uuid.UUID
+}
+
+func (r *fakeTermRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Term, error) {
+	term, ok := r.terms[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return term, nil
+}
+
+func (r *fakeTermRepository) IsInUse(ctx context.Context, term *models.Term) (bool, error) {
+	return r.inUse[term.ID], nil
+}
+
+func (r *fakeTermRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func TestDeleteTermRefusesTermInUse(t *testing.T) {
+	used := &models.Term{Base: models.Base{ID: uuid.New()}, Name: "Fall 2024"}
+	unused := &models.Term{Base: models.Base{ID: uuid.New()}, Name: "Spring 2025"}
+	repo := &fakeTermRepository{
+		terms: map[uuid.UUID]*models.Term{used.ID: used, unused.ID: unused},
+		inUse: map[uuid.UUID]bool{used.ID: true},
+	}
+	service := NewTermService(repo)
+
+	if err := service.DeleteTerm(context.Background(), used.ID); !errors.Is(err, ErrTermInUse) {
+		t.Errorf("deleting a term in use: got %v, want %v", err, ErrTermInUse)
+	}
+	if err := service.DeleteTerm(context.Background(), unused.ID); err != nil {
+		t.Errorf("deleting an unused term: %v", err)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != unused.ID {
+		t.Errorf("deleted terms %v, want only %s", repo.deleted, unused.ID)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"school-management-api/internal/models"
+	"school-management-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// TimetableService defines methods for weekly student, teacher and room timetables
+type TimetableService interface {
+	GetStudentTimetable(ctx context.Context, studentID uuid.UUID, term string) (*models.Timetable, error)
+	GetTeacherTimetable(ctx context.Context, teacherID uuid.UUID, term string) (*models.Timetable, error)
+	GetRoomTimetable(ctx context.Context, roomID uuid.UUID, term string) (*models.Timetable, error)
+}
+
+// TimetableServiceImpl implements the TimetableService interface
+type TimetableServiceImpl struct {
+	sectionRepo repositories.SectionRepository
+	termService TermService
+}
+
+// NewTimetableService creates a new instance of TimetableServiceImpl
+func NewTimetableService(sectionRepo repositories.SectionRepository, termService TermService) TimetableService {
+	return &TimetableServiceImpl{
+		sectionRepo: sectionRepo,
+		termService: termService,
+	}
+}
+
+// GetStudentTimetable builds a student's weekly timetable for a term, the current term when none is given
+func (s *TimetableServiceImpl) GetStudentTimetable(ctx context.Context, studentID uuid.UUID, term string) (*models.Timetable, error) {
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.sectionRepo.FindByStudent(ctx, studentID, &t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTimetable(t, sections, nil), nil
+}
+
+// GetTeacherTimetable builds a teacher's weekly timetable for a term, the current term when none is given
+func (s *TimetableServiceImpl) GetTeacherTimetable(ctx context.Context, teacherID uuid.UUID, term string) (*models.Timetable, error) {
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.sectionRepo.FindByTeacher(ctx, teacherID, &t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTimetable(t, sections, nil), nil
+}
+
+// GetRoomTimetable builds a room's weekly timetable for a term, the current term when none is given
+func (s *TimetableServiceImpl) GetRoomTimetable(ctx context.Context, roomID uuid.UUID, term string) (*models.Timetable, error) {
+	t, err := s.termService.ResolveTerm(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.sectionRepo.FindByRoom(ctx, roomID, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTimetable(t, sections, &roomID), nil
+}
+
+// buildTimetable lists the meetings of the sections ordered by day and start time.
+// When roomID is set only meetings held in that room are included.
+func buildTimetable(term *models.Term, sections []models.Section, roomID *uuid.UUID) *models.Timetable {
+	timetable := &models.Timetable{
+		TermID:   term.ID,
+		TermName: term.Name,
+		Entries:  []models.TimetableEntry{},
+	}
+
+	for i := range sections {
+		section := &sections[i]
+		for j := range section.Meetings {
+			meeting := &section.Meetings[j]
+			meetingRoom := section.RoomFor(meeting)
+			if roomID != nil && (meetingRoom == nil || *meetingRoom != *roomID) {
+				continue
+			}
+
+			entry := models.TimetableEntry{
+				Day:         meeting.Day,
+				DayName:     meeting.Day.String(),
+				StartTime:   meeting.StartTime,
+				EndTime:     meeting.EndTime,
+				SectionID:   section.ID,
+				SectionCode: section.Code,
+				CourseID:    section.CourseID,
+				RoomID:      meetingRoom,
+				Room:        section.RoomNameFor(meeting),
+			}
+			if section.Course != nil {
+				entry.CourseCode = section.Course.Code
+				entry.CourseName = section.Course.Name
+			}
+			timetable.Entries = append(timetable.Entries, entry)
+		}
+	}
+
+	sort.Slice(timetable.Entries, func(i, j int) bool {
+		a, b := timetable.Entries[i], timetable.Entries[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		// Meeting times are validated on save, so parse errors cannot occur here
+		startA, _ := models.ParseClock(a.StartTime)
+		startB, _ := models.ParseClock(b.StartTime)
+		return startA < startB
+	})
+	return timetable
+}
@@ -2,17 +2,90 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"school-management-api/internal/authz"
+	"school-management-api/internal/mailer"
 	"school-management-api/internal/models"
 	"school-management-api/internal/repositories"
+	"school-management-api/internal/throttle"
+	"school-management-api/internal/totp"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+const (
+	// AccessTokenTTL is how long an access token is valid; clients refresh it before then
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a refresh token is valid, so how long a client stays signed in while unused
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// PasswordResetTTL is how long a password reset link is valid
+	PasswordResetTTL = time.Hour
+	// EmailVerificationTTL is how long an email verification link is valid
+	EmailVerificationTTL = 48 * time.Hour
+	// MinPasswordLength is the shortest password a user can reset theirs to
+	MinPasswordLength = 8
+	// TwoFactorChallengeTTL is how long a user has to enter their code after their password
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// RecoveryCodeCount is how many recovery codes a user is given
+	RecoveryCodeCount = 10
+	// twoFactorIssuer names the application in authenticator apps
+	twoFactorIssuer = "School Management"
+	// twoFactorAudience marks two-factor challenge tokens so they are never mistaken for access tokens
+	twoFactorAudience = "login-two-factor"
+	// LoginHistoryRetention is how long login attempts are kept in a user's history
+	LoginHistoryRetention = 90 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidToken is returned when a refresh token is unknown, expired or revoked
+	ErrInvalidToken = errors.New("invalid or expired refresh token")
+	// ErrInvalidUserToken is returned when a password reset or email verification token is unknown, expired or used
+	ErrInvalidUserToken = errors.New("invalid or expired link")
+	// ErrInvalidTwoFactorCode is returned when a two-factor code or challenge is wrong, used or expired
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrTooManyAttempts is returned, as a LoginThrottledError, when logging in is refused after too many failures
+	ErrTooManyAttempts = errors.New("too many failed login attempts")
+)
+
+// LoginThrottledError is returned when logging in is refused for a while after too many failed attempts,
+// from the account or the address tried
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // The account is locked out rather than only delayed
+}
+
+// Error describes why logging in was refused
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account is temporarily locked after too many failed login attempts"
+	}
+	return fmt.Sprintf("%s, try again in %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+// Is makes a LoginThrottledError match ErrTooManyAttempts
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// twoFactorRoles are the roles two-factor authentication can be required for
+var twoFactorRoles = []string{authz.RoleAdmin, authz.RoleTeacher, authz.RoleCounselor, authz.RoleStudent, authz.RoleGuardian}
+
 // UserService defines the interface for user service
 type UserService interface {
 	CreateUser(ctx context.Context, user *models.User) error
@@ -20,24 +93,80 @@ type UserService interface {
 	GetAllUsers(ctx context.Context, page, pageSize int) ([]models.UserResponse, int64, error)
 	UpdateUser(ctx context.Context, user *models.User) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
-	Login(ctx context.Context, username, password string) (*models.LoginResponse, error)
+	Login(ctx context.Context, username, password, ipAddress string) (*models.LoginResponse, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*models.LoginResponse, error)
+	Logout(ctx context.Context, userID uuid.UUID, tokenID string, expiresAt time.Time, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	IsTokenRevoked(ctx context.Context, userID uuid.UUID, tokenID string, issuedAt time.Time) (bool, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	SendVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	LoginTwoFactor(ctx context.Context, challengeToken, code, ipAddress string) (*models.LoginResponse, error)
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*models.TwoFactorSetup, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	ResetTwoFactor(ctx context.Context, userID uuid.UUID) error
+	GetTwoFactorPolicy(ctx context.Context) ([]models.TwoFactorPolicy, error)
+	SetTwoFactorPolicy(ctx context.Context, roles []string, updatedBy uuid.UUID) ([]models.TwoFactorPolicy, error)
+	GetLoginAttempts(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]models.LoginAttempt, int64, error)
+	GetLockout(ctx context.Context, userID uuid.UUID) (*models.LoginLockout, error)
+	UnlockUser(ctx context.Context, userID uuid.UUID) error
+	CheckAccount(ctx context.Context, account *models.User, req models.AccountRequest) error
+	ProvisionAccount(ctx context.Context, account *models.User, req models.AccountRequest) (*models.ProvisionedAccount, error)
 }
 
 // UserServiceImpl implements the UserService interface
 type UserServiceImpl struct {
-	userRepo  repositories.UserRepository
-	jwtSecret string
+	userRepo         repositories.UserRepository
+	teacherRepo      repositories.TeacherRepository
+	studentRepo      repositories.StudentRepository
+	guardianRepo     repositories.GuardianRepository
+	tokenRepo        repositories.TokenRepository
+	twoFactorRepo    repositories.TwoFactorRepository
+	loginAttemptRepo repositories.LoginAttemptRepository
+	limiter          *throttle.Limiter
+	mailer           mailer.Mailer
+	jwtSecret        string
+	challengeKey     []byte
+	appURL           string
 }
 
-// NewUserService creates a new instance of UserServiceImpl
-func NewUserService(userRepo repositories.UserRepository, jwtSecret string) UserService {
+// NewUserService creates a new instance of UserServiceImpl. Two-factor challenge tokens are signed with a key
+// derived from the JWT secret, so they cannot be used as access tokens. Logins are throttled by the limiter.
+func NewUserService(
+	userRepo repositories.UserRepository,
+	teacherRepo repositories.TeacherRepository,
+	studentRepo repositories.StudentRepository,
+	guardianRepo repositories.GuardianRepository,
+	tokenRepo repositories.TokenRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	loginAttemptRepo repositories.LoginAttemptRepository,
+	limiter *throttle.Limiter,
+	mailer mailer.Mailer,
+	jwtSecret string,
+	appURL string,
+) UserService {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(twoFactorAudience))
 	return &UserServiceImpl{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
+		userRepo:         userRepo,
+		teacherRepo:      teacherRepo,
+		studentRepo:      studentRepo,
+		guardianRepo:     guardianRepo,
+		tokenRepo:        tokenRepo,
+		twoFactorRepo:    twoFactorRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		limiter:          limiter,
+		mailer:           mailer,
+		jwtSecret:        jwtSecret,
+		challengeKey:     mac.Sum(nil),
+		appURL:           appURL,
 	}
 }
 
-// CreateUser creates a new user
+// CreateUser creates a new user and emails them a link to verify their email address
 func (s *UserServiceImpl) CreateUser(ctx context.Context, user *models.User) error {
 	// Check if user with same username or email already exists
 	existingUser, err := s.userRepo.FindByUsername(ctx, user.Username)
@@ -50,15 +179,25 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, user *models.User) err
 		return errors.New("email already registered")
 	}
 
+	if err := s.validateLinks(ctx, user); err != nil {
+		return err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
+	user.EmailVerifiedAt = nil
+	user.TwoFactorEnabledAt = nil
 
 	// Create user
-	return s.userRepo.Create(ctx, user)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+	s.sendVerification(ctx, user)
+	return nil
 }
 
 // GetUserByID retrieves a user by their ID
@@ -70,13 +209,18 @@ func (s *UserServiceImpl) GetUserByID(ctx context.Context, id uuid.UUID) (*model
 
 	// Map to response model
 	response := &models.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
+		ID:                 user.ID,
+		Username:           user.Username,
+		Email:              user.Email,
+		FirstName:          user.FirstName,
+		LastName:           user.LastName,
+		Role:               user.Role,
+		TeacherID:          user.TeacherID,
+		StudentID:          user.StudentID,
+		GuardianID:         user.GuardianID,
+		EmailVerifiedAt:    user.EmailVerifiedAt,
+		TwoFactorEnabledAt: user.TwoFactorEnabledAt,
+		CreatedAt:          user.CreatedAt,
 	}
 
 	return response, nil
@@ -93,20 +237,26 @@ func (s *UserServiceImpl) GetAllUsers(ctx context.Context, page, pageSize int) (
 	var responses []models.UserResponse
 	for _, user := range users {
 		responses = append(responses, models.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			CreatedAt: user.CreatedAt,
+			ID:                 user.ID,
+			Username:           user.Username,
+			Email:              user.Email,
+			FirstName:          user.FirstName,
+			LastName:           user.LastName,
+			Role:               user.Role,
+			TeacherID:          user.TeacherID,
+			StudentID:          user.StudentID,
+			GuardianID:         user.GuardianID,
+			EmailVerifiedAt:    user.EmailVerifiedAt,
+			TwoFactorEnabledAt: user.TwoFactorEnabledAt,
+			CreatedAt:          user.CreatedAt,
 		})
 	}
 
 	return responses, total, nil
 }
 
-// UpdateUser updates a user
+// UpdateUser updates a user, signing them out everywhere when their role or password changes. Users other
+// than admins can only update their own login, and not its role.
 func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *models.User) error {
 	// Check if user exists
 	existingUser, err := s.userRepo.GetByID(ctx, user.ID)
@@ -114,6 +264,20 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *models.User) err
 		return err
 	}
 
+	principal, err := authz.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !principal.IsAdmin() {
+		if principal.UserID != user.ID {
+			return fmt.Errorf("%w: you can only update your own login", authz.ErrForbidden)
+		}
+		if user.Role != "" && user.Role != existingUser.Role {
+			return fmt.Errorf("%w: only admins can change roles", authz.ErrForbidden)
+		}
+		user.Role = existingUser.Role
+	}
+
 	// Check if username is already taken by another user
 	if user.Username != existingUser.Username {
 		anotherUser, err := s.userRepo.FindByUsername(ctx, user.Username)
@@ -131,7 +295,9 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *models.User) err
 	}
 
 	// If password is being updated, hash it
+	revoke := user.Role != existingUser.Role
 	if user.Password != "" && user.Password != existingUser.Password {
+		revoke = true
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return err
@@ -142,10 +308,35 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *models.User) err
 		user.Password = existingUser.Password
 	}
 
-	return s.userRepo.Update(ctx, user)
+	// Logins are linked to teachers and students when they are created or provisioned
+	user.TeacherID = existingUser.TeacherID
+	user.StudentID = existingUser.StudentID
+	user.GuardianID = existingUser.GuardianID
+	user.TokensValidAfter = existingUser.TokensValidAfter
+	user.TOTPSecret = existingUser.TOTPSecret
+	user.TOTPLastCounter = existingUser.TOTPLastCounter
+	user.TwoFactorEnabledAt = existingUser.TwoFactorEnabledAt
+
+	// A new email address has to be verified again
+	user.EmailVerifiedAt = existingUser.EmailVerifiedAt
+	emailChanged := user.Email != existingUser.Email
+	if emailChanged {
+		user.EmailVerifiedAt = nil
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if emailChanged {
+		s.sendVerification(ctx, user)
+	}
+	if revoke {
+		return s.tokenRepo.RevokeUserTokens(ctx, user.ID, time.Now())
+	}
+	return nil
 }
 
-// DeleteUser deletes a user
+// DeleteUser deletes a user, revoking their tokens
 func (s *UserServiceImpl) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	// Check if user exists
 	_, err := s.userRepo.GetByID(ctx, id)
@@ -153,11 +344,25 @@ func (s *UserServiceImpl) DeleteUser(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	return s.userRepo.Delete(ctx, id)
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeUserTokens(ctx, id, time.Now())
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *UserServiceImpl) Login(ctx context.Context, username, password string) (*models.LoginResponse, error) {
+// Login authenticates a user and returns an access token and the refresh token of a new session, or, when
+// the user has two-factor authentication turned on, a challenge token to finish logging in with a code.
+// Logins are refused for a while after too many failed attempts to the username or from the IP address.
+func (s *UserServiceImpl) Login(ctx context.Context, username, password, ipAddress string) (*models.LoginResponse, error) {
+	// Refuse attempts to an account or from an address that failed too often, before checking anything
+	account := passwordAccount(username)
+	if err := s.beginLogin(ctx, account, ipAddress); err != nil {
+		if user, findErr := s.userRepo.FindByUsername(ctx, username); findErr == nil {
+			s.recordLoginAttempt(ctx, user.ID, models.LoginStepPassword, models.LoginThrottled, ipAddress)
+		}
+		return nil, err
+	}
+
 	// Find user by username
 	user, err := s.userRepo.FindByUsername(ctx, username)
 	if err != nil {
@@ -167,47 +372,883 @@ func (s *UserServiceImpl) Login(ctx context.Context, username, password string)
 	// Check password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
+		s.recordLoginAttempt(ctx, user.ID, models.LoginStepPassword, models.LoginFailed, ipAddress)
 		return nil, errors.New("invalid credentials")
 	}
 
-	// Generate JWT token
-	token, err := s.generateJWTToken(user)
+	s.recordLoginAttempt(ctx, user.ID, models.LoginStepPassword, models.LoginSucceeded, ipAddress)
+	if err := s.limiter.Succeed(ctx, account, ipAddress); err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return s.twoFactorChallenge(user)
+	}
+	s.pruneLoginAttempts(ctx)
+	return s.issueTokens(ctx, user, uuid.New())
+}
+
+// passwordAccount is the account whose password attempts a username counts against, whether or not it exists
+func passwordAccount(username string) string {
+	return "password:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// twoFactorAccount is the account whose two-factor attempts a user counts against. It is kept apart from
+// their password attempts, so that knowing the password does not reset the count of guessed codes.
+func twoFactorAccount(userID uuid.UUID) string {
+	return "two-factor:" + userID.String()
+}
+
+// beginLogin counts an attempt to log in to an account from an address, refusing it with a
+// LoginThrottledError when either failed too often
+func (s *UserServiceImpl) beginLogin(ctx context.Context, account, ipAddress string) error {
+	now := time.Now()
+	block, err := s.limiter.Begin(ctx, account, ipAddress, now)
+	if err != nil {
+		return err
+	}
+	if block != nil {
+		return &LoginThrottledError{RetryAfter: block.Until.Sub(now), Locked: block.Locked}
+	}
+	return nil
+}
+
+// recordLoginAttempt adds a login attempt to a user's history, only logging a failure to do so so that it
+// does not stop the user logging in
+func (s *UserServiceImpl) recordLoginAttempt(ctx context.Context, userID uuid.UUID, step models.LoginStep, outcome models.LoginOutcome, ipAddress string) {
+	attempt := &models.LoginAttempt{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Step:      step,
+		Outcome:   outcome,
+		IPAddress: ipAddress,
+	}
+	if err := s.loginAttemptRepo.Create(ctx, attempt); err != nil {
+		log.Printf("Failed to record login attempt of user %s: %v", userID, err)
+	}
+}
+
+// pruneLoginAttempts forgets the login history past its retention and the failed attempts that expired
+func (s *UserServiceImpl) pruneLoginAttempts(ctx context.Context) {
+	now := time.Now()
+	if err := s.loginAttemptRepo.DeleteBefore(ctx, now.Add(-LoginHistoryRetention)); err != nil {
+		log.Printf("Failed to delete old login attempts: %v", err)
+	}
+	if err := s.limiter.Prune(ctx, now); err != nil {
+		log.Printf("Failed to prune failed login attempts: %v", err)
+	}
+}
+
+// GetLoginAttempts retrieves a user's login history with pagination, latest first
+func (s *UserServiceImpl) GetLoginAttempts(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]models.LoginAttempt, int64, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	return s.loginAttemptRepo.FindByUser(ctx, userID, page, pageSize)
+}
+
+// GetLockout retrieves whether logging in to a user's account is held back after failed attempts, with
+// their password or two-factor codes
+func (s *UserServiceImpl) GetLockout(ctx context.Context, userID uuid.UUID) (*models.LoginLockout, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lockout := &models.LoginLockout{}
+	for _, account := range []string{passwordAccount(user.Username), twoFactorAccount(user.ID)} {
+		attempts, block, err := s.limiter.Status(ctx, account, now)
+		if err != nil {
+			return nil, err
+		}
+		lockout.FailedAttempts += attempts.Failures
+		if block != nil && (lockout.BlockedUntil == nil || block.Until.After(*lockout.BlockedUntil)) {
+			until := block.Until
+			lockout.BlockedUntil = &until
+			lockout.Locked = lockout.Locked || block.Locked
+		}
+	}
+	return lockout, nil
+}
+
+// UnlockUser forgets the failed login attempts of a user's account, lifting its lockout. Attempts
+// counted against the addresses they came from are left to expire.
+func (s *UserServiceImpl) UnlockUser(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.limiter.Unlock(ctx, passwordAccount(user.Username), twoFactorAccount(user.ID))
+}
+
+// twoFactorClaims are the claims of a challenge token, which proves a user gave their password
+type twoFactorClaims struct {
+	jwt.RegisteredClaims
+}
+
+// twoFactorChallenge returns the challenge a user who gave their password finishes logging in with
+func (s *UserServiceImpl) twoFactorChallenge(user *models.User) (*models.LoginResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(TwoFactorChallengeTTL)
+	claims := twoFactorClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{twoFactorAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.challengeKey)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResponse{TwoFactorRequired: true, ChallengeToken: token, ChallengeExpiresAt: &expiresAt}, nil
+}
+
+// LoginTwoFactor finishes a login with the challenge token given for the user's password and a code from
+// their authenticator app or one of their recovery codes. A challenge can only be used once.
+func (s *UserServiceImpl) LoginTwoFactor(ctx context.Context, challengeToken, code, ipAddress string) (*models.LoginResponse, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(challengeToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.challengeKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(twoFactorAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	challengeID, _ := claims["jti"].(string)
+	issuedAt, ok := authz.IssuedAt(claims)
+	expiresAt, _ := claims.GetExpirationTime()
+	subject, _ := claims.GetSubject()
+	if challengeID == "" || !ok || expiresAt == nil {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	// Codes are guessed far more easily than passwords, so their attempts are throttled too
+	account := twoFactorAccount(userID)
+	if err := s.beginLogin(ctx, account, ipAddress); err != nil {
+		s.recordLoginAttempt(ctx, userID, models.LoginStepTwoFactor, models.LoginThrottled, ipAddress)
+		return nil, err
+	}
+
+	// A challenge that was used, or that belongs to a user since signed out everywhere, is refused
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(ctx, challengeID, userID, issuedAt)
 	if err != nil {
 		return nil, err
 	}
+	if revoked {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.TwoFactorEnabledAt == nil {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.recordLoginAttempt(ctx, userID, models.LoginStepTwoFactor, models.LoginFailed, ipAddress)
+		}
+		return nil, err
+	}
+	s.recordLoginAttempt(ctx, userID, models.LoginStepTwoFactor, models.LoginSucceeded, ipAddress)
+	if err := s.limiter.Succeed(ctx, account, ipAddress); err != nil {
+		return nil, err
+	}
+	s.pruneLoginAttempts(ctx)
+
+	err = s.tokenRepo.RevokeAccessToken(ctx, &models.RevokedToken{TokenID: challengeID, UserID: userID, ExpiresAt: expiresAt.Time})
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, user, uuid.New())
+}
+
+// SetupTwoFactor starts turning on two-factor authentication for a user, generating the secret to add to
+// their authenticator app. It is turned on once they confirm a code from the app.
+func (s *UserServiceImpl) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*models.TwoFactorSetup, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, errors.New("two-factor authentication is already turned on")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	user.TOTPLastCounter = 0
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &models.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(twoFactorIssuer, user.Username, secret),
+	}, nil
+}
+
+// EnableTwoFactor turns on two-factor authentication once the user confirms a code from their authenticator
+// app, returning their recovery codes, which are shown only this once
+func (s *UserServiceImpl) EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, errors.New("two-factor authentication is already turned on")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor authentication has not been set up")
+	}
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.TwoFactorEnabledAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Tokens limited to setting up two-factor authentication are no longer needed
+	if err := s.tokenRepo.RevokeUserTokens(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor turns off a user's two-factor authentication with a current code, unless their role requires it
+func (s *UserServiceImpl) DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return errors.New("two-factor authentication is not turned on")
+	}
+	required, err := s.twoFactorRepo.IsRequired(ctx, user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return fmt.Errorf("two-factor authentication is required for the %s role", user.Role)
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+	return s.twoFactorRepo.Disable(ctx, user.ID)
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes, given a current code, returning the new ones
+func (s *UserServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return nil, errors.New("two-factor authentication is not turned on")
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// ResetTwoFactor turns off the two-factor authentication of a user who lost their authenticator app and
+// recovery codes, signing them out everywhere. Users whose role requires it set it up again when they next log in.
+func (s *UserServiceImpl) ResetTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Disable(ctx, userID); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeUserTokens(ctx, userID, time.Now())
+}
+
+// GetTwoFactorPolicy retrieves the roles required to use two-factor authentication
+func (s *UserServiceImpl) GetTwoFactorPolicy(ctx context.Context) ([]models.TwoFactorPolicy, error) {
+	return s.twoFactorRepo.GetRequiredRoles(ctx)
+}
+
+// SetTwoFactorPolicy sets the roles required to use two-factor authentication. Users of those roles who have
+// not turned it on can only set it up until they do.
+func (s *UserServiceImpl) SetTwoFactorPolicy(ctx context.Context, roles []string, updatedBy uuid.UUID) ([]models.TwoFactorPolicy, error) {
+	unique := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !slices.Contains(twoFactorRoles, role) {
+			return nil, fmt.Errorf("unknown role %q, expected one of %s", role, strings.Join(twoFactorRoles, ", "))
+		}
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+	if err := s.twoFactorRepo.SetRequiredRoles(ctx, unique, updatedBy); err != nil {
+		return nil, err
+	}
+	return s.twoFactorRepo.GetRequiredRoles(ctx)
+}
+
+// checkSecondFactor checks a code from a user's authenticator app or, failing that, one of their recovery codes
+func (s *UserServiceImpl) checkSecondFactor(ctx context.Context, user *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.checkTOTP(ctx, user, code)
+	}
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// checkTOTP checks a code from a user's authenticator app, which can only be used once
+func (s *UserServiceImpl) checkTOTP(ctx context.Context, user *models.User, code string) error {
+	counter, ok := totp.Validate(user.TOTPSecret, strings.TrimSpace(code), time.Now(), 1)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	fresh, err := s.twoFactorRepo.UseCounter(ctx, user.ID, counter)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidTwoFactorCode
+	}
+	user.TOTPLastCounter = counter
+	return nil
+}
+
+// replaceRecoveryCodes gives a user new recovery codes, returning them
+func (s *UserServiceImpl) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{ID: uuid.New(), UserID: userID, CodeHash: hashToken(normalizeRecoveryCode(code))})
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode ignores the case of a recovery code and the separators it may be typed with
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// RefreshTokens exchanges a refresh token for a new access token and refresh token. A refresh token can only
+// be used once: using it again revokes every token of its session, as it was most likely stolen.
+func (s *UserServiceImpl) RefreshTokens(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	token, err := s.tokenRepo.FindRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if token.RevokedAt != nil {
+		if token.ReplacedBy != nil {
+			log.Printf("Refresh token of user %s was reused, revoking its session", token.UserID)
+			if err := s.tokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidToken
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	response, next, err := s.generateTokens(ctx, user, token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RotateRefreshToken(ctx, token, next); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Used twice at once
+			if err := s.tokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+				return nil, err
+			}
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// Logout ends a session: the access token it was made with is denied until it expires and the refresh
+// token, when given, is revoked together with the rest of its session
+func (s *UserServiceImpl) Logout(ctx context.Context, userID uuid.UUID, tokenID string, expiresAt time.Time, refreshToken string) error {
+	if err := s.tokenRepo.RevokeAccessToken(ctx, &models.RevokedToken{TokenID: tokenID, UserID: userID, ExpiresAt: expiresAt}); err != nil {
+		return err
+	}
+	if refreshToken != "" {
+		token, err := s.tokenRepo.FindRefreshToken(ctx, hashToken(refreshToken))
+		if err == nil && token.UserID == userID {
+			if err := s.tokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Tokens that expired can no longer be used, so there is no need to keep them
+	if err := s.tokenRepo.DeleteExpired(ctx, time.Now()); err != nil {
+		log.Printf("Failed to delete expired tokens: %v", err)
+	}
+	return nil
+}
+
+// LogoutAll ends every session of a user, revoking all of their access and refresh tokens
+func (s *UserServiceImpl) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return s.tokenRepo.RevokeUserTokens(ctx, userID, time.Now())
+}
+
+// IsTokenRevoked reports whether an access token was revoked since it was issued, including by its user
+// logging out everywhere, changing role or password, or being deleted
+func (s *UserServiceImpl) IsTokenRevoked(ctx context.Context, userID uuid.UUID, tokenID string, issuedAt time.Time) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(ctx, tokenID, userID, issuedAt)
+}
+
+// issueTokens issues an access token and a refresh token of a session to a user
+func (s *UserServiceImpl) issueTokens(ctx context.Context, user *models.User, familyID uuid.UUID) (*models.LoginResponse, error) {
+	response, refreshToken, err := s.generateTokens(ctx, user, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.CreateRefreshToken(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// generateTokens generates an access token and a refresh token of a session for a user, returning the
+// refresh token to store. Users whose role requires two-factor authentication and who have not turned it
+// on are given an access token that only allows setting it up.
+func (s *UserServiceImpl) generateTokens(ctx context.Context, user *models.User, familyID uuid.UUID) (*models.LoginResponse, *models.RefreshToken, error) {
+	scope := ""
+	if user.TwoFactorEnabledAt == nil {
+		required, err := s.twoFactorRepo.IsRequired(ctx, user.Role)
+		if err != nil {
+			return nil, nil, err
+		}
+		if required {
+			scope = authz.ScopeTwoFactorSetup
+		}
+	}
+
+	now := time.Now()
+	token, expiresAt, err := s.generateJWTToken(user, now, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	refreshToken := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(secret),
+		ExpiresAt: now.Add(RefreshTokenTTL),
+	}
 
 	// Map to response model
 	response := &models.LoginResponse{
-		Token: token,
-		User: models.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
-			CreatedAt: user.CreatedAt,
+		Token:                  token,
+		ExpiresAt:              &expiresAt,
+		RefreshToken:           secret,
+		RefreshExpiresAt:       &refreshToken.ExpiresAt,
+		TwoFactorSetupRequired: scope == authz.ScopeTwoFactorSetup,
+		User: &models.UserResponse{
+			ID:                 user.ID,
+			Username:           user.Username,
+			Email:              user.Email,
+			FirstName:          user.FirstName,
+			LastName:           user.LastName,
+			Role:               user.Role,
+			TeacherID:          user.TeacherID,
+			StudentID:          user.StudentID,
+			GuardianID:         user.GuardianID,
+			EmailVerifiedAt:    user.EmailVerifiedAt,
+			TwoFactorEnabledAt: user.TwoFactorEnabledAt,
+			CreatedAt:          user.CreatedAt,
 		},
 	}
 
-	return response, nil
+	return response, refreshToken, nil
 }
 
-// generateJWTToken generates a JWT token for the user
-func (s *UserServiceImpl) generateJWTToken(user *models.User) (string, error) {
+// randomToken returns a new random token to give a user
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the hash a refresh or user token is stored by
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateJWTToken generates an access token for the user issued at a time, limited to a scope unless it is
+// empty, returning when it expires
+func (s *UserServiceImpl) generateJWTToken(user *models.User, issuedAt time.Time, scope string) (string, time.Time, error) {
 	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	expiresAt := issuedAt.Add(AccessTokenTTL)
+	claims := jwt.MapClaims{
+		"jti":  uuid.New().String(),
 		"sub":  user.ID.String(),
 		"name": user.Username,
 		"role": user.Role,
-		"exp":  time.Now().Add(time.Hour * 24).Unix(), // Token expires after 24 hours
-	})
+		"iat":  jwt.NewNumericDate(issuedAt),
+		"exp":  expiresAt.Unix(),
+	}
+	// Carry the teacher or student the user is the login of
+	if user.TeacherID != nil {
+		claims["teacher_id"] = user.TeacherID.String()
+	}
+	if user.StudentID != nil {
+		claims["student_id"] = user.StudentID.String()
+	}
+	if user.GuardianID != nil {
+		claims["guardian_id"] = user.GuardianID.String()
+	}
+	if scope != "" {
+		claims["scope"] = scope
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign token with secret key
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// validateLinks checks that a user is the login of at most one existing teacher, student or guardian
+// that has no other login
+func (s *UserServiceImpl) validateLinks(ctx context.Context, user *models.User) error {
+	links := 0
+	for _, id := range []*uuid.UUID{user.TeacherID, user.StudentID, user.GuardianID} {
+		if id != nil {
+			links++
+		}
+	}
+	if links > 1 {
+		return errors.New("a user can only be the login of one teacher, student or guardian")
+	}
+	if user.TeacherID != nil {
+		if _, err := s.teacherRepo.GetByID(ctx, *user.TeacherID); err != nil {
+			return errors.New("teacher not found")
+		}
+		if linked, err := s.userRepo.FindByTeacher(ctx, *user.TeacherID); err == nil && linked.ID != user.ID {
+			return errors.New("teacher already has a login")
+		}
+	}
+	if user.StudentID != nil {
+		if _, err := s.studentRepo.GetByID(ctx, *user.StudentID); err != nil {
+			return errors.New("student not found")
+		}
+		if linked, err := s.userRepo.FindByStudent(ctx, *user.StudentID); err == nil && linked.ID != user.ID {
+			return errors.New("student already has a login")
+		}
+	}
+	if user.GuardianID != nil {
+		if _, err := s.guardianRepo.GetByID(ctx, *user.GuardianID); err != nil {
+			return errors.New("guardian not found")
+		}
+		if linked, err := s.userRepo.FindByGuardian(ctx, *user.GuardianID); err == nil && linked.ID != user.ID {
+			return errors.New("guardian already has a login")
+		}
+	}
+	return nil
+}
+
+// CheckAccount checks that a login can be provisioned for a person before their record is created
+func (s *UserServiceImpl) CheckAccount(ctx context.Context, account *models.User, req models.AccountRequest) error {
+	_, err := s.planAccount(ctx, account, req)
+	return err
+}
+
+// ProvisionAccount creates the login of a teacher, student or guardian, given as a user with their name, email,
+// role and link. An existing login with the same email and role that is not linked to anyone is linked instead.
+func (s *UserServiceImpl) ProvisionAccount(ctx context.Context, account *models.User, req models.AccountRequest) (*models.ProvisionedAccount, error) {
+	existing, err := s.planAccount(ctx, account, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateLinks(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		existing.TeacherID = account.TeacherID
+		existing.StudentID = account.StudentID
+		existing.GuardianID = account.GuardianID
+		if err := s.userRepo.Update(ctx, existing); err != nil {
+			return nil, err
+		}
+		response, err := s.GetUserByID(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.ProvisionedAccount{User: *response, Linked: true}, nil
+	}
+
+	provisioned := &models.ProvisionedAccount{}
+	password := req.Password
+	if password == "" {
+		password, err = generatePassword()
+		if err != nil {
+			return nil, err
+		}
+		provisioned.TemporaryPassword = password
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Username = req.Username
+	if account.Username == "" {
+		account.Username = account.Email
+	}
+	account.Password = string(hashedPassword)
+	if err := s.userRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+	s.sendVerification(ctx, account)
+
+	response, err := s.GetUserByID(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	provisioned.User = *response
+	return provisioned, nil
+}
+
+// planAccount checks a login can be provisioned, returning the existing login to link when there is one
+func (s *UserServiceImpl) planAccount(ctx context.Context, account *models.User, req models.AccountRequest) (*models.User, error) {
+	if account.Email == "" {
+		return nil, errors.New("an email address is required to provision a login")
+	}
+
+	existing, err := s.userRepo.FindByEmail(ctx, account.Email)
+	if err == nil && existing.ID != uuid.Nil {
+		if existing.TeacherID != nil || existing.StudentID != nil || existing.GuardianID != nil {
+			return nil, errors.New("the login with this email is already linked to someone else")
+		}
+		// Linking an admin's or counselor's login would hand its privileges to the person
+		if existing.Role != account.Role {
+			return nil, fmt.Errorf("the login with this email belongs to a %s, not a %s", existing.Role, account.Role)
+		}
+		return existing, nil
+	}
+
+	username := req.Username
+	if username == "" {
+		username = account.Email
+	}
+	taken, err := s.userRepo.FindByUsername(ctx, username)
+	if err == nil && taken.ID != uuid.Nil {
+		return nil, errors.New("username already taken")
+	}
+	return nil, nil
+}
+
+// ForgotPassword emails a link to reset their password to the user with an email address. Nothing is
+// sent, and no error returned, when there is no such user, so the response does not reveal who has a login.
+func (s *UserServiceImpl) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the latest link works
+	if err := s.tokenRepo.DeleteUserTokens(ctx, user.ID, models.PurposePasswordReset); err != nil {
+		return err
+	}
+	token, err := s.createUserToken(ctx, user, models.PurposePasswordReset, PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+	// A failure to deliver is only logged, as telling the caller would reveal that the user exists
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nSomeone asked to reset the password of your login %s. To choose a new password, open:\n\n%s\n\n"+
+			"The link works once and expires in %s. If you did not ask for it, you can ignore this email.\n",
+			user.FirstName, user.Username, s.link("/reset-password", token), PasswordResetTTL),
+	})
+	if err != nil {
+		log.Printf("Failed to send password reset to user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword sets a user's password with a password reset token, signing them out everywhere. Receiving
+// the link also proves the user's email address.
+func (s *UserServiceImpl) ResetPassword(ctx context.Context, token, password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+
+	userToken, err := s.tokenRepo.UseUserToken(ctx, hashToken(token), models.PurposePasswordReset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+	user, err := s.userRepo.GetByID(ctx, userToken.UserID)
+	if err != nil {
+		return ErrInvalidUserToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+	if user.EmailVerifiedAt == nil && user.Email == userToken.Email {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.DeleteUserTokens(ctx, user.ID, models.PurposePasswordReset); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeUserTokens(ctx, user.ID, time.Now())
+}
+
+// SendVerification emails a user a new link to verify their email address
+func (s *UserServiceImpl) SendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email address is already verified")
+	}
+	return s.mailVerification(ctx, user)
+}
+
+// VerifyEmail marks the email address a verification token was sent to as verified, unless the user has
+// changed it since
+func (s *UserServiceImpl) VerifyEmail(ctx context.Context, token string) error {
+	userToken, err := s.tokenRepo.UseUserToken(ctx, hashToken(token), models.PurposeEmailVerification)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+	user, err := s.userRepo.GetByID(ctx, userToken.UserID)
+	if err != nil || user.Email != userToken.Email {
+		return ErrInvalidUserToken
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+	}
+	return s.tokenRepo.DeleteUserTokens(ctx, user.ID, models.PurposeEmailVerification)
+}
+
+// sendVerification emails a new user, or one whose address changed, a link to verify their email address.
+// Failing to send it does not undo the change; the user can ask for another link.
+func (s *UserServiceImpl) sendVerification(ctx context.Context, user *models.User) {
+	if user.Email == "" {
+		return
+	}
+	if err := s.mailVerification(ctx, user); err != nil {
+		log.Printf("Failed to send email verification to user %s: %v", user.ID, err)
+	}
+}
+
+// mailVerification replaces a user's email verification links with a new one and emails it to them
+func (s *UserServiceImpl) mailVerification(ctx context.Context, user *models.User) error {
+	if err := s.tokenRepo.DeleteUserTokens(ctx, user.ID, models.PurposeEmailVerification); err != nil {
+		return err
+	}
+	token, err := s.createUserToken(ctx, user, models.PurposeEmailVerification, EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease verify the email address of your login %s by opening:\n\n%s\n\nThe link expires in %s.\n",
+			user.FirstName, user.Username, s.link("/verify-email", token), EmailVerificationTTL),
+	})
+}
+
+// createUserToken issues a single-use token for a purpose to a user, returning the token to send them
+func (s *UserServiceImpl) createUserToken(ctx context.Context, user *models.User, purpose models.TokenPurpose, ttl time.Duration) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	err = s.tokenRepo.CreateUserToken(ctx, &models.UserToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	})
 	if err != nil {
 		return "", err
 	}
+	return token, nil
+}
+
+// link returns the link to a page of the application carrying a token
+func (s *UserServiceImpl) link(path, token string) string {
+	return s.appURL + path + "?token=" + url.QueryEscape(token)
+}
 
-	return tokenString, nil
+// generatePassword returns a random temporary password
+func generatePassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
 }
@@ -0,0 +1,336 @@
+// Package throttle slows down repeated failed logins, by the account tried and the address they come from.
+//
+// Every attempt counts as a failure when it starts and is forgiven when it succeeds, so attempts made at
+// the same time cannot slip past the count. After a few failures each further attempt has to wait for a
+// delay that doubles with every failure, and after many an account is locked out for a while.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"school-management-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Policy is how failed attempts of a key are held back
+type Policy struct {
+	FreeAttempts    int           // Failures allowed before attempts are delayed
+	BaseDelay       time.Duration // Delay after the first failure over FreeAttempts, doubling with each one after
+	MaxDelay        time.Duration // Longest delay before the key is locked out
+	LockoutAttempts int           // Failures at which the key is locked out, or 0 never to lock it out
+	LockoutDuration time.Duration // How long a locked-out key waits after each failure
+	ResetAfter      time.Duration // Failures are forgotten this long after the last attempt
+}
+
+// Attempts are the failed attempts of a key
+type Attempts struct {
+	Failures      int
+	LastAttemptAt time.Time
+	ExpiresAt     time.Time
+}
+
+// DefaultAccountPolicy delays logins to an account after 3 failures, from a second up to 5 minutes, and
+// locks it out for 15 minutes after 10, until a day passes without an attempt
+var DefaultAccountPolicy = Policy{
+	FreeAttempts:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        5 * time.Minute,
+	LockoutAttempts: 10,
+	LockoutDuration: 15 * time.Minute,
+	ResetAfter:      24 * time.Hour,
+}
+
+// DefaultAddressPolicy delays logins from an address after 20 failures, from a second up to a minute, until
+// an hour passes without an attempt. Addresses are never locked out, as a whole school can share one.
+var DefaultAddressPolicy = Policy{
+	FreeAttempts: 20,
+	BaseDelay:    time.Second,
+	MaxDelay:     time.Minute,
+	ResetAfter:   time.Hour,
+}
+
+// Block is why an attempt was refused
+type Block struct {
+	Until  time.Time
+	Locked bool // The failures reached the policy's lockout rather than only delaying attempts
+}
+
+// Block returns what holds back the next attempt of a key with these failures, or nil when it may be made now
+func (p Policy) Block(attempts Attempts, now time.Time) *Block {
+	if attempts.Failures == 0 || !now.Before(attempts.ExpiresAt) {
+		return nil
+	}
+
+	block := Block{}
+	if p.LockoutAttempts > 0 && attempts.Failures >= p.LockoutAttempts {
+		block.Until = attempts.LastAttemptAt.Add(p.LockoutDuration)
+		block.Locked = true
+	} else if attempts.Failures <= p.FreeAttempts {
+		return nil
+	} else {
+		delay := p.MaxDelay
+		if over := attempts.Failures - p.FreeAttempts - 1; over < 30 && p.BaseDelay<<over < p.MaxDelay {
+			delay = p.BaseDelay << over
+		}
+		block.Until = attempts.LastAttemptAt.Add(delay)
+	}
+	if !now.Before(block.Until) {
+		return nil
+	}
+	return &block
+}
+
+// Store keeps the failed attempts of keys
+type Store interface {
+	// Attempt counts an attempt of a key as a failure, unless the policy holds it back, in which case the
+	// attempt is not counted and the block is returned
+	Attempt(ctx context.Context, key string, policy Policy, now time.Time) (*Block, error)
+	// Forgive takes back one failure of a key, for an attempt that succeeded
+	Forgive(ctx context.Context, key string) error
+	// Reset forgets every failure of a key
+	Reset(ctx context.Context, key string) error
+	// Get retrieves the failures of a key, which are zero once they are forgotten
+	Get(ctx context.Context, key string, now time.Time) (Attempts, error)
+	// Prune removes the keys whose failures were forgotten before a time
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// New creates the store of a kind: "memory" keeps attempts in this process, for a single replica, and
+// "postgres" keeps them in the database, shared by every replica
+func New(kind string, db *gorm.DB) (Store, error) {
+	switch kind {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	}
+	return nil, fmt.Errorf("unknown login throttle store %q, expected memory or postgres", kind)
+}
+
+// MemoryStore keeps attempts in memory
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]Attempts
+}
+
+// NewMemoryStore creates a new MemoryStore
+func NewMemoryStore() Store {
+	return &MemoryStore{attempts: make(map[string]Attempts)}
+}
+
+// Attempt counts an attempt of a key unless the policy holds it back
+func (s *MemoryStore) Attempt(ctx context.Context, key string, policy Policy, now time.Time) (*Block, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts := s.attempts[key]
+	if !now.Before(attempts.ExpiresAt) {
+		attempts = Attempts{}
+	}
+	if block := policy.Block(attempts, now); block != nil {
+		return block, nil
+	}
+	s.attempts[key] = Attempts{Failures: attempts.Failures + 1, LastAttemptAt: now, ExpiresAt: now.Add(policy.ResetAfter)}
+	return nil, nil
+}
+
+// Forgive takes back one failure of a key
+func (s *MemoryStore) Forgive(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempts, ok := s.attempts[key]; ok && attempts.Failures > 0 {
+		attempts.Failures--
+		s.attempts[key] = attempts
+	}
+	return nil
+}
+
+// Reset forgets every failure of a key
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// Get retrieves the failures of a key
+func (s *MemoryStore) Get(ctx context.Context, key string, now time.Time) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts := s.attempts[key]
+	if !now.Before(attempts.ExpiresAt) {
+		return Attempts{}, nil
+	}
+	return attempts, nil
+}
+
+// Prune removes the keys whose failures were forgotten before a time
+func (s *MemoryStore) Prune(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, attempts := range s.attempts {
+		if attempts.ExpiresAt.Before(before) {
+			delete(s.attempts, key)
+		}
+	}
+	return nil
+}
+
+// PostgresStore keeps attempts in the login_throttles table
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a new PostgresStore
+func NewPostgresStore(db *gorm.DB) Store {
+	return &PostgresStore{db: db}
+}
+
+// Attempt counts an attempt of a key unless the policy holds it back, locking the key's row so that
+// replicas counting attempts of it at the same time wait for each other
+func (s *PostgresStore) Attempt(ctx context.Context, key string, policy Policy, now time.Time) (*Block, error) {
+	var block *Block
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := models.LoginThrottle{Key: key, LastAttemptAt: now, ExpiresAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		attempts := Attempts{Failures: row.Failures, LastAttemptAt: row.LastAttemptAt, ExpiresAt: row.ExpiresAt}
+		if !now.Before(attempts.ExpiresAt) {
+			attempts = Attempts{}
+		}
+		if block = policy.Block(attempts, now); block != nil {
+			return nil
+		}
+		return tx.Model(&models.LoginThrottle{}).Where("key = ?", key).Updates(map[string]interface{}{
+			"failures":        attempts.Failures + 1,
+			"last_attempt_at": now,
+			"expires_at":      now.Add(policy.ResetAfter),
+		}).Error
+	})
+	return block, err
+}
+
+// Forgive takes back one failure of a key
+func (s *PostgresStore) Forgive(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Model(&models.LoginThrottle{}).
+		Where("key = ? AND failures > 0", key).
+		UpdateColumn("failures", gorm.Expr("failures - 1")).Error
+}
+
+// Reset forgets every failure of a key
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&models.LoginThrottle{}).Error
+}
+
+// Get retrieves the failures of a key
+func (s *PostgresStore) Get(ctx context.Context, key string, now time.Time) (Attempts, error) {
+	var rows []models.LoginThrottle
+	err := s.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, now).Limit(1).Find(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return Attempts{}, err
+	}
+	return Attempts{Failures: rows[0].Failures, LastAttemptAt: rows[0].LastAttemptAt, ExpiresAt: rows[0].ExpiresAt}, nil
+}
+
+// Prune removes the keys whose failures were forgotten before a time
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) error {
+	return s.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.LoginThrottle{}).Error
+}
+
+// Limiter throttles logins by the account tried, with one policy, and the address they come from, with another
+type Limiter struct {
+	store   Store
+	account Policy
+	address Policy
+}
+
+// NewLimiter creates a new Limiter
+func NewLimiter(store Store, account, address Policy) *Limiter {
+	return &Limiter{
+		store:   store,
+		account: account,
+		address: address,
+	}
+}
+
+// Begin counts an attempt to log in to an account from an address, returning the block that refuses it
+// when either has failed too often. An empty address is not throttled.
+func (l *Limiter) Begin(ctx context.Context, account, address string, now time.Time) (*Block, error) {
+	if address != "" {
+		block, err := l.store.Attempt(ctx, addressKey(address), l.address, now)
+		if err != nil || block != nil {
+			return block, err
+		}
+	}
+	block, err := l.store.Attempt(ctx, accountKey(account), l.account, now)
+	if err != nil || block == nil {
+		return block, err
+	}
+
+	// The attempt was refused, so it does not count against the address either
+	if address != "" {
+		if err := l.store.Forgive(ctx, addressKey(address)); err != nil {
+			return nil, err
+		}
+	}
+	return block, nil
+}
+
+// Succeed forgets the failures of an account that was logged in to and forgives the attempt of the address
+func (l *Limiter) Succeed(ctx context.Context, account, address string) error {
+	if err := l.store.Reset(ctx, accountKey(account)); err != nil {
+		return err
+	}
+	if address != "" {
+		return l.store.Forgive(ctx, addressKey(address))
+	}
+	return nil
+}
+
+// Unlock forgets the failures of accounts, lifting their lockout
+func (l *Limiter) Unlock(ctx context.Context, accounts ...string) error {
+	for _, account := range accounts {
+		if err := l.store.Reset(ctx, accountKey(account)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status retrieves the failures of an account and what holds back its next attempt, if anything
+func (l *Limiter) Status(ctx context.Context, account string, now time.Time) (Attempts, *Block, error) {
+	attempts, err := l.store.Get(ctx, accountKey(account), now)
+	if err != nil {
+		return Attempts{}, nil, err
+	}
+	return attempts, l.account.Block(attempts, now), nil
+}
+
+// Prune forgets the failures that expired before a time
+func (l *Limiter) Prune(ctx context.Context, before time.Time) error {
+	return l.store.Prune(ctx, before)
+}
+
+// accountKey is the key of an account's attempts
+func accountKey(account string) string {
+	return "account:" + account
+}
+
+// addressKey is the key of an address's attempts
+func addressKey(address string) string {
+	return "address:" + address
+}
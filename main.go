@@ -13,13 +13,21 @@ import (
 	"school-management-api/api/controllers"
 	"school-management-api/api/routes"
 	"school-management-api/config"
+	"school-management-api/internal/mailer"
+	"school-management-api/internal/notifications"
 	"school-management-api/internal/repositories"
 	"school-management-api/internal/services"
+	"school-management-api/internal/throttle"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func main() {
+	// Token issue times are compared with when a user's tokens were revoked, so they are kept to the
+	// millisecond rather than the default whole second
+	jwt.TimePrecision = time.Millisecond
+
 	// Load configuration
 	appConfig, err := config.LoadConfig()
 	if err != nil {
@@ -48,22 +56,86 @@ func main() {
 	userRepo := repositories.NewUserRepository(db)
 	gradeRepo := repositories.NewGradeRepository(db)
 	attendanceRepo := repositories.NewAttendanceRepository(db)
+	gradingScaleRepo := repositories.NewGradingScaleRepository(db)
+	assessmentRepo := repositories.NewAssessmentRepository(db)
+	termRepo := repositories.NewTermRepository(db)
+	sectionRepo := repositories.NewSectionRepository(db)
+	requisiteRepo := repositories.NewRequisiteRepository(db)
+	scheduleRepo := repositories.NewScheduleRepository(db)
+	roomRepo := repositories.NewRoomRepository(db)
+	alertRepo := repositories.NewAlertRepository(db)
+	excuseRepo := repositories.NewExcuseRepository(db)
+	checkInRepo := repositories.NewCheckInRepository(db)
+	analyticsRepo := repositories.NewAnalyticsRepository(db)
+	guardianRepo := repositories.NewGuardianRepository(db)
+	tokenRepo := repositories.NewTokenRepository(db)
+	twoFactorRepo := repositories.NewTwoFactorRepository(db)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(db)
+
+	// Set up login throttling, in the database when replicas must share it
+	throttleStore, err := throttle.New(appConfig.LoginThrottleStore, db)
+	if err != nil {
+		log.Fatalf("Failed to set up login throttling: %v", err)
+	}
+	accountPolicy := throttle.DefaultAccountPolicy
+	accountPolicy.LockoutAttempts = appConfig.LoginLockoutAttempts
+	accountPolicy.LockoutDuration = time.Duration(appConfig.LoginLockoutMinutes) * time.Minute
+	limiter := throttle.NewLimiter(throttleStore, accountPolicy, throttle.DefaultAddressPolicy)
+
+	// Set up notifications
+	notifier := notifications.NewLogNotifier()
+	mail, err := mailer.New(appConfig.Mailer, appConfig.MailFrom, appConfig.MailDir)
+	if err != nil {
+		log.Fatalf("Failed to set up mailer: %v", err)
+	}
 
 	// Set up services
-	studentService := services.NewStudentService(studentRepo, courseRepo)
-	teacherService := services.NewTeacherService(teacherRepo, courseRepo)
+	termService := services.NewTermService(termRepo)
+	gradingScaleService := services.NewGradingScaleService(gradingScaleRepo, courseRepo)
+	requisiteService := services.NewRequisiteService(requisiteRepo, courseRepo, studentRepo, gradeRepo, gradingScaleService)
+	userService := services.NewUserService(userRepo, teacherRepo, studentRepo, guardianRepo, tokenRepo, twoFactorRepo, loginAttemptRepo, limiter, mail, appConfig.JWTSecret, appConfig.AppURL)
+	studentService := services.NewStudentService(studentRepo, sectionRepo, termService, requisiteService, userService, guardianRepo)
+	teacherService := services.NewTeacherService(teacherRepo, sectionRepo, termService, userService)
+	guardianService := services.NewGuardianService(guardianRepo, studentRepo, userService)
 	courseService := services.NewCourseService(courseRepo)
-	userService := services.NewUserService(userRepo, appConfig.JWTSecret)
-	gradeService := services.NewGradeService(gradeRepo)
-	attendanceService := services.NewAttendanceService(attendanceRepo)
+	sectionService := services.NewSectionService(sectionRepo, courseRepo, roomRepo, termService, requisiteService)
+	gradeService := services.NewGradeService(gradeRepo, teacherRepo, guardianRepo, gradingScaleService, termService)
+	gpaService := services.NewGPAService(gradeRepo, teacherRepo, guardianRepo, gradingScaleService, appConfig.GPARetakePolicy)
+	assessmentService := services.NewAssessmentService(assessmentRepo, courseRepo, teacherRepo, guardianRepo, gradeService, gradingScaleService, termService)
+	alertService := services.NewAlertService(alertRepo, studentRepo, userRepo, termService, notifier)
+	attendanceService := services.NewAttendanceService(attendanceRepo, courseRepo, sectionRepo, excuseRepo, teacherRepo, guardianRepo, termService, alertService)
+	checkInService := services.NewCheckInService(checkInRepo, sectionRepo, studentRepo, teacherRepo, attendanceService, appConfig.JWTSecret)
+	analyticsService := services.NewAnalyticsService(analyticsRepo)
+	timetableService := services.NewTimetableService(sectionRepo, termService)
+	excuseService := services.NewExcuseService(excuseRepo, studentRepo, guardianRepo, appConfig.UploadDir)
+	roomService := services.NewRoomService(roomRepo, sectionRepo, termService)
+	scheduleService := services.NewScheduleService(scheduleRepo, sectionRepo, roomRepo, courseRepo, studentRepo, teacherRepo, termService)
+
+	// Timetable runs solve in the background, so any left unfinished by the last shutdown are abandoned
+	if err := scheduleService.RecoverInterruptedRuns(context.Background()); err != nil {
+		log.Printf("Failed to recover interrupted timetable runs: %v", err)
+	}
 
 	// Set up controllers
 	studentController := controllers.NewStudentController(studentService)
 	teacherController := controllers.NewTeacherController(teacherService)
 	courseController := controllers.NewCourseController(courseService)
 	userController := controllers.NewUserController(userService)
-	gradeController := controllers.NewGradeController(gradeService)
+	gradeController := controllers.NewGradeController(gradeService, gpaService)
 	attendanceController := controllers.NewAttendanceController(attendanceService)
+	gradingScaleController := controllers.NewGradingScaleController(gradingScaleService)
+	assessmentController := controllers.NewAssessmentController(assessmentService)
+	termController := controllers.NewTermController(termService)
+	sectionController := controllers.NewSectionController(sectionService)
+	requisiteController := controllers.NewRequisiteController(requisiteService)
+	timetableController := controllers.NewTimetableController(timetableService)
+	scheduleController := controllers.NewScheduleController(scheduleService)
+	roomController := controllers.NewRoomController(roomService)
+	alertController := controllers.NewAlertController(alertService)
+	excuseController := controllers.NewExcuseController(excuseService)
+	checkInController := controllers.NewCheckInController(checkInService)
+	analyticsController := controllers.NewAnalyticsController(analyticsService)
+	guardianController := controllers.NewGuardianController(guardianService, gradeService, attendanceService, timetableService)
 
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "release" {
@@ -77,9 +149,28 @@ func main() {
 		userController,
 		gradeController,
 		attendanceController,
+		gradingScaleController,
+		assessmentController,
+		termController,
+		sectionController,
+		requisiteController,
+		timetableController,
+		scheduleController,
+		roomController,
+		alertController,
+		excuseController,
+		checkInController,
+		analyticsController,
+		guardianController,
 		appConfig.JWTSecret,
+		userService,
 	)
 
+	// Logins are throttled by address, so only trusted proxies may report the address a request came from
+	if err := router.SetTrustedProxies(appConfig.TrustedProxies); err != nil {
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+
 	// Create HTTP server
 	addr := fmt.Sprintf(":%d", appConfig.Port)
 	server := &http.Server{